@@ -2,23 +2,35 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
     "time"
 
 	"github.com/amarantec/move-easy/internal/utils"
 	"github.com/amarantec/move-easy/internal/db"
 	"github.com/amarantec/move-easy/internal/handlers/routes"
+	"github.com/amarantec/move-easy/internal/location"
 	"github.com/amarantec/move-easy/internal/middleware"
+	"github.com/amarantec/move-easy/internal/occurrence"
+	"github.com/amarantec/move-easy/internal/sharedVehicle"
 )
 
+const SHUTDOWN_TIMEOUT = 30 * time.Second
+
 func main() {
 	utils.LoadEnv()
 	setupLogger()
 
+	if err := utils.LoadJWTKeys(); err != nil {
+		log.Fatal(err)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10 * time.Second)
     defer cancel()
 
@@ -34,7 +46,26 @@ func main() {
 
 	defer Conn.Close()
 
-	mux := routes.SetRoutes(Conn)
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	occurrenceService := occurrence.NewOccurrenceService(occurrence.NewOccurrenceRepository(Conn), nil, nil)
+	go occurrence.StartExpiryJob(jobsCtx, occurrenceService,
+		utils.GetEnvDuration("OCCURRENCE_EXPIRY_MAX_AGE", 2*time.Hour),
+		utils.GetEnvDuration("OCCURRENCE_EXPIRY_INTERVAL", 5*time.Minute))
+
+	sharedVehicleService := sharedVehicle.NewSharedVehicleService(sharedVehicle.NewSharedVehicleRepository(Conn),
+		utils.GetEnvDuration("SHARED_VEHICLE_FRESHNESS", 30*time.Minute), nil)
+	go sharedVehicle.StartExpiryJob(jobsCtx, sharedVehicleService,
+		utils.GetEnvDuration("SHARED_VEHICLE_RETENTION", 24*time.Hour),
+		utils.GetEnvDuration("SHARED_VEHICLE_EXPIRY_INTERVAL", 15*time.Minute))
+
+	locationService := location.NewLocationService(location.NewLocationRepository(Conn), nil)
+	go location.StartPruneJob(jobsCtx, locationService,
+		utils.GetEnvDuration("USER_LOCATION_RETENTION", time.Hour),
+		utils.GetEnvDuration("USER_LOCATION_PRUNE_INTERVAL", 10*time.Minute))
+
+	mux, waitBackground := routes.SetRoutes(Conn)
 	loggedMux := middleware.LoggerMiddleware(mux)
 
 	server := &http.Server{
@@ -42,8 +73,25 @@ func main() {
 		Handler: loggedMux,
 	}
 
-	fmt.Printf("Server listen on: http://localhost%s\n", server.Addr)
-	log.Fatal(server.ListenAndServe())
+	go func() {
+		fmt.Printf("Server listen on: http://localhost%s\n", server.Addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	<-signalCtx.Done()
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), SHUTDOWN_TIMEOUT)
+	defer cancelShutdown()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("could not shut the server down, error: %v", err)
+	}
+
+	waitBackground()
 }
 
 func setupLogger() {
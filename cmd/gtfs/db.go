@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/amarantec/move-easy/internal/db"
+	"github.com/amarantec/move-easy/internal/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func connect() *pgxpool.Pool {
+	utils.LoadEnv()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	connectionString, err := utils.BuildConnectionString()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	Conn, err := db.OpenConnection(ctx, connectionString)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return Conn
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/amarantec/move-easy/internal/gtfs"
+)
+
+const usage = `usage:
+  gtfs import -file <feed.zip>
+  gtfs export -file <feed.zip>`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "import":
+		importFeed(os.Args[2:])
+	case "export":
+		exportFeed(os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func importFeed(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	file := flags.String("file", "", "path of the GTFS zip archive")
+	timeout := flags.Duration("timeout", 30*time.Minute, "maximum duration of the import")
+	flags.Parse(args)
+
+	if *file == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	feed, err := gtfs.ReadFeed(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	Conn := connect()
+	defer Conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	report, err := gtfs.NewGtfsService(gtfs.NewGtfsRepository(Conn)).Import(ctx, feed)
+	printCount("bus_stop", report.BusStops)
+	printCount("bus_line", report.BusLines)
+	printCount("bus_schedule", report.BusSchedules)
+	printCount("metro", report.MetroStations)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func exportFeed(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	file := flags.String("file", "", "path of the GTFS zip archive to write")
+	timeout := flags.Duration("timeout", 5*time.Minute, "maximum duration of the export")
+	flags.Parse(args)
+
+	if *file == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	Conn := connect()
+	defer Conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	feed, err := gtfs.NewGtfsService(gtfs.NewGtfsRepository(Conn)).Export(ctx, gtfs.AgencyFromEnv())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	output, err := os.Create(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer output.Close()
+
+	if err := gtfs.WriteFeed(output, feed); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("exported %d stops, %d routes and %d trips to %s\n",
+		len(feed.Stops), len(feed.Routes), len(feed.Trips), *file)
+}
+
+func printCount(table string, count gtfs.ImportCount) {
+	fmt.Printf("%-13s created: %d, updated: %d, skipped: %d\n",
+		table, count.Created, count.Updated, count.Skipped)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+const usage = `usage:
+  jwtkey -alg <RS256|EdDSA> -out <name>
+
+writes the private key to <name>.pem and the public key to <name>.pub.pem`
+
+func main() {
+	flags := flag.NewFlagSet("jwtkey", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprintln(os.Stderr, usage) }
+	algorithm := flags.String("alg", "EdDSA", "signing algorithm, RS256 or EdDSA")
+	out := flags.String("out", "", "name of the key files, without extension")
+	flags.Parse(os.Args[1:])
+
+	if *out == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	private, public, err := utils.GenerateJWTKeyPair(*algorithm)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := writeKey(*out+".pem", private, 0600); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := writeKey(*out+".pub.pem", public, 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("private key: %s.pem\npublic key: %s.pub.pem\n", *out, *out)
+	fmt.Printf("set JWT_KEYS=<kid>=pem:%s.pem to sign with it\n", *out)
+}
+
+// writeKey never overwrites, so an existing key in use is not lost.
+func writeKey(path string, data []byte, perm os.FileMode) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -5,12 +5,16 @@ import "time"
 type Address struct {
 	ID		        int64
 	UserID	        int64
+    Label           string
+    IsPrimary       bool
 	Street 	        string
 	Number	        string
 	CEP		        string
 	Neighborhood    string
 	City	        string
 	State	        string
+    Latitude        *float64
+    Longitude       *float64
     CreatedAt       time.Time
     UpdatedAt       *time.Time
     DeletedAt       *time.Time
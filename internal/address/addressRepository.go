@@ -3,6 +3,7 @@ package address
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/amarantec/move-easy/internal"
 	"github.com/jackc/pgx/v5"
@@ -12,6 +13,11 @@ import (
 type IAddressRepository interface {
 	AddOrUpdateAddress(ctx context.Context, address internal.Address) (int64, error)
 	GetAddress(ctx context.Context, userID int64) (internal.Address, error)
+	ListAddresses(ctx context.Context, userID int64) ([]internal.Address, error)
+	CreateAddress(ctx context.Context, address internal.Address) (int64, error)
+	UpdateAddress(ctx context.Context, address internal.Address) (bool, error)
+	DeleteAddress(ctx context.Context, userID, addressID int64) (bool, error)
+	SetPrimaryAddress(ctx context.Context, userID, addressID int64) (bool, error)
 }
 
 type addressRepository struct {
@@ -22,15 +28,19 @@ func NewAddressRepository(conn *pgxpool.Pool) IAddressRepository {
 	return &addressRepository{Conn: conn}
 }
 
+// GetAddress returns the primary address of the user.
 func (r *addressRepository) GetAddress(ctx context.Context, userID int64) (internal.Address, error) {
 	address := internal.Address{UserID: userID}
 	if err :=
 		r.Conn.QueryRow(
 			ctx,
-			`SELECT id, street, number, cep, neighborhood, city, state
-				FROM address WHERE user_id = $1 AND deleted_at IS NULL;`, userID).Scan(&address.ID,
-			&address.Street, &address.Number, &address.CEP,
-			&address.Neighborhood, &address.City, &address.State); err != nil {
+			`SELECT id, label, is_primary, street, number, cep, neighborhood, city, state, latitude, longitude,
+				created_at, updated_at
+				FROM address WHERE user_id = $1 AND deleted_at IS NULL
+				ORDER BY is_primary DESC, id LIMIT 1;`, userID).Scan(&address.ID,
+			&address.Label, &address.IsPrimary, &address.Street, &address.Number, &address.CEP,
+			&address.Neighborhood, &address.City, &address.State,
+			&address.Latitude, &address.Longitude, &address.CreatedAt, &address.UpdatedAt); err != nil {
 
 		if err == pgx.ErrNoRows {
 			return internal.Address{}, nil
@@ -42,6 +52,8 @@ func (r *addressRepository) GetAddress(ctx context.Context, userID int64) (inter
 	return address, nil
 }
 
+// AddOrUpdateAddress saves the primary address of the user, creating it when
+// the user has no address yet.
 func (r *addressRepository) AddOrUpdateAddress(ctx context.Context, address internal.Address) (int64, error) {
 	addressDB, err := r.GetAddress(ctx, address.UserID)
 	if err != nil && err != pgx.ErrNoRows {
@@ -49,27 +61,25 @@ func (r *addressRepository) AddOrUpdateAddress(ctx context.Context, address inte
 	}
 
 	if addressDB.ID == internal.ZERO {
-		err := r.Conn.QueryRow(
-			ctx,
-			`INSERT INTO address (user_id, street, number, cep, neighborhood, city, state) VALUES
-				($1, $2, $3, $4, $5, $6, $7) RETURNING id;`, address.UserID, address.Street, address.Number,
-			address.CEP, address.Neighborhood, address.City, address.State).Scan(&address.ID)
-		if err != nil {
-			return internal.ZERO, err
-		}
-		return address.ID, nil
+		address.IsPrimary = true
+		return r.CreateAddress(ctx, address)
 	} else {
 		res, err :=
 			r.Conn.Exec(
 				ctx,
-				`UPDATE address SET street = $3,
-					number = $4,
-					cep = $5,
-					neighborhood = $6,
-					city = $7,
-					state = $8
-				WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;`, address.ID, address.UserID, address.Street, address.Number,
-				address.CEP, address.Neighborhood, address.City, address.State)
+				`UPDATE address SET label = COALESCE(NULLIF($3, ''), label),
+					street = $4,
+					number = $5,
+					cep = $6,
+					neighborhood = $7,
+					city = $8,
+					state = $9,
+					latitude = $10,
+					longitude = $11,
+					updated_at = $12
+				WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;`, addressDB.ID, address.UserID, address.Label,
+				address.Street, address.Number, address.CEP, address.Neighborhood, address.City, address.State,
+				address.Latitude, address.Longitude, time.Now())
 		if err != nil {
 			return internal.ZERO, err
 		}
@@ -79,7 +89,215 @@ func (r *addressRepository) AddOrUpdateAddress(ctx context.Context, address inte
 			return internal.ZERO, nil
 		} else {
 			log.Printf("%d rows affected", res.RowsAffected())
-			return address.ID, nil
+			return addressDB.ID, nil
+		}
+	}
+}
+
+func (r *addressRepository) ListAddresses(ctx context.Context, userID int64) ([]internal.Address, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	addressChannel := make(chan []internal.Address)
+	errorChannel := make(chan error)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT id, label, is_primary, street, number, cep, neighborhood, city, state, latitude, longitude,
+					created_at, updated_at
+					FROM address WHERE user_id = $1 AND deleted_at IS NULL
+					ORDER BY is_primary DESC, id;`, userID)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		addresses := []internal.Address{}
+		for rows.Next() {
+			a := internal.Address{}
+			if err := rows.Scan(
+				&a.ID,
+				&a.Label,
+				&a.IsPrimary,
+				&a.Street,
+				&a.Number,
+				&a.CEP,
+				&a.Neighborhood,
+				&a.City,
+				&a.State,
+				&a.Latitude,
+				&a.Longitude,
+				&a.CreatedAt,
+				&a.UpdatedAt); err != nil {
+				errorChannel <- err
+				return
+			}
+			a.UserID = userID
+			addresses = append(addresses, a)
+		}
+		addressChannel <- addresses
+	}()
+
+	select {
+	case addresses := <-addressChannel:
+		return addresses, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// CreateAddress inserts a new address. The first address of a user is always
+// primary, and a new primary address takes the flag from the previous one.
+func (r *addressRepository) CreateAddress(ctx context.Context, address internal.Address) (int64, error) {
+	if address.Label == internal.EMPTY {
+		address.Label = DEFAULT_ADDRESS_LABEL
+	}
+
+	tx, err := r.Conn.Begin(ctx)
+	if err != nil {
+		return internal.ZERO, err
+	}
+	defer tx.Rollback(ctx)
+
+	if address.IsPrimary {
+		if err := unsetPrimaryAddress(ctx, tx, address.UserID); err != nil {
+			return internal.ZERO, err
+		}
+	}
+
+	if err :=
+		tx.QueryRow(
+			ctx,
+			`INSERT INTO address (user_id, label, is_primary, street, number, cep, neighborhood, city, state,
+				latitude, longitude) VALUES
+				($1, $2, $3 OR NOT EXISTS (SELECT 1 FROM address WHERE user_id = $1 AND is_primary AND deleted_at IS NULL),
+				$4, $5, $6, $7, $8, $9, $10, $11) RETURNING id;`, address.UserID, address.Label, address.IsPrimary,
+			address.Street, address.Number, address.CEP, address.Neighborhood, address.City, address.State,
+			address.Latitude, address.Longitude).Scan(&address.ID); err != nil {
+		return internal.ZERO, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return internal.ZERO, err
+	}
+
+	return address.ID, nil
+}
+
+// UpdateAddress changes the label and location of an address. The primary
+// flag is only changed by SetPrimaryAddress.
+func (r *addressRepository) UpdateAddress(ctx context.Context, address internal.Address) (bool, error) {
+	result, err :=
+		r.Conn.Exec(
+			ctx,
+			`UPDATE address SET label = $3,
+				street = $4,
+				number = $5,
+				cep = $6,
+				neighborhood = $7,
+				city = $8,
+				state = $9,
+				latitude = $10,
+				longitude = $11,
+				updated_at = $12
+			WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;`, address.ID, address.UserID, address.Label,
+			address.Street, address.Number, address.CEP, address.Neighborhood, address.City, address.State,
+			address.Latitude, address.Longitude, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() > internal.ZERO, nil
+}
+
+// DeleteAddress removes an address. When it was the primary one, the oldest
+// remaining address becomes primary.
+func (r *addressRepository) DeleteAddress(ctx context.Context, userID, addressID int64) (bool, error) {
+	tx, err := r.Conn.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var wasPrimary bool
+	if err :=
+		tx.QueryRow(
+			ctx,
+			`SELECT is_primary FROM address
+				WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL FOR UPDATE;`, addressID, userID).Scan(&wasPrimary); err != nil {
+
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if _, err :=
+		tx.Exec(
+			ctx,
+			`UPDATE address SET deleted_at = $2, is_primary = FALSE WHERE id = $1;`, addressID, time.Now()); err != nil {
+		return false, err
+	}
+
+	if wasPrimary {
+		if _, err :=
+			tx.Exec(
+				ctx,
+				`UPDATE address SET is_primary = TRUE WHERE id =
+					(SELECT MIN(id) FROM address WHERE user_id = $1 AND deleted_at IS NULL);`, userID); err != nil {
+			return false, err
 		}
 	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *addressRepository) SetPrimaryAddress(ctx context.Context, userID, addressID int64) (bool, error) {
+	tx, err := r.Conn.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := unsetPrimaryAddress(ctx, tx, userID); err != nil {
+		return false, err
+	}
+
+	result, err :=
+		tx.Exec(
+			ctx,
+			`UPDATE address SET is_primary = TRUE, updated_at = $3
+				WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;`, addressID, userID, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	if result.RowsAffected() == internal.ZERO {
+		return false, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func unsetPrimaryAddress(ctx context.Context, tx pgx.Tx, userID int64) error {
+	_, err :=
+		tx.Exec(
+			ctx,
+			`UPDATE address SET is_primary = FALSE
+				WHERE user_id = $1 AND is_primary AND deleted_at IS NULL;`, userID)
+	return err
 }
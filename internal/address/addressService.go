@@ -3,22 +3,55 @@ package address
 import (
 	"context"
 	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/geocoding"
+	"github.com/amarantec/move-easy/internal/postal"
+	"github.com/amarantec/move-easy/internal/utils"
 	"errors"
+	"fmt"
+	"strings"
 	"unicode/utf8"
 	"unicode"
 )
 
+const DEFAULT_ADDRESS_LABEL = "Home"
+
 type IAddressService interface {
 	GetAddress(ctx context.Context, userID int64) (internal.Address, error)
 	AddOrUpdateAddress(ctx context.Context, address internal.Address) (int64, error)
+	ListAddresses(ctx context.Context, userID int64) ([]internal.Address, error)
+	CreateAddress(ctx context.Context, address internal.Address) (int64, error)
+	UpdateAddress(ctx context.Context, address internal.Address) (bool, error)
+	DeleteAddress(ctx context.Context, userID, addressID int64) (bool, error)
+	SetPrimaryAddress(ctx context.Context, userID, addressID int64) (bool, error)
+}
+
+// FieldError tells which field of an address disagrees with the postal
+// directory and why.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := []string{}
+	for _, field := range e.Fields {
+		messages = append(messages, field.Field+": "+field.Message)
+	}
+	return "address does not match the postal directory, " + strings.Join(messages, ", ")
 }
 
 type addressService struct {
 	addressRepository IAddressRepository
+	geocoder          geocoding.IGeocoder
+	directory         postal.IPostalDirectory
 }
 
-func NewAddressService(repository IAddressRepository) IAddressService {
-	return &addressService{addressRepository: repository}
+func NewAddressService(repository IAddressRepository, geocoder geocoding.IGeocoder, directory postal.IPostalDirectory) IAddressService {
+	return &addressService{addressRepository: repository, geocoder: geocoder, directory: directory}
 }
 
 func (s *addressService) GetAddress(ctx context.Context, userID int64) (internal.Address, error) {
@@ -28,19 +61,156 @@ func (s *addressService) GetAddress(ctx context.Context, userID int64) (internal
 	return s.addressRepository.GetAddress(ctx, userID)
 }
 
+// AddOrUpdateAddress saves the primary address of the user.
 func (s *addressService) AddOrUpdateAddress(ctx context.Context, address internal.Address) (int64, error) {
-	if valid, err := validateAddress(address); err != nil || !valid {
+	if err := s.prepareAddress(ctx, &address); err != nil {
 		return internal.ZERO, err
 	}
 
 	return s.addressRepository.AddOrUpdateAddress(ctx, address)
 }
 
+func (s *addressService) ListAddresses(ctx context.Context, userID int64) ([]internal.Address, error) {
+	if userID <= internal.ZERO {
+		return []internal.Address{}, ErrAddressUserIDInvalid
+	}
+	return s.addressRepository.ListAddresses(ctx, userID)
+}
+
+func (s *addressService) CreateAddress(ctx context.Context, address internal.Address) (int64, error) {
+	if address.Label = strings.TrimSpace(address.Label); address.Label == internal.EMPTY {
+		return internal.ZERO, ErrAddressLabelEmpty
+	}
+
+	if err := s.prepareAddress(ctx, &address); err != nil {
+		return internal.ZERO, err
+	}
+
+	return s.addressRepository.CreateAddress(ctx, address)
+}
+
+// UpdateAddress changes an address and, when IsPrimary is set, makes it the
+// primary one.
+func (s *addressService) UpdateAddress(ctx context.Context, address internal.Address) (bool, error) {
+	if address.ID <= internal.ZERO {
+		return false, ErrAddressIDInvalid
+	}
+
+	if address.Label = strings.TrimSpace(address.Label); address.Label == internal.EMPTY {
+		return false, ErrAddressLabelEmpty
+	}
+
+	if err := s.prepareAddress(ctx, &address); err != nil {
+		return false, err
+	}
+
+	updated, err := s.addressRepository.UpdateAddress(ctx, address)
+	if err != nil || !updated || !address.IsPrimary {
+		return updated, err
+	}
+
+	return s.addressRepository.SetPrimaryAddress(ctx, address.UserID, address.ID)
+}
+
+func (s *addressService) DeleteAddress(ctx context.Context, userID, addressID int64) (bool, error) {
+	if userID <= internal.ZERO {
+		return false, ErrAddressUserIDInvalid
+	}
+
+	if addressID <= internal.ZERO {
+		return false, ErrAddressIDInvalid
+	}
+
+	return s.addressRepository.DeleteAddress(ctx, userID, addressID)
+}
+
+func (s *addressService) SetPrimaryAddress(ctx context.Context, userID, addressID int64) (bool, error) {
+	if userID <= internal.ZERO {
+		return false, ErrAddressUserIDInvalid
+	}
+
+	if addressID <= internal.ZERO {
+		return false, ErrAddressIDInvalid
+	}
+
+	return s.addressRepository.SetPrimaryAddress(ctx, userID, addressID)
+}
+
+// prepareAddress fills a missing neighborhood and city from the postal
+// directory and, after validating the address, checks that its CEP exists and
+// belongs to the city and state given, then geocodes it.
+func (s *addressService) prepareAddress(ctx context.Context, address *internal.Address) error {
+	var code postal.PostalCode
+	if s.directory != nil {
+		var err error
+		if code, err = s.directory.Lookup(ctx, address.CEP); err != nil && !errors.Is(err, postal.ErrPostalCodeNotFound) {
+			return err
+		}
+
+		if address.Neighborhood == internal.EMPTY {
+			address.Neighborhood = code.Neighborhood
+		}
+		if address.City == internal.EMPTY {
+			address.City = code.City
+		}
+	}
+
+	if valid, err := validateAddress(*address); err != nil || !valid {
+		return err
+	}
+
+	if s.directory != nil {
+		if err := matchPostalCode(*address, code); err != nil {
+			return err
+		}
+	}
+
+	address.Latitude, address.Longitude = nil, nil
+	if s.geocoder != nil {
+		result, err := s.geocoder.Geocode(ctx, address.CEP, address.Street, address.Number)
+		if err != nil && !errors.Is(err, geocoding.ErrGeocodeNotFound) {
+			return err
+		}
+		// An address outside the dataset is still saved, without coordinates.
+		if err == nil {
+			address.Latitude, address.Longitude = &result.Latitude, &result.Longitude
+		}
+	}
+
+	return nil
+}
+
+func matchPostalCode(a internal.Address, code postal.PostalCode) error {
+	if code.CEP == internal.EMPTY {
+		return &ValidationError{Fields: []FieldError{{Field: "CEP", Message: postal.ErrPostalCodeNotFound.Error()}}}
+	}
+
+	fields := []FieldError{}
+	if utils.FoldText(a.City) != utils.FoldText(code.City) {
+		fields = append(fields, FieldError{Field: "City",
+			Message: fmt.Sprintf("cep %s belongs to %s, not %s", code.CEP, code.City, a.City)})
+	}
+
+	if strings.ToUpper(a.State) != code.State {
+		fields = append(fields, FieldError{Field: "State",
+			Message: fmt.Sprintf("cep %s belongs to %s, not %s", code.CEP, code.State, a.State)})
+	}
+
+	if len(fields) > internal.ZERO {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
 func validateAddress(a internal.Address) (bool, error) {
 	if a.UserID <= internal.ZERO {
 		return false, ErrAddressUserIDInvalid
 	}
 
+	if utf8.RuneCountInString(a.Label) > 50 {
+		return false, ErrAddressLabelInvalid
+	}
+
 	if a.Street == internal.EMPTY {
 		return false, ErrAddressStreetEmpty
 	} else if utf8.RuneCountInString(a.Street) < 3 || utf8.RuneCountInString(a.Street) > 100 {
@@ -94,6 +264,9 @@ func validateAddress(a internal.Address) (bool, error) {
 
 var (
     ErrAddressUserIDInvalid = errors.New("address user id is empty or negative")
+    ErrAddressIDInvalid = errors.New("address id is empty or negative")
+    ErrAddressLabelEmpty = errors.New("address label is empty")
+    ErrAddressLabelInvalid = errors.New("address label must have at most 50 characters")
     ErrAddressStreetEmpty = errors.New("address street is empty")
     ErrAddressStreetInvalid = errors.New("address street must be between 3-100 characters")
     ErrAddressNumberEmpty = errors.New("address number is empty")
@@ -3,13 +3,21 @@ package address
 import (
     "context"
     "errors"
+    "strings"
     "testing"
     "github.com/amarantec/move-easy/internal"
+    "github.com/amarantec/move-easy/internal/geocoding"
+    "github.com/amarantec/move-easy/internal/postal"
 )
 
 type mockAddressRepository struct {
     GetAddressFunc      func(ctx context.Context, userID int64) (internal.Address, error)
     AddOrUpdateFunc     func(ctx context.Context, address internal.Address) (int64, error)
+    ListAddressesFunc   func(ctx context.Context, userID int64) ([]internal.Address, error)
+    CreateFunc          func(ctx context.Context, address internal.Address) (int64, error)
+    UpdateFunc          func(ctx context.Context, address internal.Address) (bool, error)
+    DeleteFunc          func(ctx context.Context, userID, addressID int64) (bool, error)
+    SetPrimaryFunc      func(ctx context.Context, userID, addressID int64) (bool, error)
 }
 
 func (m *mockAddressRepository) GetAddress(ctx context.Context, userID int64) (internal.Address, error) {
@@ -26,6 +34,41 @@ func (m *mockAddressRepository) AddOrUpdateAddress(ctx context.Context, address
     return internal.ZERO, ErrAddOrUpdateNotImplemented
 }
 
+func (m *mockAddressRepository) ListAddresses(ctx context.Context, userID int64) ([]internal.Address, error) {
+    if m.ListAddressesFunc != nil {
+        return m.ListAddressesFunc(ctx, userID)
+    }
+    return nil, ErrNotImplemented
+}
+
+func (m *mockAddressRepository) CreateAddress(ctx context.Context, address internal.Address) (int64, error) {
+    if m.CreateFunc != nil {
+        return m.CreateFunc(ctx, address)
+    }
+    return internal.ZERO, ErrNotImplemented
+}
+
+func (m *mockAddressRepository) UpdateAddress(ctx context.Context, address internal.Address) (bool, error) {
+    if m.UpdateFunc != nil {
+        return m.UpdateFunc(ctx, address)
+    }
+    return false, ErrNotImplemented
+}
+
+func (m *mockAddressRepository) DeleteAddress(ctx context.Context, userID, addressID int64) (bool, error) {
+    if m.DeleteFunc != nil {
+        return m.DeleteFunc(ctx, userID, addressID)
+    }
+    return false, ErrNotImplemented
+}
+
+func (m *mockAddressRepository) SetPrimaryAddress(ctx context.Context, userID, addressID int64) (bool, error) {
+    if m.SetPrimaryFunc != nil {
+        return m.SetPrimaryFunc(ctx, userID, addressID)
+    }
+    return false, ErrNotImplemented
+}
+
 func TestGetAddress(t *testing.T) {
     tests := []struct {
         name      string
@@ -86,7 +129,7 @@ func TestGetAddress(t *testing.T) {
             mockRepo := &mockAddressRepository{
                 GetAddressFunc: tt.mockFunc,
             }
-            service := NewAddressService(mockRepo)
+            service := NewAddressService(mockRepo, nil, nil)
 
             got, err := service.GetAddress(context.Background(), tt.userID)
             if (err != nil) != tt.wantErr {
@@ -165,7 +208,7 @@ func TestAddOrUpdateAddress(t *testing.T) {
             mockRepo := &mockAddressRepository{
                 AddOrUpdateFunc: tt.mockFunc,
             }
-            service := NewAddressService(mockRepo)
+            service := NewAddressService(mockRepo, nil, nil)
 
             id, err := service.AddOrUpdateAddress(context.Background(), tt.input)
             if (err != nil) != tt.wantErr {
@@ -178,7 +221,195 @@ func TestAddOrUpdateAddress(t *testing.T) {
     }
 }
 
+func TestAddOrUpdateAddressGeocoded(t *testing.T) {
+    geocoder, err := geocoding.NewCSVGeocoder(strings.NewReader(
+        "cep,street,number_start,number_end,latitude,longitude\n95520000,Rua General Osório,2000,2999,-29.8850,-50.2680\n"))
+    if err != nil {
+        t.Fatalf("Erro inesperado: %v", err)
+    }
+
+    tests := []struct {
+        name        string
+        cep         string
+        wantLocated bool
+    }{
+        {name: "CEP encontrado", cep: "95520000", wantLocated: true},
+        {name: "CEP fora da base", cep: "01001000", wantLocated: false},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var saved internal.Address
+            mockRepo := &mockAddressRepository{
+                AddOrUpdateFunc: func(ctx context.Context, address internal.Address) (int64, error) {
+                    saved = address
+                    return 1, nil
+                },
+            }
+            service := NewAddressService(mockRepo, geocoder, nil)
+
+            _, err := service.AddOrUpdateAddress(context.Background(), internal.Address{
+                UserID:       1,
+                Street:       "R. General Osório",
+                Number:       "2211",
+                CEP:          tt.cep,
+                Neighborhood: "Glória",
+                City:         "Osório",
+                State:        "RS",
+            })
+            if err != nil {
+                t.Fatalf("[%s] Erro inesperado: %v", tt.name, err)
+            }
+
+            if located := saved.Latitude != nil && saved.Longitude != nil; located != tt.wantLocated {
+                t.Fatalf("[%s] Coordenadas esperadas: %v, recebidas: %+v", tt.name, tt.wantLocated, saved)
+            }
+            if tt.wantLocated && (*saved.Latitude != -29.8850 || *saved.Longitude != -50.2680) {
+                t.Errorf("[%s] Coordenadas inesperadas: %v, %v", tt.name, *saved.Latitude, *saved.Longitude)
+            }
+        })
+    }
+}
+
+func TestAddOrUpdateAddressPostalCode(t *testing.T) {
+    directory, err := postal.NewCSVDirectory(strings.NewReader(
+        "cep,neighborhood,city,state\n95520000,Centro,Osório,RS\n"))
+    if err != nil {
+        t.Fatalf("Erro inesperado: %v", err)
+    }
+
+    tests := []struct {
+        name       string
+        input      internal.Address
+        wantFields []string
+        wantSaved  internal.Address
+    }{
+        {
+            name:      "Bairro e cidade preenchidos pelo CEP",
+            input:     internal.Address{UserID: 1, Street: "Rua Nova", Number: "10", CEP: "95520000", State: "RS"},
+            wantSaved: internal.Address{UserID: 1, Street: "Rua Nova", Number: "10", CEP: "95520000", Neighborhood: "Centro", City: "Osório", State: "RS"},
+        },
+        {
+            name:      "Cidade sem acento",
+            input:     internal.Address{UserID: 1, Street: "Rua Nova", Number: "10", CEP: "95520000", Neighborhood: "Glória", City: "osorio", State: "rs"},
+            wantSaved: internal.Address{UserID: 1, Street: "Rua Nova", Number: "10", CEP: "95520000", Neighborhood: "Glória", City: "osorio", State: "rs"},
+        },
+        {
+            name:       "Cidade e estado divergentes",
+            input:      internal.Address{UserID: 1, Street: "Rua Nova", Number: "10", CEP: "95520000", Neighborhood: "Centro", City: "Porto Alegre", State: "SC"},
+            wantFields: []string{"City", "State"},
+        },
+        {
+            name:       "CEP inexistente",
+            input:      internal.Address{UserID: 1, Street: "Rua Nova", Number: "10", CEP: "00000000", Neighborhood: "Centro", City: "Osório", State: "RS"},
+            wantFields: []string{"CEP"},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var saved internal.Address
+            mockRepo := &mockAddressRepository{
+                AddOrUpdateFunc: func(ctx context.Context, address internal.Address) (int64, error) {
+                    saved = address
+                    return 1, nil
+                },
+            }
+            service := NewAddressService(mockRepo, nil, directory)
+
+            _, err := service.AddOrUpdateAddress(context.Background(), tt.input)
+
+            var validationErr *ValidationError
+            if len(tt.wantFields) == internal.ZERO {
+                if err != nil {
+                    t.Fatalf("[%s] Erro inesperado: %v", tt.name, err)
+                }
+                if saved != tt.wantSaved {
+                    t.Errorf("[%s] Endereço esperado: %+v, recebido: %+v", tt.name, tt.wantSaved, saved)
+                }
+                return
+            }
+
+            if !errors.As(err, &validationErr) {
+                t.Fatalf("[%s] Erro de validação esperado, recebido: %v", tt.name, err)
+            }
+            if len(validationErr.Fields) != len(tt.wantFields) {
+                t.Fatalf("[%s] Campos esperados: %v, recebidos: %+v", tt.name, tt.wantFields, validationErr.Fields)
+            }
+            for i, field := range tt.wantFields {
+                if validationErr.Fields[i].Field != field {
+                    t.Errorf("[%s] Campo esperado: %s, recebido: %s", tt.name, field, validationErr.Fields[i].Field)
+                }
+            }
+        })
+    }
+}
+
+func TestCreateAddress(t *testing.T) {
+    valid := internal.Address{UserID: 1, Label: " Work ", Street: "Rua Nova", Number: "10", CEP: "95520000",
+        Neighborhood: "Centro", City: "Osório", State: "RS"}
+
+    tests := []struct {
+        name      string
+        label     string
+        wantLabel string
+        wantError error
+    }{
+        {name: "Rótulo sem espaços", label: " Work ", wantLabel: "Work"},
+        {name: "Rótulo vazio", label: "  ", wantError: ErrAddressLabelEmpty},
+        {name: "Rótulo longo", label: strings.Repeat("a", 51), wantError: ErrAddressLabelInvalid},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var saved internal.Address
+            mockRepo := &mockAddressRepository{
+                CreateFunc: func(ctx context.Context, address internal.Address) (int64, error) {
+                    saved = address
+                    return 2, nil
+                },
+            }
+
+            input := valid
+            input.Label = tt.label
+            _, err := NewAddressService(mockRepo, nil, nil).CreateAddress(context.Background(), input)
+            if !errors.Is(err, tt.wantError) {
+                t.Fatalf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+            }
+            if saved.Label != tt.wantLabel {
+                t.Errorf("[%s] Rótulo esperado: %q, recebido: %q", tt.name, tt.wantLabel, saved.Label)
+            }
+        })
+    }
+}
+
+func TestUpdateAddressPrimary(t *testing.T) {
+    var primaryID int64
+    mockRepo := &mockAddressRepository{
+        UpdateFunc: func(ctx context.Context, address internal.Address) (bool, error) {
+            return true, nil
+        },
+        SetPrimaryFunc: func(ctx context.Context, userID, addressID int64) (bool, error) {
+            primaryID = addressID
+            return true, nil
+        },
+    }
+
+    updated, err := NewAddressService(mockRepo, nil, nil).UpdateAddress(context.Background(), internal.Address{
+        ID: 3, UserID: 1, Label: "School", IsPrimary: true, Street: "Rua Nova", Number: "10", CEP: "95520000",
+        Neighborhood: "Centro", City: "Osório", State: "RS",
+    })
+    if err != nil || !updated {
+        t.Fatalf("Atualização esperada, recebido: %v, %v", updated, err)
+    }
+
+    if primaryID != 3 {
+        t.Errorf("Endereço principal esperado: 3, recebido: %d", primaryID)
+    }
+}
+
 var (
+    ErrNotImplemented               = errors.New("function not implemented")
     ErrUserIDEmpty                  = errors.New("UserID is empty")
     ErrGetAddressNotImplemented     = errors.New("GetAddress function not implemented")
     ErrAddOrUpdateNotImplemented    = errors.New("AddOrUpdate function not implemented")
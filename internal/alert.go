@@ -0,0 +1,28 @@
+package internal
+
+import "time"
+
+type AlertReason string
+
+const (
+	SOS_ALERT		AlertReason = "SOS"
+	ACCIDENT_ALERT	AlertReason = "ACCIDENT"
+)
+
+type AlertDelivery struct {
+	ContactID	int64
+	ContactName	string
+	Channel		string
+	Sent		bool
+	Error		string
+}
+
+type Alert struct {
+	UserID		int64
+	Reason		AlertReason
+	Latitude	*float64
+	Longitude	*float64
+	LocatedAt	*time.Time
+	Message		string
+	Deliveries	[]AlertDelivery
+}
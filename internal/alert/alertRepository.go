@@ -0,0 +1,67 @@
+package alert
+
+import (
+	"context"
+	"strings"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IAlertRepository interface {
+	GetUserName(ctx context.Context, userID int64) (string, error)
+	GetLastUserLocation(ctx context.Context, userID int64) (internal.UserLocation, error)
+}
+
+type alertRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewAlertRepository(connection *pgxpool.Pool) IAlertRepository {
+	return &alertRepository{Conn: connection}
+}
+
+func (r *alertRepository) GetUserName(ctx context.Context, userID int64) (string, error) {
+	var firstName, lastName *string
+	if err :=
+		r.Conn.QueryRow(
+			ctx,
+			`SELECT first_name, last_name FROM users
+				WHERE id = $1 AND deleted_at IS NULL;`, userID).Scan(&firstName, &lastName); err != nil {
+
+		if err == pgx.ErrNoRows {
+			return internal.EMPTY, nil
+		}
+
+		return internal.EMPTY, err
+	}
+
+	name := []string{}
+	for _, part := range []*string{firstName, lastName} {
+		if part != nil && strings.TrimSpace(*part) != internal.EMPTY {
+			name = append(name, strings.TrimSpace(*part))
+		}
+	}
+	return strings.Join(name, " "), nil
+}
+
+func (r *alertRepository) GetLastUserLocation(ctx context.Context, userID int64) (internal.UserLocation, error) {
+	location := internal.UserLocation{UserID: userID}
+	if err :=
+		r.Conn.QueryRow(
+			ctx,
+			`SELECT id, line_id, latitude, longitude, time_stamp, created_at
+				FROM user_location WHERE user_id = $1
+				ORDER BY time_stamp DESC LIMIT 1;`, userID).Scan(&location.ID, &location.LineID,
+			&location.Latitude, &location.Longitude, &location.TimeStamp, &location.CreatedAt); err != nil {
+
+		if err == pgx.ErrNoRows {
+			return internal.UserLocation{}, nil
+		}
+
+		return internal.UserLocation{}, err
+	}
+
+	return location, nil
+}
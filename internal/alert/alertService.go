@@ -0,0 +1,193 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/contact"
+	"github.com/amarantec/move-easy/internal/notifier"
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+const DEFAULT_ALERT_NAME = "A Move Easy user"
+
+var alertSubject = template.Must(template.New("subject").Parse(
+	`Emergency alert from {{.Name}}`))
+
+var alertBody = template.Must(template.New("body").Parse(
+	`{{.Name}} {{if eq .Reason "ACCIDENT"}}reported an accident{{else}}needs help{{end}} and listed you as an emergency contact.
+{{if .Located}}Last known location ({{.LocatedAt.Format "02/01/2006 15:04"}}): https://www.google.com/maps/search/?api=1&query={{.Latitude}},{{.Longitude}}{{else}}Their location is unknown.{{end}}`))
+
+type IAlertService interface {
+	SendSOS(ctx context.Context, userID int64, latitude, longitude *float64) (internal.Alert, error)
+	NotifyOccurrence(ctx context.Context, occurrence internal.Occurrence) (internal.Alert, error)
+}
+
+type alertService struct {
+	repository     IAlertRepository
+	contactService contact.IContactService
+	notifiers      []notifier.INotifier
+	limiter        *utils.RateLimiter
+}
+
+// NewAlertService lets each user send one alert of each reason per
+// cooldown, since every alert fans out paid messages to all the contacts. A
+// zero cooldown does not limit them.
+func NewAlertService(repo IAlertRepository, contactService contact.IContactService, notifiers []notifier.INotifier, cooldown time.Duration) IAlertService {
+	service := &alertService{repository: repo, contactService: contactService, notifiers: notifiers}
+	if cooldown > internal.ZERO {
+		service.limiter = utils.NewRateLimiter(1, cooldown)
+	}
+	return service
+}
+
+// SendSOS alerts the contacts of the user at the given location or, when
+// none is given, at the last location the user reported.
+func (s *alertService) SendSOS(ctx context.Context, userID int64, latitude, longitude *float64) (internal.Alert, error) {
+	if userID <= internal.ZERO {
+		return internal.Alert{}, ErrAlertUserIDInvalid
+	}
+
+	if (latitude == nil) != (longitude == nil) ||
+		(latitude != nil && !utils.ValidCoordinates(*latitude, *longitude)) {
+		return internal.Alert{}, ErrAlertLocationInvalid
+	}
+
+	alert := internal.Alert{UserID: userID, Reason: internal.SOS_ALERT, Latitude: latitude, Longitude: longitude}
+	if latitude != nil {
+		now := time.Now()
+		alert.LocatedAt = &now
+	}
+	return s.send(ctx, alert)
+}
+
+// NotifyOccurrence alerts the contacts of the user who reported an accident,
+// at the place of the occurrence. Other occurrence types are ignored.
+func (s *alertService) NotifyOccurrence(ctx context.Context, occurrence internal.Occurrence) (internal.Alert, error) {
+	if occurrence.Type != internal.ACCIDENT {
+		return internal.Alert{}, nil
+	}
+
+	if occurrence.UserID <= internal.ZERO {
+		return internal.Alert{}, ErrAlertUserIDInvalid
+	}
+
+	return s.send(ctx, internal.Alert{
+		UserID:    occurrence.UserID,
+		Reason:    internal.ACCIDENT_ALERT,
+		Latitude:  &occurrence.Latitude,
+		Longitude: &occurrence.Longitude,
+		LocatedAt: &occurrence.TimeStamp,
+	})
+}
+
+func (s *alertService) send(ctx context.Context, alert internal.Alert) (internal.Alert, error) {
+	if len(s.notifiers) == internal.ZERO {
+		return internal.Alert{}, ErrAlertNotifierMissing
+	}
+
+	contacts, err := s.contactService.ListContacts(ctx, alert.UserID)
+	if err != nil {
+		return internal.Alert{}, err
+	}
+
+	if len(contacts) == internal.ZERO {
+		return internal.Alert{}, ErrAlertContactsEmpty
+	}
+
+	key := fmt.Sprintf("%d:%s", alert.UserID, alert.Reason)
+	if s.limiter != nil && !s.limiter.Allow(key) {
+		return internal.Alert{}, ErrAlertCooldown
+	}
+
+	if alert.Latitude == nil {
+		location, err := s.repository.GetLastUserLocation(ctx, alert.UserID)
+		if err != nil {
+			return internal.Alert{}, err
+		}
+
+		if location.ID != internal.ZERO {
+			alert.Latitude = &location.Latitude
+			alert.Longitude = &location.Longitude
+			alert.LocatedAt = &location.TimeStamp
+		}
+	}
+
+	name, err := s.repository.GetUserName(ctx, alert.UserID)
+	if err != nil {
+		return internal.Alert{}, err
+	}
+
+	if name == internal.EMPTY {
+		name = DEFAULT_ALERT_NAME
+	}
+
+	message, err := renderAlert(name, alert)
+	if err != nil {
+		return internal.Alert{}, err
+	}
+	alert.Message = message.Body
+
+	sent := false
+	alert.Deliveries = []internal.AlertDelivery{}
+	for _, c := range contacts {
+		for _, n := range s.notifiers {
+			err := n.Notify(ctx, c, message)
+			if errors.Is(err, notifier.ErrNotifierNoDestination) {
+				continue
+			}
+
+			delivery := internal.AlertDelivery{ContactID: c.ID, ContactName: c.Name, Channel: n.Channel(), Sent: err == nil}
+			if err != nil {
+				delivery.Error = err.Error()
+			}
+			sent = sent || delivery.Sent
+			alert.Deliveries = append(alert.Deliveries, delivery)
+		}
+	}
+
+	if !sent {
+		// Nothing reached the contacts, so the user may try again at once.
+		if s.limiter != nil {
+			s.limiter.Release(key)
+		}
+		return alert, ErrAlertNotDelivered
+	}
+	return alert, nil
+}
+
+func renderAlert(name string, alert internal.Alert) (notifier.Message, error) {
+	data := map[string]interface{}{
+		"Name":    name,
+		"Reason":  string(alert.Reason),
+		"Located": alert.Latitude != nil && alert.LocatedAt != nil,
+	}
+	if alert.Latitude != nil && alert.LocatedAt != nil {
+		data["Latitude"] = *alert.Latitude
+		data["Longitude"] = *alert.Longitude
+		data["LocatedAt"] = *alert.LocatedAt
+	}
+
+	var subject, body strings.Builder
+	if err := alertSubject.Execute(&subject, data); err != nil {
+		return notifier.Message{}, err
+	}
+	if err := alertBody.Execute(&body, data); err != nil {
+		return notifier.Message{}, err
+	}
+	return notifier.Message{Subject: subject.String(), Body: body.String()}, nil
+}
+
+var (
+	ErrAlertUserIDInvalid   = errors.New("user id must be greater than zero")
+	ErrAlertLocationInvalid = errors.New("latitude and longitude must be given together and be valid coordinates")
+	ErrAlertNotifierMissing = errors.New("no notification channel is configured")
+	ErrAlertContactsEmpty   = errors.New("user has no emergency contacts")
+	ErrAlertNotDelivered    = errors.New("alert could not be delivered to any contact")
+	ErrAlertCooldown        = errors.New("an alert was sent moments ago, wait before sending another")
+)
@@ -0,0 +1,196 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/contact"
+	"github.com/amarantec/move-easy/internal/mailer"
+	"github.com/amarantec/move-easy/internal/notifier"
+)
+
+type mockAlertRepository struct {
+	location internal.UserLocation
+}
+
+func (m *mockAlertRepository) GetUserName(ctx context.Context, userID int64) (string, error) {
+	return "Maria Silva", nil
+}
+
+func (m *mockAlertRepository) GetLastUserLocation(ctx context.Context, userID int64) (internal.UserLocation, error) {
+	return m.location, nil
+}
+
+type mockContactService struct {
+	contact.IContactService
+	contacts []internal.Contact
+}
+
+func (m *mockContactService) ListContacts(ctx context.Context, userID int64) ([]internal.Contact, error) {
+	return m.contacts, nil
+}
+
+func TestSendSOS(t *testing.T) {
+	contacts := []internal.Contact{
+		{ID: 1, Name: "João", DDI: "55", DDD: "51", PhoneNumber: "999998888"},
+		{ID: 2, Name: "Ana", DDI: "55", DDD: "51", PhoneNumber: "999997777", Email: "ana@example.com"},
+	}
+	lastLocation := internal.UserLocation{ID: 9, Latitude: -29.88, Longitude: -50.27, TimeStamp: time.Now()}
+	latitude, longitude := -30.03, -51.23
+	invalid := 91.0
+
+	tests := []struct {
+		name         string
+		contacts     []internal.Contact
+		location     internal.UserLocation
+		latitude     *float64
+		longitude    *float64
+		wantError    error
+		wantSent     int
+		wantLocation string
+	}{
+		{
+			name:         "Localização informada",
+			contacts:     contacts,
+			location:     lastLocation,
+			latitude:     &latitude,
+			longitude:    &longitude,
+			wantSent:     2,
+			wantLocation: "query=-30.03,-51.23",
+		},
+		{
+			name:         "Última localização conhecida",
+			contacts:     contacts,
+			location:     lastLocation,
+			wantSent:     2,
+			wantLocation: "query=-29.88,-50.27",
+		},
+		{
+			name:         "Localização desconhecida",
+			contacts:     contacts,
+			wantSent:     2,
+			wantLocation: "location is unknown",
+		},
+		{
+			name:      "Localização inválida",
+			contacts:  contacts,
+			latitude:  &invalid,
+			longitude: &longitude,
+			wantError: ErrAlertLocationInvalid,
+		},
+		{
+			name:      "Somente latitude",
+			contacts:  contacts,
+			latitude:  &latitude,
+			wantError: ErrAlertLocationInvalid,
+		},
+		{
+			name:      "Sem contatos",
+			contacts:  []internal.Contact{},
+			wantError: ErrAlertContactsEmpty,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := notifier.NewFakeNotifier()
+			service := NewAlertService(&mockAlertRepository{location: tt.location},
+				&mockContactService{contacts: tt.contacts}, []notifier.INotifier{fake}, internal.ZERO)
+
+			alert, err := service.SendSOS(context.Background(), 1, tt.latitude, tt.longitude)
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+
+			if len(fake.Sent()) != tt.wantSent || len(alert.Deliveries) != tt.wantSent {
+				t.Errorf("[%s] Envios esperados: %d, recebidos: %d", tt.name, tt.wantSent, len(fake.Sent()))
+			}
+
+			if tt.wantSent > internal.ZERO {
+				if !strings.Contains(alert.Message, "Maria Silva needs help") || !strings.Contains(alert.Message, tt.wantLocation) {
+					t.Errorf("[%s] Mensagem inesperada: %s", tt.name, alert.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestSendSOSWithoutNotifier(t *testing.T) {
+	service := NewAlertService(&mockAlertRepository{}, &mockContactService{}, nil, internal.ZERO)
+	if _, err := service.SendSOS(context.Background(), 1, nil, nil); !errors.Is(err, ErrAlertNotifierMissing) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrAlertNotifierMissing, err)
+	}
+}
+
+func TestSendSOSNotDelivered(t *testing.T) {
+	contacts := []internal.Contact{{ID: 1, Name: "João", DDI: "55", DDD: "51", PhoneNumber: "999998888"}}
+	service := NewAlertService(&mockAlertRepository{}, &mockContactService{contacts: contacts},
+		[]notifier.INotifier{notifier.NewEmailNotifier(mailer.NewMemoryMailer())}, internal.ZERO)
+
+	alert, err := service.SendSOS(context.Background(), 1, nil, nil)
+	if !errors.Is(err, ErrAlertNotDelivered) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrAlertNotDelivered, err)
+	}
+
+	if len(alert.Deliveries) != internal.ZERO {
+		t.Errorf("Nenhuma entrega esperada para contato sem e-mail, recebidas: %+v", alert.Deliveries)
+	}
+}
+
+func TestSendSOSCooldown(t *testing.T) {
+	contacts := []internal.Contact{{ID: 1, Name: "João", DDI: "55", DDD: "51", PhoneNumber: "999998888"}}
+	fake := notifier.NewFakeNotifier()
+	service := NewAlertService(&mockAlertRepository{}, &mockContactService{contacts: contacts},
+		[]notifier.INotifier{fake}, 5*time.Minute)
+
+	if _, err := service.SendSOS(context.Background(), 1, nil, nil); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if _, err := service.SendSOS(context.Background(), 1, nil, nil); !errors.Is(err, ErrAlertCooldown) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrAlertCooldown, err)
+	}
+
+	if _, err := service.SendSOS(context.Background(), 2, nil, nil); err != nil {
+		t.Errorf("Outro usuário não deveria esperar, erro: %v", err)
+	}
+
+	if _, err := service.NotifyOccurrence(context.Background(),
+		internal.Occurrence{UserID: 1, Type: internal.ACCIDENT, Latitude: -29.88, Longitude: -50.27, TimeStamp: time.Now()}); err != nil {
+		t.Errorf("Acidente não deveria esperar pelo SOS, erro: %v", err)
+	}
+
+	if len(fake.Sent()) != 3 {
+		t.Errorf("Envios esperados: 3, recebidos: %d", len(fake.Sent()))
+	}
+}
+
+func TestNotifyOccurrence(t *testing.T) {
+	contacts := []internal.Contact{{ID: 1, Name: "João", DDI: "55", DDD: "51", PhoneNumber: "999998888"}}
+	fake := notifier.NewFakeNotifier()
+	service := NewAlertService(&mockAlertRepository{}, &mockContactService{contacts: contacts}, []notifier.INotifier{fake}, internal.ZERO)
+
+	if _, err := service.NotifyOccurrence(context.Background(),
+		internal.Occurrence{UserID: 1, Type: internal.LOCKED_BUS, Latitude: -29.88, Longitude: -50.27}); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if len(fake.Sent()) != internal.ZERO {
+		t.Errorf("Nenhum alerta esperado para ônibus trancado, recebidos: %d", len(fake.Sent()))
+	}
+
+	alert, err := service.NotifyOccurrence(context.Background(),
+		internal.Occurrence{ID: 3, UserID: 1, Type: internal.ACCIDENT, Latitude: -29.88, Longitude: -50.27, TimeStamp: time.Now()})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if alert.Reason != internal.ACCIDENT_ALERT || !strings.Contains(alert.Message, "reported an accident") ||
+		!strings.Contains(alert.Message, "query=-29.88,-50.27") {
+		t.Errorf("Alerta de acidente inesperado: %+v", alert)
+	}
+}
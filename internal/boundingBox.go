@@ -0,0 +1,30 @@
+package internal
+
+type BoundingBox struct {
+	MinLatitude		float64
+	MinLongitude	float64
+	MaxLatitude		float64
+	MaxLongitude	float64
+}
+
+func PointBox(latitude, longitude float64) BoundingBox {
+	return BoundingBox{MinLatitude: latitude, MinLongitude: longitude, MaxLatitude: latitude, MaxLongitude: longitude}
+}
+
+func (b BoundingBox) IsValid() bool {
+	return b.MinLatitude >= -90 && b.MaxLatitude <= 90 && b.MinLongitude >= -180 && b.MaxLongitude <= 180 &&
+		b.MinLatitude <= b.MaxLatitude && b.MinLongitude <= b.MaxLongitude
+}
+
+func (b BoundingBox) Intersects(other BoundingBox) bool {
+	return b.MinLatitude <= other.MaxLatitude && other.MinLatitude <= b.MaxLatitude &&
+		b.MinLongitude <= other.MaxLongitude && other.MinLongitude <= b.MaxLongitude
+}
+
+func (b BoundingBox) Extend(latitude, longitude float64) BoundingBox {
+	b.MinLatitude = min(b.MinLatitude, latitude)
+	b.MinLongitude = min(b.MinLongitude, longitude)
+	b.MaxLatitude = max(b.MaxLatitude, latitude)
+	b.MaxLongitude = max(b.MaxLongitude, longitude)
+	return b
+}
@@ -2,8 +2,11 @@ package bus
 
 import (
 	"context"
+	"time"
 
 	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/utils"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -12,6 +15,15 @@ type IBusRepository interface {
 	InsertBusStop(ctx context.Context, busStop internal.BusStop) (int64, error)
 	GetBusLine(ctx context.Context, busLineID int64) (internal.BusLine, error)
 	GetBusStop(ctx context.Context, busStopID int64) (internal.BusStop, error)
+	ListBusSchedules(ctx context.Context, busLineID int64) ([]internal.BusSchedules, error)
+	InsertBusSchedules(ctx context.Context, busLineID int64, schedules []internal.BusSchedules) ([]int64, error)
+	ReplaceBusSchedules(ctx context.Context, busLineID int64, schedules []internal.BusSchedules) ([]int64, error)
+	DeleteBusSchedule(ctx context.Context, busLineID, scheduleID int64) (bool, error)
+	ListNextDepartures(ctx context.Context, busLineID int64, dayOfWeek string, after time.Time, limit int) ([]internal.BusSchedules, error)
+	ListBusLineStops(ctx context.Context, busLineID int64) ([]internal.BusLineStop, error)
+	ReplaceBusLineStops(ctx context.Context, busLineID int64, stops []internal.BusLineStop) ([]int64, error)
+	ListBusLinesByStop(ctx context.Context, busStopID int64) ([]internal.BusLine, error)
+	ListNearbyBusStops(ctx context.Context, latitude, longitude, radius float64, limit int) ([]internal.NearbyBusStop, error)
 }
 
 type busRepository struct {
@@ -39,9 +51,9 @@ func (r *busRepository) GetBusLine(ctx context.Context, busLineID int64) (intern
 
 	if err :=
 		r.Conn.QueryRow(ctx,
-			`SELECT name, bus_init, bus_end WHERE id = $1
-				AND deleted_at IS NULL;`, busLineID).Scan(&busLine.Name, busLine.BusInit.ID,
-			&busLine.BusEnd.ID); err != nil {
+			`SELECT name, bus_init, bus_end, created_at FROM bus_line WHERE id = $1
+				AND deleted_at IS NULL;`, busLineID).Scan(&busLine.Name, &busLine.BusInit.ID,
+			&busLine.BusEnd.ID, &busLine.CreatedAt); err != nil {
 		return internal.BusLine{}, err
 	}
 	// BUSCAR DETALHES DOS PONTOS DE PARADA NA ROTA
@@ -52,6 +64,18 @@ func (r *busRepository) GetBusLine(ctx context.Context, busLineID int64) (intern
 		return internal.BusLine{}, nil
 	}
 
+	stops, err := r.ListBusLineStops(ctx, busLineID)
+	if err != nil {
+		return internal.BusLine{}, err
+	}
+	busLine.Stops = stops
+
+	schedules, err := r.ListBusSchedules(ctx, busLineID)
+	if err != nil {
+		return internal.BusLine{}, err
+	}
+	busLine.Schedules = schedules
+
 	return busLine, nil
 }
 
@@ -75,7 +99,385 @@ func (r *busRepository) GetBusStop(ctx context.Context, busStopID int64) (intern
 			`SELECT name, latitude, longitude FROM bus_stop WHERE id= $1
 				AND deleted_at IS NULL;`, busStopID).Scan(&busStop.Name,
 			&busStop.Latitude, &busStop.Longitude); err != nil {
+		if err == pgx.ErrNoRows {
+			return internal.BusStop{}, nil
+		}
 		return internal.BusStop{}, err
 	}
 	return busStop, nil
 }
+
+func (r *busRepository) ListBusSchedules(ctx context.Context, busLineID int64) ([]internal.BusSchedules, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	scheduleChannel := make(chan []internal.BusSchedules)
+	errorChannel := make(chan error)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT id, day_of_week, start_time, end_time, created_at FROM bus_schedule
+					WHERE bus_line_id = $1 AND deleted_at IS NULL
+					ORDER BY day_of_week, start_time;`, busLineID)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		schedules := []internal.BusSchedules{}
+		for rows.Next() {
+			s := internal.BusSchedules{BusLineID: busLineID}
+			if err := rows.Scan(
+				&s.ID,
+				&s.DayOfWeek,
+				&s.StartTime,
+				&s.EndTime,
+				&s.CreatedAt); err != nil {
+				errorChannel <- err
+				return
+			}
+			schedules = append(schedules, s)
+		}
+		scheduleChannel <- schedules
+	}()
+
+	select {
+	case schedules := <-scheduleChannel:
+		return schedules, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *busRepository) InsertBusSchedules(ctx context.Context, busLineID int64, schedules []internal.BusSchedules) ([]int64, error) {
+	tx, err := r.Conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	ids, err := insertBusSchedules(ctx, tx, busLineID, schedules)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *busRepository) ReplaceBusSchedules(ctx context.Context, busLineID int64, schedules []internal.BusSchedules) ([]int64, error) {
+	tx, err := r.Conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err :=
+		tx.Exec(
+			ctx,
+			`UPDATE bus_schedule SET deleted_at = $2 WHERE bus_line_id = $1
+				AND deleted_at IS NULL;`, busLineID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	ids, err := insertBusSchedules(ctx, tx, busLineID, schedules)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *busRepository) DeleteBusSchedule(ctx context.Context, busLineID, scheduleID int64) (bool, error) {
+	result, err :=
+		r.Conn.Exec(
+			ctx,
+			`UPDATE bus_schedule SET deleted_at = $3 WHERE bus_line_id = $1 AND id = $2
+				AND deleted_at IS NULL;`, busLineID, scheduleID, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	if result.RowsAffected() == internal.ZERO {
+		return false, nil
+	} else {
+		return true, nil
+	}
+}
+
+func (r *busRepository) ListNextDepartures(ctx context.Context, busLineID int64, dayOfWeek string, after time.Time, limit int) ([]internal.BusSchedules, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	scheduleChannel := make(chan []internal.BusSchedules)
+	errorChannel := make(chan error)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT id, day_of_week, start_time, end_time, created_at FROM bus_schedule
+					WHERE bus_line_id = $1 AND day_of_week = $2 AND start_time >= $3
+						AND deleted_at IS NULL
+					ORDER BY start_time LIMIT $4;`, busLineID, dayOfWeek, after, limit)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		schedules := []internal.BusSchedules{}
+		for rows.Next() {
+			s := internal.BusSchedules{BusLineID: busLineID}
+			if err := rows.Scan(
+				&s.ID,
+				&s.DayOfWeek,
+				&s.StartTime,
+				&s.EndTime,
+				&s.CreatedAt); err != nil {
+				errorChannel <- err
+				return
+			}
+			schedules = append(schedules, s)
+		}
+		scheduleChannel <- schedules
+	}()
+
+	select {
+	case schedules := <-scheduleChannel:
+		return schedules, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func insertBusSchedules(ctx context.Context, tx pgx.Tx, busLineID int64, schedules []internal.BusSchedules) ([]int64, error) {
+	ids := make([]int64, internal.ZERO, len(schedules))
+	for _, schedule := range schedules {
+		var id int64
+		if err :=
+			tx.QueryRow(
+				ctx,
+				`INSERT INTO bus_schedule (bus_line_id, day_of_week, start_time, end_time)
+					VALUES ($1, $2, $3, $4) RETURNING id;`, busLineID, schedule.DayOfWeek,
+				schedule.StartTime, schedule.EndTime).Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (r *busRepository) ListBusLineStops(ctx context.Context, busLineID int64) ([]internal.BusLineStop, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	stopChannel := make(chan []internal.BusLineStop)
+	errorChannel := make(chan error)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT ls.id, ls.sequence, ls.travel_time_offset, s.id, s.name, s.latitude, s.longitude
+					FROM bus_line_stop ls JOIN bus_stop s ON s.id = ls.bus_stop_id
+					WHERE ls.bus_line_id = $1 AND ls.deleted_at IS NULL AND s.deleted_at IS NULL
+					ORDER BY ls.sequence;`, busLineID)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		stops := []internal.BusLineStop{}
+		for rows.Next() {
+			s := internal.BusLineStop{BusLineID: busLineID}
+			if err := rows.Scan(
+				&s.ID,
+				&s.Sequence,
+				&s.TravelTimeOffset,
+				&s.BusStop.ID,
+				&s.BusStop.Name,
+				&s.BusStop.Latitude,
+				&s.BusStop.Longitude); err != nil {
+				errorChannel <- err
+				return
+			}
+			stops = append(stops, s)
+		}
+		stopChannel <- stops
+	}()
+
+	select {
+	case stops := <-stopChannel:
+		return stops, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *busRepository) ReplaceBusLineStops(ctx context.Context, busLineID int64, stops []internal.BusLineStop) ([]int64, error) {
+	tx, err := r.Conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err :=
+		tx.Exec(
+			ctx,
+			`UPDATE bus_line_stop SET deleted_at = $2 WHERE bus_line_id = $1
+				AND deleted_at IS NULL;`, busLineID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, internal.ZERO, len(stops))
+	for _, stop := range stops {
+		var id int64
+		if err :=
+			tx.QueryRow(
+				ctx,
+				`INSERT INTO bus_line_stop (bus_line_id, bus_stop_id, sequence, travel_time_offset)
+					VALUES ($1, $2, $3, $4) RETURNING id;`, busLineID, stop.BusStop.ID, stop.Sequence,
+				stop.TravelTimeOffset).Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *busRepository) ListBusLinesByStop(ctx context.Context, busStopID int64) ([]internal.BusLine, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	lineChannel := make(chan []internal.BusLine)
+	errorChannel := make(chan error)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT DISTINCT l.id, l.name, l.created_at,
+					i.id, i.name, i.latitude, i.longitude,
+					e.id, e.name, e.latitude, e.longitude
+				FROM bus_line l
+					JOIN bus_stop i ON i.id = l.bus_init
+					JOIN bus_stop e ON e.id = l.bus_end
+					LEFT JOIN bus_line_stop ls ON ls.bus_line_id = l.id AND ls.deleted_at IS NULL
+				WHERE l.deleted_at IS NULL
+					AND (ls.bus_stop_id = $1 OR l.bus_init = $1 OR l.bus_end = $1)
+				ORDER BY l.name;`, busStopID)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		lines := []internal.BusLine{}
+		for rows.Next() {
+			l := internal.BusLine{}
+			if err := rows.Scan(
+				&l.ID,
+				&l.Name,
+				&l.CreatedAt,
+				&l.BusInit.ID,
+				&l.BusInit.Name,
+				&l.BusInit.Latitude,
+				&l.BusInit.Longitude,
+				&l.BusEnd.ID,
+				&l.BusEnd.Name,
+				&l.BusEnd.Latitude,
+				&l.BusEnd.Longitude); err != nil {
+				errorChannel <- err
+				return
+			}
+			lines = append(lines, l)
+		}
+		lineChannel <- lines
+	}()
+
+	select {
+	case lines := <-lineChannel:
+		return lines, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *busRepository) ListNearbyBusStops(ctx context.Context, latitude, longitude, radius float64, limit int) ([]internal.NearbyBusStop, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	stopChannel := make(chan []internal.NearbyBusStop)
+	errorChannel := make(chan error)
+
+	minLat, minLon, maxLat, maxLon := utils.BoundingBox(latitude, longitude, radius)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT id, name, latitude, longitude, created_at, distance FROM (
+					SELECT id, name, latitude, longitude, created_at, `+utils.DISTANCE_SQL+` AS distance
+					FROM bus_stop WHERE latitude BETWEEN $3 AND $4 AND longitude BETWEEN $5 AND $6
+						AND deleted_at IS NULL) AS nearby
+				WHERE distance <= $7 ORDER BY distance LIMIT $8;`, latitude, longitude,
+				minLat, maxLat, minLon, maxLon, radius, limit)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		stops := []internal.NearbyBusStop{}
+		for rows.Next() {
+			s := internal.NearbyBusStop{}
+			if err := rows.Scan(
+				&s.ID,
+				&s.Name,
+				&s.Latitude,
+				&s.Longitude,
+				&s.CreatedAt,
+				&s.Distance); err != nil {
+				errorChannel <- err
+				return
+			}
+			stops = append(stops, s)
+		}
+		stopChannel <- stops
+	}()
+
+	select {
+	case stops := <-stopChannel:
+		return stops, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -2,8 +2,20 @@ package bus
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+const (
+	NEXT_DEPARTURES_LIMIT = 10
+	DEFAULT_NEARBY_RADIUS = 500.0
+	MAX_NEARBY_RADIUS     = 5000.0
+	NEARBY_LIMIT          = 20
 )
 
 type IBusService interface {
@@ -11,6 +23,15 @@ type IBusService interface {
 	InsertBusStop(ctx context.Context, busStop internal.BusStop) (int64, error)
 	GetBusLine(ctx context.Context, busLineID int64) (internal.BusLine, error)
 	GetBusStop(ctx context.Context, busStopID int64) (internal.BusStop, error)
+	ListBusSchedules(ctx context.Context, busLineID int64) ([]internal.BusSchedules, error)
+	AddBusSchedules(ctx context.Context, busLineID int64, schedules []internal.BusSchedules) ([]int64, error)
+	ReplaceBusSchedules(ctx context.Context, busLineID int64, schedules []internal.BusSchedules) ([]int64, error)
+	DeleteBusSchedule(ctx context.Context, busLineID, scheduleID int64) (bool, error)
+	NextDepartures(ctx context.Context, busLineID int64, dayOfWeek string, after time.Time) ([]internal.BusSchedules, error)
+	ListBusLineStops(ctx context.Context, busLineID int64) ([]internal.BusLineStop, error)
+	SetBusLineStops(ctx context.Context, busLineID int64, stops []internal.BusLineStop) ([]int64, error)
+	ListBusLinesByStop(ctx context.Context, busStopID int64) ([]internal.BusLine, error)
+	ListNearbyBusStops(ctx context.Context, latitude, longitude, radius float64) ([]internal.NearbyBusStop, error)
 }
 
 type busService struct {
@@ -36,3 +57,203 @@ func (s *busService) GetBusLine(ctx context.Context, busLineID int64) (internal.
 func (s *busService) GetBusStop(ctx context.Context, busStopID int64) (internal.BusStop, error) {
 	return s.repository.GetBusStop(ctx, busStopID)
 }
+
+func (s *busService) ListBusSchedules(ctx context.Context, busLineID int64) ([]internal.BusSchedules, error) {
+	if busLineID <= internal.ZERO {
+		return []internal.BusSchedules{}, ErrBusLineIDInvalid
+	}
+	return s.repository.ListBusSchedules(ctx, busLineID)
+}
+
+func (s *busService) AddBusSchedules(ctx context.Context, busLineID int64, schedules []internal.BusSchedules) ([]int64, error) {
+	if busLineID <= internal.ZERO {
+		return nil, ErrBusLineIDInvalid
+	}
+
+	if len(schedules) == internal.ZERO {
+		return nil, ErrBusSchedulesEmpty
+	}
+
+	normalized, err := normalizeSchedules(schedules)
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.InsertBusSchedules(ctx, busLineID, normalized)
+}
+
+func (s *busService) ReplaceBusSchedules(ctx context.Context, busLineID int64, schedules []internal.BusSchedules) ([]int64, error) {
+	if busLineID <= internal.ZERO {
+		return nil, ErrBusLineIDInvalid
+	}
+
+	normalized, err := normalizeSchedules(schedules)
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.ReplaceBusSchedules(ctx, busLineID, normalized)
+}
+
+func (s *busService) DeleteBusSchedule(ctx context.Context, busLineID, scheduleID int64) (bool, error) {
+	if busLineID <= internal.ZERO {
+		return false, ErrBusLineIDInvalid
+	}
+
+	if scheduleID <= internal.ZERO {
+		return false, ErrBusScheduleIDInvalid
+	}
+	return s.repository.DeleteBusSchedule(ctx, busLineID, scheduleID)
+}
+
+func (s *busService) NextDepartures(ctx context.Context, busLineID int64, dayOfWeek string, after time.Time) ([]internal.BusSchedules, error) {
+	if busLineID <= internal.ZERO {
+		return []internal.BusSchedules{}, ErrBusLineIDInvalid
+	}
+
+	now := time.Now()
+	if after.IsZero() {
+		after = now
+	}
+
+	day := now.Weekday()
+	if dayOfWeek != internal.EMPTY {
+		var err error
+		if day, err = ParseDayOfWeek(dayOfWeek); err != nil {
+			return []internal.BusSchedules{}, err
+		}
+	}
+
+	return s.repository.ListNextDepartures(ctx, busLineID, day.String(), TimeOfDay(after), NEXT_DEPARTURES_LIMIT)
+}
+
+func (s *busService) ListBusLineStops(ctx context.Context, busLineID int64) ([]internal.BusLineStop, error) {
+	if busLineID <= internal.ZERO {
+		return []internal.BusLineStop{}, ErrBusLineIDInvalid
+	}
+	return s.repository.ListBusLineStops(ctx, busLineID)
+}
+
+// SetBusLineStops replaces the ordered stops of a line. When no sequence is
+// informed, the stops are numbered in the order they were sent.
+func (s *busService) SetBusLineStops(ctx context.Context, busLineID int64, stops []internal.BusLineStop) ([]int64, error) {
+	if busLineID <= internal.ZERO {
+		return nil, ErrBusLineIDInvalid
+	}
+
+	if len(stops) < 2 {
+		return nil, ErrBusLineStopsTooFew
+	}
+
+	autoSequence := true
+	for _, stop := range stops {
+		if stop.Sequence != internal.ZERO {
+			autoSequence = false
+			break
+		}
+	}
+
+	ordered := make([]internal.BusLineStop, internal.ZERO, len(stops))
+	sequences := map[int]bool{}
+	for i, stop := range stops {
+		if autoSequence {
+			stop.Sequence = i + 1
+		}
+
+		if stop.Sequence <= internal.ZERO || sequences[stop.Sequence] {
+			return nil, ErrBusLineStopSequenceInvalid
+		}
+		sequences[stop.Sequence] = true
+
+		if stop.BusStop.ID <= internal.ZERO {
+			return nil, ErrBusStopIDInvalid
+		}
+
+		if stop.TravelTimeOffset != nil && *stop.TravelTimeOffset < internal.ZERO {
+			return nil, ErrBusLineStopOffsetInvalid
+		}
+
+		stop.BusLineID = busLineID
+		ordered = append(ordered, stop)
+	}
+
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].Sequence < ordered[j].Sequence
+	})
+
+	return s.repository.ReplaceBusLineStops(ctx, busLineID, ordered)
+}
+
+func (s *busService) ListBusLinesByStop(ctx context.Context, busStopID int64) ([]internal.BusLine, error) {
+	if busStopID <= internal.ZERO {
+		return []internal.BusLine{}, ErrBusStopIDInvalid
+	}
+	return s.repository.ListBusLinesByStop(ctx, busStopID)
+}
+
+func (s *busService) ListNearbyBusStops(ctx context.Context, latitude, longitude, radius float64) ([]internal.NearbyBusStop, error) {
+	if !utils.ValidCoordinates(latitude, longitude) {
+		return []internal.NearbyBusStop{}, ErrBusStopLocationInvalid
+	}
+
+	if radius <= internal.ZERO {
+		radius = DEFAULT_NEARBY_RADIUS
+	} else if radius > MAX_NEARBY_RADIUS {
+		return []internal.NearbyBusStop{}, ErrBusStopRadiusInvalid
+	}
+
+	return s.repository.ListNearbyBusStops(ctx, latitude, longitude, radius, NEARBY_LIMIT)
+}
+
+// ParseDayOfWeek accepts English week day names in any case, such as
+// "monday" or "MONDAY", which are stored as time.Weekday.String().
+func ParseDayOfWeek(value string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(strings.TrimSpace(value), day.String()) {
+			return day, nil
+		}
+	}
+	return time.Sunday, ErrBusScheduleDayInvalid
+}
+
+// TimeOfDay drops the date of t, the same way pgx reads TIME columns.
+func TimeOfDay(t time.Time) time.Time {
+	return time.Date(2000, time.January, 1, t.Hour(), t.Minute(), t.Second(), internal.ZERO, time.UTC)
+}
+
+func normalizeSchedules(schedules []internal.BusSchedules) ([]internal.BusSchedules, error) {
+	normalized := make([]internal.BusSchedules, internal.ZERO, len(schedules))
+	for _, schedule := range schedules {
+		day, err := ParseDayOfWeek(schedule.DayOfWeek)
+		if err != nil {
+			return nil, err
+		}
+
+		if schedule.StartTime == nil || schedule.EndTime == nil {
+			return nil, ErrBusScheduleTimeEmpty
+		}
+
+		start := TimeOfDay(*schedule.StartTime)
+		end := TimeOfDay(*schedule.EndTime)
+
+		schedule.DayOfWeek = day.String()
+		schedule.StartTime = &start
+		schedule.EndTime = &end
+		normalized = append(normalized, schedule)
+	}
+
+	return normalized, nil
+}
+
+var (
+	ErrBusLineIDInvalid       = errors.New("bus line id is empty or negative")
+	ErrBusScheduleIDInvalid   = errors.New("bus schedule id is empty or negative")
+	ErrBusStopIDInvalid       = errors.New("bus stop id is empty or negative")
+	ErrBusSchedulesEmpty      = errors.New("bus schedules list is empty")
+	ErrBusScheduleDayInvalid  = errors.New("bus schedule day of week must be an english week day name, example: Monday")
+	ErrBusScheduleTimeEmpty   = errors.New("bus schedule start and end time are required")
+	ErrBusStopLocationInvalid = errors.New("bus stop latitude or longitude out of range")
+	ErrBusStopRadiusInvalid   = errors.New("bus stop search radius must be at most 5000 meters")
+
+	ErrBusLineStopsTooFew         = errors.New("bus line must have at least 2 stops")
+	ErrBusLineStopSequenceInvalid = errors.New("bus line stop sequences must be positive and unique")
+	ErrBusLineStopOffsetInvalid   = errors.New("bus line stop travel time offset cannot be negative")
+)
@@ -0,0 +1,223 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+type mockBusRepository struct {
+	IBusRepository
+	InsertBusSchedulesFunc  func(ctx context.Context, busLineID int64, schedules []internal.BusSchedules) ([]int64, error)
+	ListNextDeparturesFunc  func(ctx context.Context, busLineID int64, dayOfWeek string, after time.Time, limit int) ([]internal.BusSchedules, error)
+	ReplaceBusLineStopsFunc func(ctx context.Context, busLineID int64, stops []internal.BusLineStop) ([]int64, error)
+}
+
+func (m *mockBusRepository) InsertBusSchedules(ctx context.Context, busLineID int64, schedules []internal.BusSchedules) ([]int64, error) {
+	if m.InsertBusSchedulesFunc != nil {
+		return m.InsertBusSchedulesFunc(ctx, busLineID, schedules)
+	}
+	return nil, ErrInsertBusSchedulesFuncNotImplemented
+}
+
+func (m *mockBusRepository) ListNextDepartures(ctx context.Context, busLineID int64, dayOfWeek string, after time.Time, limit int) ([]internal.BusSchedules, error) {
+	if m.ListNextDeparturesFunc != nil {
+		return m.ListNextDeparturesFunc(ctx, busLineID, dayOfWeek, after, limit)
+	}
+	return nil, ErrListNextDeparturesFuncNotImplemented
+}
+
+func (m *mockBusRepository) ReplaceBusLineStops(ctx context.Context, busLineID int64, stops []internal.BusLineStop) ([]int64, error) {
+	if m.ReplaceBusLineStopsFunc != nil {
+		return m.ReplaceBusLineStopsFunc(ctx, busLineID, stops)
+	}
+	return nil, ErrReplaceBusLineStopsFuncNotImplemented
+}
+
+func TestAddBusSchedules(t *testing.T) {
+	start := time.Date(2025, time.March, 10, 8, 30, 0, 0, time.UTC)
+	end := start.Add(50 * time.Minute)
+
+	tests := []struct {
+		name      string
+		busLineID int64
+		input     []internal.BusSchedules
+		wantDay   string
+		wantError error
+	}{
+		{
+			name:      "Horário salvo com dia normalizado",
+			busLineID: 1,
+			input:     []internal.BusSchedules{{DayOfWeek: "monday", StartTime: &start, EndTime: &end}},
+			wantDay:   "Monday",
+		},
+		{
+			name:      "Dia da semana inválido",
+			busLineID: 1,
+			input:     []internal.BusSchedules{{DayOfWeek: "segunda", StartTime: &start, EndTime: &end}},
+			wantError: ErrBusScheduleDayInvalid,
+		},
+		{
+			name:      "Horário sem fim",
+			busLineID: 1,
+			input:     []internal.BusSchedules{{DayOfWeek: "Monday", StartTime: &start}},
+			wantError: ErrBusScheduleTimeEmpty,
+		},
+		{
+			name:      "Lista vazia",
+			busLineID: 1,
+			input:     []internal.BusSchedules{},
+			wantError: ErrBusSchedulesEmpty,
+		},
+		{
+			name:      "Linha inválida",
+			busLineID: internal.ZERO,
+			input:     []internal.BusSchedules{{DayOfWeek: "Monday", StartTime: &start, EndTime: &end}},
+			wantError: ErrBusLineIDInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockBusRepository{
+				InsertBusSchedulesFunc: func(ctx context.Context, busLineID int64, schedules []internal.BusSchedules) ([]int64, error) {
+					for _, schedule := range schedules {
+						if schedule.DayOfWeek != tt.wantDay {
+							t.Errorf("[%s] Dia esperado: %s, recebido: %s", tt.name, tt.wantDay, schedule.DayOfWeek)
+						}
+						if schedule.StartTime.Year() != 2000 || schedule.StartTime.Hour() != 8 {
+							t.Errorf("[%s] Horário não normalizado: %v", tt.name, schedule.StartTime)
+						}
+					}
+					return []int64{1}, nil
+				},
+			}
+			service := NewBusService(mockRepo)
+
+			_, err := service.AddBusSchedules(context.Background(), tt.busLineID, tt.input)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+		})
+	}
+}
+
+func TestNextDepartures(t *testing.T) {
+	after := time.Date(0, time.January, 1, 7, 45, 0, 0, time.UTC)
+
+	mockRepo := &mockBusRepository{
+		ListNextDeparturesFunc: func(ctx context.Context, busLineID int64, dayOfWeek string, after time.Time, limit int) ([]internal.BusSchedules, error) {
+			if dayOfWeek != "Saturday" {
+				t.Errorf("Dia esperado: Saturday, recebido: %s", dayOfWeek)
+			}
+			if after.Hour() != 7 || after.Minute() != 45 {
+				t.Errorf("Horário esperado: 07:45, recebido: %s", after.Format("15:04"))
+			}
+			if limit != NEXT_DEPARTURES_LIMIT {
+				t.Errorf("Limite esperado: %d, recebido: %d", NEXT_DEPARTURES_LIMIT, limit)
+			}
+			return []internal.BusSchedules{}, nil
+		},
+	}
+	service := NewBusService(mockRepo)
+
+	if _, err := service.NextDepartures(context.Background(), 1, "SATURDAY", after); err != nil {
+		t.Errorf("Erro inesperado: %v", err)
+	}
+
+	if _, err := service.NextDepartures(context.Background(), 1, "sabado", after); !errors.Is(err, ErrBusScheduleDayInvalid) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrBusScheduleDayInvalid, err)
+	}
+}
+
+func TestSetBusLineStops(t *testing.T) {
+	offset := int64(120)
+	negative := int64(-1)
+
+	tests := []struct {
+		name          string
+		input         []internal.BusLineStop
+		wantSequences []int
+		wantError     error
+	}{
+		{
+			name: "Sequência automática",
+			input: []internal.BusLineStop{
+				{BusStop: internal.BusStop{ID: 7}},
+				{BusStop: internal.BusStop{ID: 3}, TravelTimeOffset: &offset},
+				{BusStop: internal.BusStop{ID: 5}},
+			},
+			wantSequences: []int{1, 2, 3},
+		},
+		{
+			name: "Sequência informada fora de ordem",
+			input: []internal.BusLineStop{
+				{BusStop: internal.BusStop{ID: 5}, Sequence: 30},
+				{BusStop: internal.BusStop{ID: 7}, Sequence: 10},
+				{BusStop: internal.BusStop{ID: 3}, Sequence: 20},
+			},
+			wantSequences: []int{10, 20, 30},
+		},
+		{
+			name: "Sequência repetida",
+			input: []internal.BusLineStop{
+				{BusStop: internal.BusStop{ID: 5}, Sequence: 1},
+				{BusStop: internal.BusStop{ID: 7}, Sequence: 1},
+			},
+			wantError: ErrBusLineStopSequenceInvalid,
+		},
+		{
+			name:      "Apenas uma parada",
+			input:     []internal.BusLineStop{{BusStop: internal.BusStop{ID: 5}}},
+			wantError: ErrBusLineStopsTooFew,
+		},
+		{
+			name: "Tempo de percurso negativo",
+			input: []internal.BusLineStop{
+				{BusStop: internal.BusStop{ID: 5}},
+				{BusStop: internal.BusStop{ID: 7}, TravelTimeOffset: &negative},
+			},
+			wantError: ErrBusLineStopOffsetInvalid,
+		},
+		{
+			name: "Parada sem ID",
+			input: []internal.BusLineStop{
+				{BusStop: internal.BusStop{ID: 5}},
+				{BusStop: internal.BusStop{}},
+			},
+			wantError: ErrBusStopIDInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockBusRepository{
+				ReplaceBusLineStopsFunc: func(ctx context.Context, busLineID int64, stops []internal.BusLineStop) ([]int64, error) {
+					ids := []int64{}
+					for i, stop := range stops {
+						if stop.Sequence != tt.wantSequences[i] {
+							t.Errorf("[%s] Sequência esperada: %d, recebida: %d", tt.name, tt.wantSequences[i], stop.Sequence)
+						}
+						ids = append(ids, int64(i+1))
+					}
+					return ids, nil
+				},
+			}
+			service := NewBusService(mockRepo)
+
+			_, err := service.SetBusLineStops(context.Background(), 1, tt.input)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+		})
+	}
+}
+
+var (
+	ErrReplaceBusLineStopsFuncNotImplemented = errors.New("ReplaceBusLineStopsFunc not implemented")
+	ErrInsertBusSchedulesFuncNotImplemented  = errors.New("InsertBusSchedulesFunc not implemented")
+	ErrListNextDeparturesFuncNotImplemented  = errors.New("ListNextDeparturesFunc not implemented")
+)
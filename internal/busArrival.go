@@ -0,0 +1,20 @@
+package internal
+
+import "time"
+
+type ArrivalSource int
+
+const (
+	LIVE ArrivalSource = iota
+	SCHEDULED
+)
+
+type BusArrival struct {
+	LineID		int64
+	LineName	string
+	BusStopID	int64
+	ExpectedAt	time.Time
+	Source		ArrivalSource
+	Confidence	float64
+	Riders		int
+}
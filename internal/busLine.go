@@ -7,6 +7,7 @@ type BusLine struct {
 	Name      string
 	BusInit   BusStop
 	BusEnd    BusStop
+	Stops     []BusLineStop
 	Schedules []BusSchedules
 	CreatedAt time.Time
 	UpdatedAt *time.Time
@@ -0,0 +1,14 @@
+package internal
+
+import "time"
+
+type BusLineStop struct {
+	ID					int64
+	BusLineID			int64
+	BusStop				BusStop
+	Sequence			int
+	TravelTimeOffset	*int64
+	CreatedAt			time.Time
+	UpdatedAt			*time.Time
+	DeletedAt			*time.Time
+}
@@ -0,0 +1,11 @@
+package internal
+
+import "time"
+
+type BusPosition struct {
+	LineID		int64
+	Latitude	float64
+	Longitude	float64
+	Riders		int
+	LastSeen	time.Time
+}
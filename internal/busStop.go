@@ -11,3 +11,8 @@ type BusStop struct {
 	UpdatedAt *time.Time
 	DeletedAt *time.Time
 }
+
+type NearbyBusStop struct {
+	BusStop
+	Distance float64
+}
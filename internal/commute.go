@@ -0,0 +1,19 @@
+package internal
+
+import "time"
+
+type CommuteLine struct {
+	LineID		int64
+	LineName	string
+	BoardStop	NearbyBusStop
+	AlightStop	NearbyBusStop
+	Departures	[]time.Time
+}
+
+type Commute struct {
+	From		SavedPlace
+	To			SavedPlace
+	FromStops	[]NearbyBusStop
+	ToStops		[]NearbyBusStop
+	Lines		[]CommuteLine
+}
@@ -0,0 +1,122 @@
+package commute
+
+import (
+	"context"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ICommuteRepository interface {
+	SavePlace(ctx context.Context, place internal.SavedPlace) (int64, error)
+	GetPlaceByName(ctx context.Context, userID int64, name string) (internal.SavedPlace, error)
+	ListPlaces(ctx context.Context, userID int64) ([]internal.SavedPlace, error)
+	DeletePlace(ctx context.Context, userID, placeID int64) (bool, error)
+}
+
+type commuteRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewCommuteRepository(connection *pgxpool.Pool) ICommuteRepository {
+	return &commuteRepository{Conn: connection}
+}
+
+// SavePlace creates the place or replaces the coordinates of the place with
+// the same name, ignoring case, restoring it if it was deleted.
+func (r *commuteRepository) SavePlace(ctx context.Context, place internal.SavedPlace) (int64, error) {
+	if err :=
+		r.Conn.QueryRow(
+			ctx,
+			`INSERT INTO saved_place (user_id, name, latitude, longitude) VALUES ($1, $2, $3, $4)
+				ON CONFLICT (user_id, (LOWER(name))) DO UPDATE SET name = EXCLUDED.name,
+					latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude,
+					updated_at = NOW(), deleted_at = NULL
+				RETURNING id;`, place.UserID, place.Name, place.Latitude, place.Longitude).Scan(&place.ID); err != nil {
+		return internal.ZERO, err
+	}
+
+	return place.ID, nil
+}
+
+func (r *commuteRepository) GetPlaceByName(ctx context.Context, userID int64, name string) (internal.SavedPlace, error) {
+	place := internal.SavedPlace{UserID: userID}
+	if err :=
+		r.Conn.QueryRow(
+			ctx,
+			`SELECT id, name, latitude, longitude, created_at, updated_at FROM saved_place
+				WHERE user_id = $1 AND LOWER(name) = LOWER($2) AND deleted_at IS NULL;`, userID, name).Scan(&place.ID,
+			&place.Name, &place.Latitude, &place.Longitude, &place.CreatedAt, &place.UpdatedAt); err != nil {
+
+		if err == pgx.ErrNoRows {
+			return internal.SavedPlace{}, nil
+		}
+
+		return internal.SavedPlace{}, err
+	}
+
+	return place, nil
+}
+
+func (r *commuteRepository) ListPlaces(ctx context.Context, userID int64) ([]internal.SavedPlace, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	placeChannel := make(chan []internal.SavedPlace)
+	errorChannel := make(chan error)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT id, user_id, name, latitude, longitude, created_at, updated_at FROM saved_place
+					WHERE user_id = $1 AND deleted_at IS NULL ORDER BY name;`, userID)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		places := []internal.SavedPlace{}
+		for rows.Next() {
+			p := internal.SavedPlace{}
+			if err := rows.Scan(
+				&p.ID,
+				&p.UserID,
+				&p.Name,
+				&p.Latitude,
+				&p.Longitude,
+				&p.CreatedAt,
+				&p.UpdatedAt); err != nil {
+				errorChannel <- err
+				return
+			}
+			places = append(places, p)
+		}
+		placeChannel <- places
+	}()
+
+	select {
+	case places := <-placeChannel:
+		return places, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *commuteRepository) DeletePlace(ctx context.Context, userID, placeID int64) (bool, error) {
+	result, err :=
+		r.Conn.Exec(
+			ctx,
+			`UPDATE saved_place SET deleted_at = NOW()
+				WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;`, placeID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() > internal.ZERO, nil
+}
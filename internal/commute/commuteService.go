@@ -0,0 +1,279 @@
+package commute
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/address"
+	"github.com/amarantec/move-easy/internal/bus"
+	"github.com/amarantec/move-easy/internal/eta"
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+const (
+	DEFAULT_FROM_PLACE = "Home"
+	DEFAULT_TO_PLACE   = "Work"
+	// COMMUTE_STOP_RADIUS is how far in meters a rider is expected to walk to
+	// or from a stop, and COMMUTE_STOP_LIMIT how many stops are tried at each
+	// end.
+	COMMUTE_STOP_RADIUS      = 500.0
+	COMMUTE_STOP_LIMIT       = 5
+	COMMUTE_DEPARTURES_LIMIT = 3
+	// COMMUTE_DEPARTURES_WINDOW is how far ahead departures are looked for,
+	// so late at night the first trips of the next day are listed.
+	COMMUTE_DEPARTURES_WINDOW = 24 * time.Hour
+)
+
+type ICommuteService interface {
+	SavePlace(ctx context.Context, place internal.SavedPlace) (int64, error)
+	ListPlaces(ctx context.Context, userID int64) ([]internal.SavedPlace, error)
+	DeletePlace(ctx context.Context, userID, placeID int64) (bool, error)
+	MyCommute(ctx context.Context, userID int64, from, to string) (internal.Commute, error)
+}
+
+type commuteService struct {
+	repository     ICommuteRepository
+	busService     bus.IBusService
+	addressService address.IAddressService
+}
+
+func NewCommuteService(repo ICommuteRepository, busService bus.IBusService, addressService address.IAddressService) ICommuteService {
+	return &commuteService{repository: repo, busService: busService, addressService: addressService}
+}
+
+func (s *commuteService) SavePlace(ctx context.Context, place internal.SavedPlace) (int64, error) {
+	place.Name = strings.TrimSpace(place.Name)
+	if valid, err := validateSavedPlace(place); err != nil || !valid {
+		return internal.ZERO, err
+	}
+	return s.repository.SavePlace(ctx, place)
+}
+
+func (s *commuteService) ListPlaces(ctx context.Context, userID int64) ([]internal.SavedPlace, error) {
+	if userID <= internal.ZERO {
+		return []internal.SavedPlace{}, ErrSavedPlaceUserIDInvalid
+	}
+	return s.repository.ListPlaces(ctx, userID)
+}
+
+func (s *commuteService) DeletePlace(ctx context.Context, userID, placeID int64) (bool, error) {
+	if userID <= internal.ZERO {
+		return false, ErrSavedPlaceUserIDInvalid
+	}
+
+	if placeID <= internal.ZERO {
+		return false, ErrSavedPlaceIDInvalid
+	}
+
+	return s.repository.DeletePlace(ctx, userID, placeID)
+}
+
+// MyCommute finds the lines that stop near the from place and, further along
+// their route, near the to place, keeping for each line the pair of stops with
+// the shortest total walk and its next departures from the boarding stop.
+func (s *commuteService) MyCommute(ctx context.Context, userID int64, from, to string) (internal.Commute, error) {
+	if userID <= internal.ZERO {
+		return internal.Commute{}, ErrSavedPlaceUserIDInvalid
+	}
+
+	if from = strings.TrimSpace(from); from == internal.EMPTY {
+		from = DEFAULT_FROM_PLACE
+	}
+
+	if to = strings.TrimSpace(to); to == internal.EMPTY {
+		to = DEFAULT_TO_PLACE
+	}
+
+	commute := internal.Commute{Lines: []internal.CommuteLine{}}
+	var err error
+	if commute.From, err = s.place(ctx, userID, from); err != nil {
+		return internal.Commute{}, err
+	}
+
+	if commute.To, err = s.place(ctx, userID, to); err != nil {
+		return internal.Commute{}, err
+	}
+
+	if commute.FromStops, err = s.nearbyStops(ctx, commute.From); err != nil {
+		return internal.Commute{}, err
+	}
+
+	if commute.ToStops, err = s.nearbyStops(ctx, commute.To); err != nil {
+		return internal.Commute{}, err
+	}
+
+	alightStops := map[int64]internal.NearbyBusStop{}
+	for _, stop := range commute.ToStops {
+		alightStops[stop.ID] = stop
+	}
+
+	type candidate struct {
+		line   internal.CommuteLine
+		route  []internal.BusLineStop
+		board  int
+		walked float64
+	}
+	candidates := map[int64]*candidate{}
+	routes := map[int64][]internal.BusLineStop{}
+
+	for _, boardStop := range commute.FromStops {
+		lines, err := s.busService.ListBusLinesByStop(ctx, boardStop.ID)
+		if err != nil {
+			return internal.Commute{}, err
+		}
+
+		for _, line := range lines {
+			route, ok := routes[line.ID]
+			if !ok {
+				lineStops, err := s.busService.ListBusLineStops(ctx, line.ID)
+				if err != nil {
+					return internal.Commute{}, err
+				}
+				route = eta.RouteStops(line, lineStops)
+				routes[line.ID] = route
+			}
+
+			for board := range route {
+				if route[board].BusStop.ID != boardStop.ID {
+					continue
+				}
+
+				for alight := board + 1; alight < len(route); alight++ {
+					alightStop, ok := alightStops[route[alight].BusStop.ID]
+					if !ok {
+						continue
+					}
+
+					walked := boardStop.Distance + alightStop.Distance
+					if c, ok := candidates[line.ID]; ok && c.walked <= walked {
+						continue
+					}
+					candidates[line.ID] = &candidate{
+						line: internal.CommuteLine{
+							LineID:     line.ID,
+							LineName:   line.Name,
+							BoardStop:  boardStop,
+							AlightStop: alightStop,
+						},
+						route:  route,
+						board:  board,
+						walked: walked,
+					}
+				}
+			}
+		}
+	}
+
+	now := time.Now()
+	for _, c := range candidates {
+		toBoard, _ := eta.TravelTime(c.route, internal.ZERO, c.board)
+		if c.line.Departures, err = eta.ScheduledDepartures(ctx, s.busService, c.line.LineID, toBoard, now,
+			now.Add(COMMUTE_DEPARTURES_WINDOW), COMMUTE_DEPARTURES_LIMIT); err != nil {
+			return internal.Commute{}, err
+		}
+		commute.Lines = append(commute.Lines, c.line)
+	}
+
+	sort.Slice(commute.Lines, func(i, j int) bool {
+		a, b := commute.Lines[i], commute.Lines[j]
+		if len(a.Departures) == internal.ZERO || len(b.Departures) == internal.ZERO {
+			return len(a.Departures) > len(b.Departures)
+		}
+		return a.Departures[0].Before(b.Departures[0])
+	})
+
+	return commute, nil
+}
+
+// place reads a saved place by name. When no place was saved with that name,
+// the geocoded address of the user with the same label is used, and Home
+// falls back to the primary address.
+func (s *commuteService) place(ctx context.Context, userID int64, name string) (internal.SavedPlace, error) {
+	place, err := s.repository.GetPlaceByName(ctx, userID, name)
+	if err != nil {
+		return internal.SavedPlace{}, err
+	}
+
+	if place.ID != internal.ZERO {
+		return place, nil
+	}
+
+	if s.addressService == nil {
+		return internal.SavedPlace{}, ErrSavedPlaceNotFound
+	}
+
+	addresses, err := s.addressService.ListAddresses(ctx, userID)
+	if err != nil {
+		return internal.SavedPlace{}, err
+	}
+
+	var found *internal.Address
+	for i, a := range addresses {
+		if a.Latitude == nil || a.Longitude == nil {
+			continue
+		}
+		if strings.EqualFold(a.Label, name) {
+			found = &addresses[i]
+			break
+		}
+		if a.IsPrimary && strings.EqualFold(name, DEFAULT_FROM_PLACE) {
+			found = &addresses[i]
+		}
+	}
+
+	if found == nil {
+		return internal.SavedPlace{}, ErrSavedPlaceNotFound
+	}
+
+	return internal.SavedPlace{
+		UserID:    userID,
+		Name:      name,
+		Latitude:  *found.Latitude,
+		Longitude: *found.Longitude,
+		CreatedAt: found.CreatedAt,
+	}, nil
+}
+
+func (s *commuteService) nearbyStops(ctx context.Context, place internal.SavedPlace) ([]internal.NearbyBusStop, error) {
+	stops, err := s.busService.ListNearbyBusStops(ctx, place.Latitude, place.Longitude, COMMUTE_STOP_RADIUS)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stops) > COMMUTE_STOP_LIMIT {
+		stops = stops[:COMMUTE_STOP_LIMIT]
+	}
+	return stops, nil
+}
+
+func validateSavedPlace(p internal.SavedPlace) (bool, error) {
+	if p.UserID <= internal.ZERO {
+		return false, ErrSavedPlaceUserIDInvalid
+	}
+
+	if p.Name == internal.EMPTY {
+		return false, ErrSavedPlaceNameEmpty
+	} else if utf8.RuneCountInString(p.Name) > 50 {
+		return false, ErrSavedPlaceNameInvalid
+	}
+
+	if !utils.ValidCoordinates(p.Latitude, p.Longitude) || (p.Latitude == internal.ZERO && p.Longitude == internal.ZERO) {
+		return false, ErrSavedPlaceLocationInvalid
+	}
+
+	return true, nil
+}
+
+var (
+	ErrSavedPlaceIDInvalid       = errors.New("saved place id is empty or negative")
+	ErrSavedPlaceUserIDInvalid   = errors.New("saved place user id is empty or negative")
+	ErrSavedPlaceNameEmpty       = errors.New("saved place name is empty")
+	ErrSavedPlaceNameInvalid     = errors.New("saved place name must have at most 50 characters")
+	ErrSavedPlaceLocationInvalid = errors.New("saved place latitude or longitude missing or out of range")
+	ErrSavedPlaceNotFound        = errors.New("saved place not found")
+)
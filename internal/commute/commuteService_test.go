@@ -0,0 +1,190 @@
+package commute
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/address"
+	"github.com/amarantec/move-easy/internal/bus"
+)
+
+type mockCommuteRepository struct {
+	ICommuteRepository
+	places map[string]internal.SavedPlace
+}
+
+func (m *mockCommuteRepository) SavePlace(ctx context.Context, place internal.SavedPlace) (int64, error) {
+	place.ID = int64(len(m.places) + 1)
+	m.places[strings.ToLower(place.Name)] = place
+	return place.ID, nil
+}
+
+func (m *mockCommuteRepository) GetPlaceByName(ctx context.Context, userID int64, name string) (internal.SavedPlace, error) {
+	return m.places[strings.ToLower(name)], nil
+}
+
+type mockBusService struct {
+	bus.IBusService
+	nearby     map[float64][]internal.NearbyBusStop
+	lines      map[int64][]internal.BusLine
+	lineStops  map[int64][]internal.BusLineStop
+	departures func(dayOfWeek string, after time.Time) []internal.BusSchedules
+}
+
+func (m *mockBusService) ListNearbyBusStops(ctx context.Context, latitude, longitude, radius float64) ([]internal.NearbyBusStop, error) {
+	return m.nearby[latitude], nil
+}
+
+func (m *mockBusService) ListBusLinesByStop(ctx context.Context, busStopID int64) ([]internal.BusLine, error) {
+	return m.lines[busStopID], nil
+}
+
+func (m *mockBusService) ListBusLineStops(ctx context.Context, busLineID int64) ([]internal.BusLineStop, error) {
+	return m.lineStops[busLineID], nil
+}
+
+func (m *mockBusService) NextDepartures(ctx context.Context, busLineID int64, dayOfWeek string, after time.Time) ([]internal.BusSchedules, error) {
+	return m.departures(dayOfWeek, after), nil
+}
+
+type mockAddressService struct {
+	address.IAddressService
+	addresses []internal.Address
+}
+
+func (m *mockAddressService) ListAddresses(ctx context.Context, userID int64) ([]internal.Address, error) {
+	return m.addresses, nil
+}
+
+func TestMyCommute(t *testing.T) {
+	now := time.Now()
+	if (now.Hour() == 0 && now.Minute() < 10) || (now.Hour() == 23 && now.Minute() > 50) {
+		t.Skip("horários próximos da meia-noite mudam de dia")
+	}
+
+	offset := int64(120)
+	centro := internal.BusStop{ID: 1, Name: "Centro", Latitude: -29.880, Longitude: -50.270}
+	hospital := internal.BusStop{ID: 2, Name: "Hospital", Latitude: -29.890, Longitude: -50.270}
+	rodoviaria := internal.BusStop{ID: 3, Name: "Rodoviária", Latitude: -29.900, Longitude: -50.270}
+
+	repository := &mockCommuteRepository{places: map[string]internal.SavedPlace{
+		"home": {ID: 1, UserID: 1, Name: "Home", Latitude: -29.8901, Longitude: -50.2701},
+		"work": {ID: 2, UserID: 1, Name: "Work", Latitude: -29.9002, Longitude: -50.2702},
+	}}
+	busService := &mockBusService{
+		nearby: map[float64][]internal.NearbyBusStop{
+			-29.8901: {{BusStop: hospital, Distance: 15}},
+			-29.9002: {{BusStop: rodoviaria, Distance: 30}},
+		},
+		lines: map[int64][]internal.BusLine{2: {
+			{ID: 1, Name: "101", BusInit: centro, BusEnd: rodoviaria},
+			{ID: 2, Name: "102", BusInit: rodoviaria, BusEnd: centro},
+		}},
+		lineStops: map[int64][]internal.BusLineStop{
+			1: {
+				{BusLineID: 1, BusStop: centro, Sequence: 1},
+				{BusLineID: 1, BusStop: hospital, Sequence: 2, TravelTimeOffset: &offset},
+				{BusLineID: 1, BusStop: rodoviaria, Sequence: 3, TravelTimeOffset: &offset},
+			},
+			2: {
+				{BusLineID: 2, BusStop: rodoviaria, Sequence: 1},
+				{BusLineID: 2, BusStop: hospital, Sequence: 2, TravelTimeOffset: &offset},
+				{BusLineID: 2, BusStop: centro, Sequence: 3, TravelTimeOffset: &offset},
+			},
+		},
+		departures: func(dayOfWeek string, after time.Time) []internal.BusSchedules {
+			if dayOfWeek != now.Weekday().String() {
+				return []internal.BusSchedules{}
+			}
+			start := bus.TimeOfDay(after.Add(5 * time.Minute))
+			return []internal.BusSchedules{{BusLineID: 1, StartTime: &start}}
+		},
+	}
+
+	commute, err := NewCommuteService(repository, busService, nil).MyCommute(context.Background(), 1, "", "")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if commute.From.Name != "Home" || commute.To.Name != "Work" {
+		t.Errorf("Locais padrão esperados, recebidos: %s e %s", commute.From.Name, commute.To.Name)
+	}
+
+	// A linha 102 passa pelas duas paradas, mas no sentido contrário.
+	if len(commute.Lines) != 1 {
+		t.Fatalf("Linhas esperadas: 1, recebidas: %d", len(commute.Lines))
+	}
+
+	line := commute.Lines[0]
+	if line.LineName != "101" || line.BoardStop.ID != hospital.ID || line.AlightStop.ID != rodoviaria.ID {
+		t.Errorf("Linha 101 do Hospital à Rodoviária esperada: %+v", line)
+	}
+
+	// O horário parte do Centro, dois minutos antes do Hospital.
+	if len(line.Departures) != 1 {
+		t.Fatalf("Partidas esperadas: 1, recebidas: %d", len(line.Departures))
+	}
+	if want := now.Add(5 * time.Minute); line.Departures[0].Before(want.Add(-2*time.Second)) || line.Departures[0].After(want.Add(2*time.Second)) {
+		t.Errorf("Partida esperada: %v, recebida: %v", want, line.Departures[0])
+	}
+}
+
+func TestMyCommutePlaceNotFound(t *testing.T) {
+	service := NewCommuteService(&mockCommuteRepository{places: map[string]internal.SavedPlace{}}, &mockBusService{}, &mockAddressService{})
+
+	if _, err := service.MyCommute(context.Background(), 1, "Home", "Work"); !errors.Is(err, ErrSavedPlaceNotFound) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrSavedPlaceNotFound, err)
+	}
+}
+
+func TestMyCommuteHomeFromAddress(t *testing.T) {
+	homeLatitude, homeLongitude := -29.8901, -50.2701
+	schoolLatitude, schoolLongitude := -29.9002, -50.2702
+	repository := &mockCommuteRepository{places: map[string]internal.SavedPlace{}}
+	addressService := &mockAddressService{addresses: []internal.Address{
+		{ID: 1, UserID: 1, Label: "Casa", IsPrimary: true, Latitude: &homeLatitude, Longitude: &homeLongitude},
+		{ID: 2, UserID: 1, Label: "School", Latitude: &schoolLatitude, Longitude: &schoolLongitude},
+	}}
+
+	commute, err := NewCommuteService(repository, &mockBusService{}, addressService).MyCommute(context.Background(), 1, "", "school")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if commute.From.Latitude != homeLatitude || commute.From.Longitude != homeLongitude {
+		t.Errorf("Endereço principal esperado como Home, recebido: %+v", commute.From)
+	}
+
+	if commute.To.Latitude != schoolLatitude || commute.To.Longitude != schoolLongitude {
+		t.Errorf("Endereço School esperado como destino, recebido: %+v", commute.To)
+	}
+}
+
+func TestSavePlace(t *testing.T) {
+	service := NewCommuteService(&mockCommuteRepository{places: map[string]internal.SavedPlace{}}, nil, nil)
+
+	tests := []struct {
+		name      string
+		place     internal.SavedPlace
+		wantError error
+	}{
+		{name: "Local válido", place: internal.SavedPlace{UserID: 1, Name: " Home ", Latitude: -29.89, Longitude: -50.27}},
+		{name: "Nome vazio", place: internal.SavedPlace{UserID: 1, Name: "  ", Latitude: -29.89, Longitude: -50.27}, wantError: ErrSavedPlaceNameEmpty},
+		{name: "Nome longo", place: internal.SavedPlace{UserID: 1, Name: strings.Repeat("a", 51), Latitude: -29.89, Longitude: -50.27}, wantError: ErrSavedPlaceNameInvalid},
+		{name: "Sem coordenadas", place: internal.SavedPlace{UserID: 1, Name: "Work"}, wantError: ErrSavedPlaceLocationInvalid},
+		{name: "Usuário inválido", place: internal.SavedPlace{Name: "Work", Latitude: -29.89, Longitude: -50.27}, wantError: ErrSavedPlaceUserIDInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.SavePlace(context.Background(), tt.place)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ type Contact struct {
 	DDI         string
 	DDD         string
 	PhoneNumber string
+	Email       string
 	CreatedAt   time.Time
 	UpdatedAt   *time.Time
 	DeletedAt   *time.Time
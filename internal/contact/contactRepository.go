@@ -29,9 +29,9 @@ func (r *contactRepository) SaveContact(ctx context.Context, contact internal.Co
 	err :=
 		r.Conn.QueryRow(
 			ctx,
-			`INSERT INTO contacts (user_id, name, ddi, ddd, phone_number) VALUES
-                ($1, $2, $3, $4, $5) RETURNING id;`, contact.UserID, contact.Name, contact.DDI,
-			contact.DDD, contact.PhoneNumber).Scan(&contact.ID)
+			`INSERT INTO contacts (user_id, name, ddi, ddd, phone_number, email) VALUES
+                ($1, $2, $3, $4, $5, $6) RETURNING id;`, contact.UserID, contact.Name, contact.DDI,
+			contact.DDD, contact.PhoneNumber, contact.Email).Scan(&contact.ID)
 	if err != nil {
 		return internal.ZERO, err
 	}
@@ -44,9 +44,9 @@ func (r *contactRepository) GetContact(ctx context.Context, userID, contactID in
 	if err :=
 		r.Conn.QueryRow(
 			ctx,
-			`SELECT name, ddi, ddd, phone_number FROM contacts
+			`SELECT name, ddi, ddd, phone_number, email FROM contacts
                 WHERE user_id = $1 AND id = $2 AND deleted_at IS NULL;`, userID, contactID).Scan(&contact.Name,
-			&contact.DDI, &contact.DDD, &contact.PhoneNumber); err != nil {
+			&contact.DDI, &contact.DDD, &contact.PhoneNumber, &contact.Email); err != nil {
 
 		if err == pgx.ErrNoRows {
 			return internal.Contact{}, nil
@@ -69,7 +69,7 @@ func (r *contactRepository) ListContacts(ctx context.Context, userID int64) ([]i
 		rows, err :=
 			r.Conn.Query(
 				ctx,
-				`SELECT id, name, ddi, ddd, phone_number FROM contacts
+				`SELECT id, name, ddi, ddd, phone_number, email FROM contacts
                 	WHERE user_id = $1 AND deleted_at IS NULL`, userID)
 		if err != nil {
 			errorChannel <- err
@@ -85,7 +85,8 @@ func (r *contactRepository) ListContacts(ctx context.Context, userID int64) ([]i
 				&c.Name,
 				&c.DDI,
 				&c.DDD,
-				&c.PhoneNumber); err != nil {
+				&c.PhoneNumber,
+				&c.Email); err != nil {
 				errorChannel <- err
 				return
 			}
@@ -110,8 +111,9 @@ func (r *contactRepository) UpdateContact(ctx context.Context, contact internal.
 		r.Conn.Exec(
 			ctx,
 			`UPDATE contacts SET name = $3, ddi = $4, ddd = $5,
-                phone_number = $6, updated_at = $7 WHERE user_id = $1 AND id = $2
-                    AND deleted_at IS NULL;`, contact.UserID, contact.ID, contact.Name, contact.DDI, contact.DDD, contact.PhoneNumber, time.Now())
+                phone_number = $6, email = $7, updated_at = $8 WHERE user_id = $1 AND id = $2
+                    AND deleted_at IS NULL;`, contact.UserID, contact.ID, contact.Name, contact.DDI, contact.DDD, contact.PhoneNumber,
+			contact.Email, time.Now())
 	if err != nil {
 		return false, err
 	}
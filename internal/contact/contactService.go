@@ -3,6 +3,7 @@ package contact
 import (
 	"context"
 	"errors"
+	"net/mail"
 	"unicode"
 	"unicode/utf8"
 
@@ -108,6 +109,13 @@ func validateContact(c internal.Contact) (bool, error) {
 		}
 	}
 
+	if c.Email != internal.EMPTY {
+		if address, err := mail.ParseAddress(c.Email); err != nil || address.Address != c.Email ||
+			utf8.RuneCountInString(c.Email) > 100 {
+			return false, ErrContactEmailInvalid
+		}
+	}
+
 	return true, nil
 }
 
@@ -122,4 +130,5 @@ var (
 	ErrContactDDDInvalid         = errors.New("contact ddd must have only 3 digits")
 	ErrContactPhoneNumberEmpty   = errors.New("contact phone number is empty")
 	ErrContactPhoneNumberInvalid = errors.New("contact phone number must have 9 digits in range 0-9")
+	ErrContactEmailInvalid       = errors.New("contact email must be a valid address with at most 100 characters")
 )
@@ -80,6 +80,16 @@ func TestSaveContact(t *testing.T) {
 			wantResponse: internal.ZERO,
 			wantError:    true,
 		},
+		{
+			name:   "Err Invalid Email",
+			userID: 1,
+			input:  internal.Contact{UserID: 1, Name: "Test", DDI: "055", DDD: "051", PhoneNumber: "123456789", Email: "Test <test@"},
+			mockFunc: func(ctx context.Context, contact internal.Contact) (int64, error) {
+				return 1, nil
+			},
+			wantResponse: internal.ZERO,
+			wantError:    true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -13,7 +13,8 @@ func createTables(ctx context.Context) {
             created_at TIMESTAMP DEFAULT NOW(),
             updated_at TIMESTAMP NULL,
             deleted_at TIMESTAMP NULL
-        );`
+        );
+        ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT FALSE;`
 
 	_, err := Conn.Exec(ctx, createUsersTable)
 	if err != nil {
@@ -33,7 +34,17 @@ func createTables(ctx context.Context) {
             created_at TIMESTAMP DEFAULT NOW(),
             updated_at TIMESTAMP NULL,
             deleted_at TIMESTAMP NULL
-        );`
+        );
+        ALTER TABLE address ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION NULL;
+        ALTER TABLE address ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION NULL;
+        ALTER TABLE address ADD COLUMN IF NOT EXISTS label VARCHAR(50) NOT NULL DEFAULT 'Home';
+        ALTER TABLE address ADD COLUMN IF NOT EXISTS is_primary BOOLEAN NOT NULL DEFAULT FALSE;
+        UPDATE address a SET is_primary = TRUE
+            WHERE a.deleted_at IS NULL
+                AND a.id = (SELECT MIN(id) FROM address f WHERE f.user_id = a.user_id AND f.deleted_at IS NULL)
+                AND NOT EXISTS (SELECT 1 FROM address p WHERE p.user_id = a.user_id AND p.is_primary AND p.deleted_at IS NULL);
+        CREATE UNIQUE INDEX IF NOT EXISTS address_user_primary_idx
+            ON address (user_id) WHERE is_primary AND deleted_at IS NULL;`
 
 	_, err = Conn.Exec(ctx, createAddressTable)
 	if err != nil {
@@ -51,7 +62,8 @@ func createTables(ctx context.Context) {
             created_at TIMESTAMP DEFAULT NOW(),
             updated_at TIMESTAMP NULL,
             deleted_at TIMESTAMP NULL
-        );`
+        );
+        ALTER TABLE contacts ADD COLUMN IF NOT EXISTS email VARCHAR(100) NOT NULL DEFAULT '';`
 	_, err = Conn.Exec(ctx, createContactTable)
 	if err != nil {
 		panic(err)
@@ -68,7 +80,11 @@ func createTables(ctx context.Context) {
 			created_at TIMESTAMP DEFAULT NOW(),
 			updated_at TIMESTAMP NULL,
 			deleted_at TIMESTAMP NULL
-		);`
+		);
+		CREATE INDEX IF NOT EXISTS shared_vehicle_location_idx
+			ON shared_vehicle (latitude, longitude) WHERE deleted_at IS NULL;
+		CREATE INDEX IF NOT EXISTS shared_vehicle_reported_at_idx
+			ON shared_vehicle (reported_at) WHERE deleted_at IS NULL;`
 
 	_, err = Conn.Exec(ctx, createSharedVehicleTable)
 	if err != nil {
@@ -84,7 +100,9 @@ func createTables(ctx context.Context) {
 			created_at TIMESTAMP DEFAULT NOW(),
 			updated_at TIMESTAMP NULL,
 			deleted_at TIMESTAMP NULL
-		);`
+		);
+		CREATE INDEX IF NOT EXISTS bus_stop_location_idx
+			ON bus_stop (latitude, longitude) WHERE deleted_at IS NULL;`
 
 	_, err = Conn.Exec(ctx, createBusStopTable)
 	if err != nil {
@@ -117,7 +135,9 @@ func createTables(ctx context.Context) {
 			created_at	TIMESTAMP DEFAULT NOW(),
 			updated_at 	TIMESTAMP NULL,
 			deleted_at  TIMESTAMP NULL
-		);`
+		);
+		CREATE INDEX IF NOT EXISTS bus_schedule_departure_idx
+			ON bus_schedule (bus_line_id, day_of_week, start_time) WHERE deleted_at IS NULL;`
 
 	_, err = Conn.Exec(ctx, createBusScheduleTable)
 	if err != nil {
@@ -133,10 +153,197 @@ func createTables(ctx context.Context) {
 			created_at TIMESTAMP DEFAULT NOW(),
 			updated_at TIMESTAMP NULL,
 			deleted_at TIMESTAMP NULL
-		);`
+		);
+		CREATE INDEX IF NOT EXISTS metro_location_idx
+			ON metro (latitude, longitude) WHERE deleted_at IS NULL;`
 
 	_, err = Conn.Exec(ctx, createMetroTable)
 	if err != nil {
 		panic(err)
 	}
+
+	createOccurrenceTable := `
+		CREATE TABLE IF NOT EXISTS occurrence (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER REFERENCES users (id),
+			type INTEGER NOT NULL,
+			description TEXT,
+			latitude DOUBLE PRECISION NOT NULL,
+			longitude DOUBLE PRECISION NOT NULL,
+			time_stamp TIMESTAMP DEFAULT NOW(),
+			confirmation INTEGER DEFAULT 0,
+			disputes INTEGER DEFAULT 0,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP NULL,
+			deleted_at TIMESTAMP NULL
+		);
+		CREATE INDEX IF NOT EXISTS occurrence_type_time_stamp_idx
+			ON occurrence (type, time_stamp) WHERE deleted_at IS NULL;`
+
+	_, err = Conn.Exec(ctx, createOccurrenceTable)
+	if err != nil {
+		panic(err)
+	}
+
+	createOccurrenceConfirmationTable := `
+		CREATE TABLE IF NOT EXISTS occurrence_confirmation (
+			id SERIAL PRIMARY KEY,
+			occurrence_id INTEGER NOT NULL REFERENCES occurrence (id),
+			user_id INTEGER NOT NULL REFERENCES users (id),
+			confirmed BOOLEAN NOT NULL,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP NULL,
+			UNIQUE (occurrence_id, user_id)
+		);`
+
+	_, err = Conn.Exec(ctx, createOccurrenceConfirmationTable)
+	if err != nil {
+		panic(err)
+	}
+
+	createUserFeedbackTable := `
+		CREATE TABLE IF NOT EXISTS user_feedback (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users (id),
+			latitude DOUBLE PRECISION NOT NULL,
+			longitude DOUBLE PRECISION NOT NULL,
+			description TEXT NOT NULL,
+			category VARCHAR(50) NOT NULL,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP NULL,
+			deleted_at TIMESTAMP NULL
+		);`
+
+	_, err = Conn.Exec(ctx, createUserFeedbackTable)
+	if err != nil {
+		panic(err)
+	}
+
+	createUserVotesTable := `
+		CREATE TABLE IF NOT EXISTS user_votes (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users (id),
+			feedback_id INTEGER NOT NULL REFERENCES user_feedback (id),
+			vote_type INTEGER NOT NULL,
+			voted_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE (user_id, feedback_id)
+		);`
+
+	_, err = Conn.Exec(ctx, createUserVotesTable)
+	if err != nil {
+		panic(err)
+	}
+
+	createBusLineStopTable := `
+		CREATE TABLE IF NOT EXISTS bus_line_stop (
+			id SERIAL PRIMARY KEY,
+			bus_line_id INTEGER NOT NULL REFERENCES bus_line(id),
+			bus_stop_id INTEGER NOT NULL REFERENCES bus_stop(id),
+			sequence INTEGER NOT NULL,
+			travel_time_offset INTEGER NULL,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP NULL,
+			deleted_at TIMESTAMP NULL
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS bus_line_stop_sequence_idx
+			ON bus_line_stop (bus_line_id, sequence) WHERE deleted_at IS NULL;
+		CREATE INDEX IF NOT EXISTS bus_line_stop_stop_idx
+			ON bus_line_stop (bus_stop_id) WHERE deleted_at IS NULL;`
+
+	_, err = Conn.Exec(ctx, createBusLineStopTable)
+	if err != nil {
+		panic(err)
+	}
+
+	createGtfsColumns := `
+		ALTER TABLE bus_stop ADD COLUMN IF NOT EXISTS gtfs_id VARCHAR(255) NULL;
+		ALTER TABLE bus_line ADD COLUMN IF NOT EXISTS gtfs_id VARCHAR(255) NULL;
+		ALTER TABLE bus_schedule ADD COLUMN IF NOT EXISTS gtfs_id VARCHAR(255) NULL;
+		ALTER TABLE metro ADD COLUMN IF NOT EXISTS gtfs_id VARCHAR(255) NULL;
+		CREATE UNIQUE INDEX IF NOT EXISTS bus_stop_gtfs_id_idx ON bus_stop (gtfs_id);
+		CREATE UNIQUE INDEX IF NOT EXISTS bus_line_gtfs_id_idx ON bus_line (gtfs_id);
+		CREATE UNIQUE INDEX IF NOT EXISTS bus_schedule_gtfs_id_idx ON bus_schedule (gtfs_id);
+		CREATE UNIQUE INDEX IF NOT EXISTS metro_gtfs_id_idx ON metro (gtfs_id);`
+
+	_, err = Conn.Exec(ctx, createGtfsColumns)
+	if err != nil {
+		panic(err)
+	}
+
+	createUserLocationTable := `
+		CREATE TABLE IF NOT EXISTS user_location (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users (id),
+			line_id INTEGER NOT NULL REFERENCES bus_line (id),
+			latitude DOUBLE PRECISION NOT NULL,
+			longitude DOUBLE PRECISION NOT NULL,
+			time_stamp TIMESTAMP NOT NULL,
+			created_at TIMESTAMP DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS user_location_line_time_stamp_idx
+			ON user_location (line_id, time_stamp);
+		CREATE INDEX IF NOT EXISTS user_location_user_time_stamp_idx
+			ON user_location (user_id, time_stamp);`
+
+	_, err = Conn.Exec(ctx, createUserLocationTable)
+	if err != nil {
+		panic(err)
+	}
+
+	createSavedPlaceTable := `
+		CREATE TABLE IF NOT EXISTS saved_place (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users (id),
+			name VARCHAR(50) NOT NULL,
+			latitude DOUBLE PRECISION NOT NULL,
+			longitude DOUBLE PRECISION NOT NULL,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP NULL,
+			deleted_at TIMESTAMP NULL
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS saved_place_user_name_idx
+			ON saved_place (user_id, (LOWER(name)));`
+
+	_, err = Conn.Exec(ctx, createSavedPlaceTable)
+	if err != nil {
+		panic(err)
+	}
+
+	createSessionsTable := `
+		CREATE TABLE IF NOT EXISTS sessions (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users (id),
+			refresh_token_hash CHAR(64) NOT NULL UNIQUE,
+			previous_token_hash CHAR(64) NULL,
+			expires_at TIMESTAMP NOT NULL,
+			revoked_at TIMESTAMP NULL,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP NULL
+		);
+		CREATE INDEX IF NOT EXISTS sessions_previous_token_hash_idx
+			ON sessions (previous_token_hash);
+		CREATE INDEX IF NOT EXISTS sessions_user_active_idx
+			ON sessions (user_id) WHERE revoked_at IS NULL;`
+
+	_, err = Conn.Exec(ctx, createSessionsTable)
+	if err != nil {
+		panic(err)
+	}
+
+	createPasswordResetTokensTable := `
+		CREATE TABLE IF NOT EXISTS password_reset_tokens (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users (id),
+			token_hash CHAR(64) NOT NULL UNIQUE,
+			expires_at TIMESTAMP NOT NULL,
+			used_at TIMESTAMP NULL,
+			created_at TIMESTAMP DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS password_reset_tokens_user_idx
+			ON password_reset_tokens (user_id) WHERE used_at IS NULL;`
+
+	_, err = Conn.Exec(ctx, createPasswordResetTokensTable)
+	if err != nil {
+		panic(err)
+	}
 }
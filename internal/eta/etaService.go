@@ -0,0 +1,274 @@
+package eta
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/bus"
+	"github.com/amarantec/move-easy/internal/location"
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+const (
+	// AVERAGE_BUS_SPEED in meters per second (about 18 km/h), used when a
+	// line has no travel times between its stops.
+	AVERAGE_BUS_SPEED = 5.0
+	// ARRIVING_DISTANCE is the distance in meters under which a bus is
+	// considered to be at the stop.
+	ARRIVING_DISTANCE = 50.0
+	// MAX_ARRIVAL_WAIT hides schedule arrivals too far in the future to help
+	// a rider decide whether to wait.
+	MAX_ARRIVAL_WAIT = 2 * time.Hour
+)
+
+type IEtaService interface {
+	ListArrivals(ctx context.Context, busStopID int64) ([]internal.BusArrival, error)
+}
+
+type etaService struct {
+	busService      bus.IBusService
+	locationService location.ILocationService
+}
+
+func NewEtaService(busService bus.IBusService, locationService location.ILocationService) IEtaService {
+	return &etaService{busService: busService, locationService: locationService}
+}
+
+// ListArrivals estimates the next arrival of every line that serves a stop.
+// Live estimates come from the bus positions clustered from rider pings;
+// lines without riders fall back to the schedule departures shifted by the
+// travel time from the first stop.
+func (s *etaService) ListArrivals(ctx context.Context, busStopID int64) ([]internal.BusArrival, error) {
+	if busStopID <= internal.ZERO {
+		return []internal.BusArrival{}, bus.ErrBusStopIDInvalid
+	}
+
+	stop, err := s.busService.GetBusStop(ctx, busStopID)
+	if err != nil {
+		return []internal.BusArrival{}, err
+	}
+
+	if stop.ID == internal.ZERO {
+		return []internal.BusArrival{}, ErrArrivalStopNotFound
+	}
+
+	lines, err := s.busService.ListBusLinesByStop(ctx, busStopID)
+	if err != nil {
+		return []internal.BusArrival{}, err
+	}
+
+	now := time.Now()
+	arrivals := []internal.BusArrival{}
+	for _, line := range lines {
+		lineStops, err := s.busService.ListBusLineStops(ctx, line.ID)
+		if err != nil {
+			return []internal.BusArrival{}, err
+		}
+
+		route := RouteStops(line, lineStops)
+		target := stopIndex(route, busStopID)
+		if target < internal.ZERO {
+			continue
+		}
+
+		positions, err := s.locationService.EstimateBusPositions(ctx, line.ID)
+		if err != nil {
+			return []internal.BusArrival{}, err
+		}
+
+		arrival, ok := liveArrival(route, target, positions, now)
+		if !ok {
+			if arrival, ok, err = s.scheduledArrival(ctx, line.ID, route, target, now); err != nil {
+				return []internal.BusArrival{}, err
+			}
+		}
+
+		if ok {
+			arrival.LineID = line.ID
+			arrival.LineName = line.Name
+			arrival.BusStopID = busStopID
+			arrivals = append(arrivals, arrival)
+		}
+	}
+
+	sort.Slice(arrivals, func(i, j int) bool {
+		return arrivals[i].ExpectedAt.Before(arrivals[j].ExpectedAt)
+	})
+
+	return arrivals, nil
+}
+
+// RouteStops returns the ordered stops of a line, or only its first and last
+// stops when the line has no ordered stops.
+func RouteStops(line internal.BusLine, stops []internal.BusLineStop) []internal.BusLineStop {
+	if len(stops) >= 2 {
+		return stops
+	}
+	return []internal.BusLineStop{
+		{BusLineID: line.ID, BusStop: line.BusInit, Sequence: 1},
+		{BusLineID: line.ID, BusStop: line.BusEnd, Sequence: 2},
+	}
+}
+
+func stopIndex(route []internal.BusLineStop, busStopID int64) int {
+	for i, stop := range route {
+		if stop.BusStop.ID == busStopID {
+			return i
+		}
+	}
+	return -1
+}
+
+// TravelTime estimates the time between two stops of a route, using the
+// travel time offsets when all of them are known and the distance at
+// AVERAGE_BUS_SPEED otherwise. The boolean reports whether offsets were used.
+func TravelTime(route []internal.BusLineStop, from, to int) (time.Duration, bool) {
+	offsets := time.Duration(internal.ZERO)
+	distance := 0.0
+	known := true
+	for i := from + 1; i <= to; i++ {
+		if route[i].TravelTimeOffset == nil {
+			known = false
+		} else {
+			offsets += time.Duration(*route[i].TravelTimeOffset) * time.Second
+		}
+		distance += utils.HaversineDistance(route[i-1].BusStop.Latitude, route[i-1].BusStop.Longitude,
+			route[i].BusStop.Latitude, route[i].BusStop.Longitude)
+	}
+
+	if known {
+		return offsets, true
+	}
+	return time.Duration(distance / AVERAGE_BUS_SPEED * float64(time.Second)), false
+}
+
+// liveArrival picks the bus that will reach the target stop first. Each bus
+// is placed at its nearest stop of the route; buses nearest to a stop after
+// the target already passed it and are ignored.
+func liveArrival(route []internal.BusLineStop, target int, positions []internal.BusPosition, now time.Time) (internal.BusArrival, bool) {
+	best := internal.BusArrival{}
+	found := false
+
+	for _, position := range positions {
+		nearest := internal.ZERO
+		nearestDistance := math.MaxFloat64
+		for i, stop := range route {
+			distance := utils.HaversineDistance(position.Latitude, position.Longitude, stop.BusStop.Latitude, stop.BusStop.Longitude)
+			if distance < nearestDistance {
+				nearest, nearestDistance = i, distance
+			}
+		}
+
+		if nearest > target {
+			continue
+		}
+
+		remaining := time.Duration(internal.ZERO)
+		usedOffsets := true
+		if nearest < target || nearestDistance > ARRIVING_DISTANCE {
+			remaining, usedOffsets = TravelTime(route, nearest, target)
+			remaining += time.Duration(nearestDistance / AVERAGE_BUS_SPEED * float64(time.Second))
+		}
+
+		arrival := internal.BusArrival{
+			ExpectedAt: now.Add(remaining),
+			Source:     internal.LIVE,
+			Confidence: liveConfidence(position, remaining, usedOffsets, now),
+			Riders:     position.Riders,
+		}
+		if !found || arrival.ExpectedAt.Before(best.ExpectedAt) {
+			best, found = arrival, true
+		}
+	}
+
+	return best, found
+}
+
+// liveConfidence starts from how many riders agree on the bus position and
+// is lowered for stale pings, long predictions and routes without travel
+// times.
+func liveConfidence(position internal.BusPosition, remaining time.Duration, usedOffsets bool, now time.Time) float64 {
+	confidence := 0.5 + 0.1*float64(position.Riders)
+	if age := now.Sub(position.LastSeen); age > time.Minute {
+		confidence -= 0.2
+	}
+	if remaining > 15*time.Minute {
+		confidence -= 0.2
+	}
+	if !usedOffsets {
+		confidence -= 0.1
+	}
+	return math.Max(0.1, math.Min(0.95, confidence))
+}
+
+// ScheduledDepartures lists, in order and up to limit, the scheduled trips of
+// a line that reach a stop toStop after leaving the first one between from
+// and until. Each schedule is read on its own day, starting from the day the
+// earliest of them left, which is the day before from shortly after midnight,
+// and going on to the next days when until is past midnight.
+func ScheduledDepartures(ctx context.Context, busService bus.IBusService, lineID int64, toStop time.Duration, from, until time.Time, limit int) ([]time.Time, error) {
+	departures := []time.Time{}
+	after := from.Add(-toStop)
+	day := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, after.Location())
+	for !day.After(until.Add(-toStop)) {
+		schedules, err := busService.NextDepartures(ctx, lineID, day.Weekday().String(), after)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, schedule := range schedules {
+			if schedule.StartTime == nil {
+				continue
+			}
+
+			start := schedule.StartTime
+			departure := time.Date(day.Year(), day.Month(), day.Day(),
+				start.Hour(), start.Minute(), start.Second(), 0, day.Location()).Add(toStop)
+			if departure.Before(from) {
+				continue
+			}
+			if departure.After(until) {
+				return departures, nil
+			}
+
+			departures = append(departures, departure)
+			if len(departures) == limit {
+				return departures, nil
+			}
+		}
+
+		day = day.AddDate(0, 0, 1)
+		after = day
+	}
+
+	return departures, nil
+}
+
+// scheduledArrival looks for the first departure that reaches the target stop
+// from now on, which may have left the first stop before now.
+func (s *etaService) scheduledArrival(ctx context.Context, lineID int64, route []internal.BusLineStop, target int, now time.Time) (internal.BusArrival, bool, error) {
+	toStop, usedOffsets := TravelTime(route, internal.ZERO, target)
+
+	departures, err := ScheduledDepartures(ctx, s.busService, lineID, toStop, now, now.Add(MAX_ARRIVAL_WAIT), 1)
+	if err != nil || len(departures) == internal.ZERO {
+		return internal.BusArrival{}, false, err
+	}
+
+	confidence := 0.3
+	if usedOffsets {
+		confidence = 0.4
+	}
+	return internal.BusArrival{
+		ExpectedAt: departures[0],
+		Source:     internal.SCHEDULED,
+		Confidence: confidence,
+	}, true, nil
+}
+
+var (
+	ErrArrivalStopNotFound = errors.New("bus stop not found")
+)
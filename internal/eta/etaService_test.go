@@ -0,0 +1,199 @@
+package eta
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/bus"
+	"github.com/amarantec/move-easy/internal/location"
+)
+
+type mockBusService struct {
+	bus.IBusService
+	stops      map[int64]internal.BusStop
+	lines      []internal.BusLine
+	lineStops  map[int64][]internal.BusLineStop
+	departures func(dayOfWeek string, after time.Time) []internal.BusSchedules
+}
+
+func (m *mockBusService) GetBusStop(ctx context.Context, busStopID int64) (internal.BusStop, error) {
+	return m.stops[busStopID], nil
+}
+
+func (m *mockBusService) ListBusLinesByStop(ctx context.Context, busStopID int64) ([]internal.BusLine, error) {
+	return m.lines, nil
+}
+
+func (m *mockBusService) ListBusLineStops(ctx context.Context, busLineID int64) ([]internal.BusLineStop, error) {
+	return m.lineStops[busLineID], nil
+}
+
+func (m *mockBusService) NextDepartures(ctx context.Context, busLineID int64, dayOfWeek string, after time.Time) ([]internal.BusSchedules, error) {
+	if m.departures == nil {
+		return []internal.BusSchedules{}, nil
+	}
+	return m.departures(dayOfWeek, after), nil
+}
+
+type mockLocationService struct {
+	location.ILocationService
+	positions map[int64][]internal.BusPosition
+}
+
+func (m *mockLocationService) EstimateBusPositions(ctx context.Context, lineID int64) ([]internal.BusPosition, error) {
+	return m.positions[lineID], nil
+}
+
+// newLine builds a line with three stops about 1.1 km apart, two minutes from
+// each other.
+func newLine(lineID int64) (map[int64]internal.BusStop, []internal.BusLineStop) {
+	offset := int64(120)
+	stops := map[int64]internal.BusStop{
+		1: {ID: 1, Name: "Centro", Latitude: -29.880, Longitude: -50.270},
+		2: {ID: 2, Name: "Hospital", Latitude: -29.890, Longitude: -50.270},
+		3: {ID: 3, Name: "Rodoviária", Latitude: -29.900, Longitude: -50.270},
+	}
+	return stops, []internal.BusLineStop{
+		{BusLineID: lineID, BusStop: stops[1], Sequence: 1},
+		{BusLineID: lineID, BusStop: stops[2], Sequence: 2, TravelTimeOffset: &offset},
+		{BusLineID: lineID, BusStop: stops[3], Sequence: 3, TravelTimeOffset: &offset},
+	}
+}
+
+func TestListArrivalsLive(t *testing.T) {
+	stops, lineStops := newLine(1)
+	busService := &mockBusService{
+		stops:     stops,
+		lines:     []internal.BusLine{{ID: 1, Name: "101", BusInit: stops[1], BusEnd: stops[3]}},
+		lineStops: map[int64][]internal.BusLineStop{1: lineStops},
+	}
+	locationService := &mockLocationService{positions: map[int64][]internal.BusPosition{
+		1: {{LineID: 1, Latitude: -29.880, Longitude: -50.270, Riders: 3, LastSeen: time.Now()}},
+	}}
+
+	arrivals, err := NewEtaService(busService, locationService).ListArrivals(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if len(arrivals) != 1 {
+		t.Fatalf("Chegadas esperadas: 1, recebidas: %d", len(arrivals))
+	}
+
+	arrival := arrivals[0]
+	if arrival.Source != internal.LIVE || arrival.LineName != "101" || arrival.Riders != 3 {
+		t.Errorf("Chegada em tempo real esperada: %+v", arrival)
+	}
+
+	if wait := time.Until(arrival.ExpectedAt); wait < 3*time.Minute+50*time.Second || wait > 4*time.Minute {
+		t.Errorf("Espera esperada: 4m, recebida: %v", wait)
+	}
+
+	if arrival.Confidence < 0.7 {
+		t.Errorf("Confiança esperada de ao menos 0.7, recebida: %v", arrival.Confidence)
+	}
+}
+
+func TestListArrivalsScheduleFallback(t *testing.T) {
+	now := time.Now()
+	if now.Hour() == 0 && now.Minute() < 10 {
+		t.Skip("horários próximos da meia-noite mudam de dia")
+	}
+
+	stops, lineStops := newLine(1)
+	busService := &mockBusService{
+		stops:     stops,
+		lines:     []internal.BusLine{{ID: 1, Name: "101", BusInit: stops[1], BusEnd: stops[3]}},
+		lineStops: map[int64][]internal.BusLineStop{1: lineStops},
+		departures: func(dayOfWeek string, after time.Time) []internal.BusSchedules {
+			if dayOfWeek != now.Weekday().String() {
+				return []internal.BusSchedules{}
+			}
+			start := bus.TimeOfDay(after.Add(time.Minute))
+			return []internal.BusSchedules{{BusLineID: 1, DayOfWeek: now.Weekday().String(), StartTime: &start}}
+		},
+	}
+	// O único ônibus com passageiros já passou pela parada 2.
+	locationService := &mockLocationService{positions: map[int64][]internal.BusPosition{
+		1: {{LineID: 1, Latitude: -29.900, Longitude: -50.270, Riders: 1, LastSeen: now}},
+	}}
+
+	arrivals, err := NewEtaService(busService, locationService).ListArrivals(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if len(arrivals) != 1 || arrivals[0].Source != internal.SCHEDULED {
+		t.Fatalf("Chegada pela tabela de horários esperada: %+v", arrivals)
+	}
+
+	if wait := time.Until(arrivals[0].ExpectedAt); wait < 0 || wait > time.Minute {
+		t.Errorf("Espera esperada: até 1m, recebida: %v", wait)
+	}
+
+	if arrivals[0].Confidence >= 0.5 {
+		t.Errorf("Confiança da tabela de horários deveria ser baixa, recebida: %v", arrivals[0].Confidence)
+	}
+}
+
+func TestScheduledDepartures(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.October, day, hour, minute, 0, 0, time.UTC)
+	}
+	// Segunda 19, terça 20 e quarta 21 de outubro de 2026.
+	schedules := map[string][]time.Time{
+		time.Monday.String():    {at(19, 23, 58)},
+		time.Tuesday.String():   {at(20, 0, 30), at(20, 23, 50)},
+		time.Wednesday.String(): {at(21, 0, 10)},
+	}
+	busService := &mockBusService{
+		departures: func(dayOfWeek string, after time.Time) []internal.BusSchedules {
+			found := []internal.BusSchedules{}
+			for _, start := range schedules[dayOfWeek] {
+				start := bus.TimeOfDay(start)
+				if !start.Before(bus.TimeOfDay(after)) {
+					found = append(found, internal.BusSchedules{BusLineID: 1, DayOfWeek: dayOfWeek, StartTime: &start})
+				}
+			}
+			return found
+		},
+	}
+
+	tests := []struct {
+		name string
+		from time.Time
+		want []time.Time
+	}{
+		{name: "Logo após a meia-noite", from: at(20, 0, 5), want: []time.Time{at(20, 0, 8), at(20, 0, 40)}},
+		{name: "Fim da noite", from: at(20, 23, 45), want: []time.Time{at(21, 0, 0), at(21, 0, 20)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			departures, err := ScheduledDepartures(context.Background(), busService, 1, 10*time.Minute, tt.from, tt.from.Add(MAX_ARRIVAL_WAIT), 3)
+			if err != nil {
+				t.Fatalf("[%s] Erro inesperado: %v", tt.name, err)
+			}
+
+			if len(departures) != len(tt.want) {
+				t.Fatalf("[%s] Partidas esperadas: %v, recebidas: %v", tt.name, tt.want, departures)
+			}
+			for i := range departures {
+				if !departures[i].Equal(tt.want[i]) {
+					t.Errorf("[%s] Partida esperada: %v, recebida: %v", tt.name, tt.want[i], departures[i])
+				}
+			}
+		})
+	}
+}
+
+func TestListArrivalsStopNotFound(t *testing.T) {
+	service := NewEtaService(&mockBusService{}, &mockLocationService{})
+
+	if _, err := service.ListArrivals(context.Background(), 99); !errors.Is(err, ErrArrivalStopNotFound) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrArrivalStopNotFound, err)
+	}
+}
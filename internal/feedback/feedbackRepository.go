@@ -0,0 +1,150 @@
+package feedback
+
+import (
+	"context"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IFeedbackRepository interface {
+	InsertFeedback(ctx context.Context, feedback internal.UserFeedback) (int64, error)
+	GetFeedback(ctx context.Context, feedbackID int64) (internal.UserFeedback, error)
+	ListFeedback(ctx context.Context, category string, limit int) ([]internal.UserFeedback, error)
+	SaveVote(ctx context.Context, vote internal.UserVotes) (int64, error)
+	DeleteVote(ctx context.Context, userID, feedbackID int64) (bool, error)
+}
+
+type feedbackRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewFeedbackRepository(connection *pgxpool.Pool) IFeedbackRepository {
+	return &feedbackRepository{Conn: connection}
+}
+
+func (r *feedbackRepository) InsertFeedback(ctx context.Context, feedback internal.UserFeedback) (int64, error) {
+	if err :=
+		r.Conn.QueryRow(
+			ctx,
+			`INSERT INTO user_feedback (user_id, latitude, longitude, description, category)
+				VALUES ($1, $2, $3, $4, $5) RETURNING id;`, feedback.UserID, feedback.Latitude,
+			feedback.Longitude, feedback.Description, feedback.Category).Scan(&feedback.ID); err != nil {
+		return internal.ZERO, err
+	}
+
+	return feedback.ID, nil
+}
+
+func (r *feedbackRepository) GetFeedback(ctx context.Context, feedbackID int64) (internal.UserFeedback, error) {
+	feedback := internal.UserFeedback{ID: feedbackID}
+	if err :=
+		r.Conn.QueryRow(
+			ctx,
+			`SELECT f.user_id, f.latitude, f.longitude, f.description, f.category, f.created_at,
+				COUNT(v.id) FILTER (WHERE v.vote_type = $2),
+				COUNT(v.id) FILTER (WHERE v.vote_type = $3)
+			FROM user_feedback f LEFT JOIN user_votes v ON v.feedback_id = f.id
+			WHERE f.id = $1 AND f.deleted_at IS NULL
+			GROUP BY f.id;`, feedbackID, internal.UPVOTE, internal.DOWNVOTE).Scan(&feedback.UserID,
+			&feedback.Latitude, &feedback.Longitude, &feedback.Description, &feedback.Category,
+			&feedback.CreatedAt, &feedback.Upvotes, &feedback.Downvotes); err != nil {
+		if err == pgx.ErrNoRows {
+			return internal.UserFeedback{}, nil
+		}
+		return internal.UserFeedback{}, err
+	}
+
+	feedback.Score = feedback.Upvotes - feedback.Downvotes
+	return feedback, nil
+}
+
+func (r *feedbackRepository) ListFeedback(ctx context.Context, category string, limit int) ([]internal.UserFeedback, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	feedbackChannel := make(chan []internal.UserFeedback)
+	errorChannel := make(chan error)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT f.id, f.user_id, f.latitude, f.longitude, f.description, f.category, f.created_at,
+					COUNT(v.id) FILTER (WHERE v.vote_type = $2),
+					COUNT(v.id) FILTER (WHERE v.vote_type = $3)
+				FROM user_feedback f LEFT JOIN user_votes v ON v.feedback_id = f.id
+				WHERE f.deleted_at IS NULL AND ($1 = '' OR f.category = $1)
+				GROUP BY f.id
+				ORDER BY COUNT(v.id) FILTER (WHERE v.vote_type = $2) -
+					COUNT(v.id) FILTER (WHERE v.vote_type = $3) DESC, f.created_at DESC
+				LIMIT $4;`, category, internal.UPVOTE, internal.DOWNVOTE, limit)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		feedbacks := []internal.UserFeedback{}
+		for rows.Next() {
+			f := internal.UserFeedback{}
+			if err := rows.Scan(
+				&f.ID,
+				&f.UserID,
+				&f.Latitude,
+				&f.Longitude,
+				&f.Description,
+				&f.Category,
+				&f.CreatedAt,
+				&f.Upvotes,
+				&f.Downvotes); err != nil {
+				errorChannel <- err
+				return
+			}
+			f.Score = f.Upvotes - f.Downvotes
+			feedbacks = append(feedbacks, f)
+		}
+		feedbackChannel <- feedbacks
+	}()
+
+	select {
+	case feedbacks := <-feedbackChannel:
+		return feedbacks, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *feedbackRepository) SaveVote(ctx context.Context, vote internal.UserVotes) (int64, error) {
+	if err :=
+		r.Conn.QueryRow(
+			ctx,
+			`INSERT INTO user_votes (user_id, feedback_id, vote_type, voted_at) VALUES ($1, $2, $3, $4)
+				ON CONFLICT (user_id, feedback_id) DO UPDATE SET vote_type = EXCLUDED.vote_type,
+					voted_at = EXCLUDED.voted_at
+				RETURNING id;`, vote.UserID, vote.FeedbackID, vote.VoteType, time.Now()).Scan(&vote.ID); err != nil {
+		return internal.ZERO, err
+	}
+
+	return vote.ID, nil
+}
+
+func (r *feedbackRepository) DeleteVote(ctx context.Context, userID, feedbackID int64) (bool, error) {
+	result, err :=
+		r.Conn.Exec(
+			ctx,
+			`DELETE FROM user_votes WHERE user_id = $1 AND feedback_id = $2;`, userID, feedbackID)
+	if err != nil {
+		return false, err
+	}
+
+	if result.RowsAffected() == internal.ZERO {
+		return false, nil
+	} else {
+		return true, nil
+	}
+}
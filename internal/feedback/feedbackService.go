@@ -0,0 +1,134 @@
+package feedback
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+const (
+	DEFAULT_LIST_LIMIT = 50
+	MAX_LIST_LIMIT     = 200
+)
+
+type IFeedbackService interface {
+	InsertFeedback(ctx context.Context, feedback internal.UserFeedback) (int64, error)
+	GetFeedback(ctx context.Context, feedbackID int64) (internal.UserFeedback, error)
+	ListFeedback(ctx context.Context, category string, limit int) ([]internal.UserFeedback, error)
+	Vote(ctx context.Context, vote internal.UserVotes) (internal.UserFeedback, error)
+	DeleteVote(ctx context.Context, userID, feedbackID int64) (bool, error)
+}
+
+type feedbackService struct {
+	feedbackRepository IFeedbackRepository
+}
+
+func NewFeedbackService(repository IFeedbackRepository) IFeedbackService {
+	return &feedbackService{feedbackRepository: repository}
+}
+
+func (s *feedbackService) InsertFeedback(ctx context.Context, feedback internal.UserFeedback) (int64, error) {
+	if valid, err := validateFeedback(feedback); err != nil || !valid {
+		return internal.ZERO, err
+	}
+	return s.feedbackRepository.InsertFeedback(ctx, feedback)
+}
+
+func (s *feedbackService) GetFeedback(ctx context.Context, feedbackID int64) (internal.UserFeedback, error) {
+	if feedbackID <= internal.ZERO {
+		return internal.UserFeedback{}, ErrFeedbackIDInvalid
+	}
+	return s.feedbackRepository.GetFeedback(ctx, feedbackID)
+}
+
+func (s *feedbackService) ListFeedback(ctx context.Context, category string, limit int) ([]internal.UserFeedback, error) {
+	if limit <= internal.ZERO {
+		limit = DEFAULT_LIST_LIMIT
+	} else if limit > MAX_LIST_LIMIT {
+		limit = MAX_LIST_LIMIT
+	}
+	return s.feedbackRepository.ListFeedback(ctx, category, limit)
+}
+
+func (s *feedbackService) Vote(ctx context.Context, vote internal.UserVotes) (internal.UserFeedback, error) {
+	if vote.UserID <= internal.ZERO {
+		return internal.UserFeedback{}, ErrFeedbackUserIDInvalid
+	}
+
+	if vote.FeedbackID <= internal.ZERO {
+		return internal.UserFeedback{}, ErrFeedbackIDInvalid
+	}
+
+	if vote.VoteType == nil || !vote.VoteType.IsValid() {
+		return internal.UserFeedback{}, ErrVoteTypeInvalid
+	}
+
+	feedback, err := s.feedbackRepository.GetFeedback(ctx, vote.FeedbackID)
+	if err != nil {
+		return internal.UserFeedback{}, err
+	}
+
+	if feedback.ID == internal.ZERO {
+		return internal.UserFeedback{}, ErrFeedbackNotFound
+	}
+
+	if feedback.UserID == vote.UserID {
+		return internal.UserFeedback{}, ErrFeedbackOwnVote
+	}
+
+	if _, err := s.feedbackRepository.SaveVote(ctx, vote); err != nil {
+		return internal.UserFeedback{}, err
+	}
+
+	return s.feedbackRepository.GetFeedback(ctx, vote.FeedbackID)
+}
+
+func (s *feedbackService) DeleteVote(ctx context.Context, userID, feedbackID int64) (bool, error) {
+	if userID <= internal.ZERO {
+		return false, ErrFeedbackUserIDInvalid
+	}
+
+	if feedbackID <= internal.ZERO {
+		return false, ErrFeedbackIDInvalid
+	}
+	return s.feedbackRepository.DeleteVote(ctx, userID, feedbackID)
+}
+
+func validateFeedback(f internal.UserFeedback) (bool, error) {
+	if f.UserID <= internal.ZERO {
+		return false, ErrFeedbackUserIDInvalid
+	}
+
+	if f.Description == internal.EMPTY {
+		return false, ErrFeedbackDescriptionEmpty
+	} else if utf8.RuneCountInString(f.Description) < 3 || utf8.RuneCountInString(f.Description) > 500 {
+		return false, ErrFeedbackDescriptionInvalid
+	}
+
+	if f.Category == internal.EMPTY {
+		return false, ErrFeedbackCategoryEmpty
+	} else if utf8.RuneCountInString(f.Category) > 50 {
+		return false, ErrFeedbackCategoryInvalid
+	}
+
+	if f.Latitude < -90 || f.Latitude > 90 || f.Longitude < -180 || f.Longitude > 180 {
+		return false, ErrFeedbackLocationInvalid
+	}
+
+	return true, nil
+}
+
+var (
+	ErrFeedbackIDInvalid          = errors.New("feedback id is empty or negative")
+	ErrFeedbackUserIDInvalid      = errors.New("feedback user id is empty or negative")
+	ErrFeedbackDescriptionEmpty   = errors.New("feedback description is empty")
+	ErrFeedbackDescriptionInvalid = errors.New("feedback description must be between 3-500 characters")
+	ErrFeedbackCategoryEmpty      = errors.New("feedback category is empty")
+	ErrFeedbackCategoryInvalid    = errors.New("feedback category must have at most 50 characters")
+	ErrFeedbackLocationInvalid    = errors.New("feedback latitude or longitude out of range")
+	ErrFeedbackNotFound           = errors.New("feedback not found")
+	ErrFeedbackOwnVote            = errors.New("feedback cannot be voted by its author")
+	ErrVoteTypeInvalid            = errors.New("vote type is required and must be 0 (UPVOTE) or 1 (DOWNVOTE)")
+)
@@ -0,0 +1,196 @@
+package feedback
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+type mockFeedbackRepository struct {
+	InsertFeedbackFunc func(ctx context.Context, feedback internal.UserFeedback) (int64, error)
+	GetFeedbackFunc    func(ctx context.Context, feedbackID int64) (internal.UserFeedback, error)
+	ListFeedbackFunc   func(ctx context.Context, category string, limit int) ([]internal.UserFeedback, error)
+	SaveVoteFunc       func(ctx context.Context, vote internal.UserVotes) (int64, error)
+	DeleteVoteFunc     func(ctx context.Context, userID, feedbackID int64) (bool, error)
+}
+
+func (m *mockFeedbackRepository) InsertFeedback(ctx context.Context, feedback internal.UserFeedback) (int64, error) {
+	if m.InsertFeedbackFunc != nil {
+		return m.InsertFeedbackFunc(ctx, feedback)
+	}
+	return internal.ZERO, ErrInsertFeedbackFuncNotImplemented
+}
+
+func (m *mockFeedbackRepository) GetFeedback(ctx context.Context, feedbackID int64) (internal.UserFeedback, error) {
+	if m.GetFeedbackFunc != nil {
+		return m.GetFeedbackFunc(ctx, feedbackID)
+	}
+	return internal.UserFeedback{}, ErrGetFeedbackFuncNotImplemented
+}
+
+func (m *mockFeedbackRepository) ListFeedback(ctx context.Context, category string, limit int) ([]internal.UserFeedback, error) {
+	if m.ListFeedbackFunc != nil {
+		return m.ListFeedbackFunc(ctx, category, limit)
+	}
+	return []internal.UserFeedback{}, ErrListFeedbackFuncNotImplemented
+}
+
+func (m *mockFeedbackRepository) SaveVote(ctx context.Context, vote internal.UserVotes) (int64, error) {
+	if m.SaveVoteFunc != nil {
+		return m.SaveVoteFunc(ctx, vote)
+	}
+	return internal.ZERO, ErrSaveVoteFuncNotImplemented
+}
+
+func (m *mockFeedbackRepository) DeleteVote(ctx context.Context, userID, feedbackID int64) (bool, error) {
+	if m.DeleteVoteFunc != nil {
+		return m.DeleteVoteFunc(ctx, userID, feedbackID)
+	}
+	return false, ErrDeleteVoteFuncNotImplemented
+}
+
+func TestInsertFeedback(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     internal.UserFeedback
+		wantID    int64
+		wantError error
+	}{
+		{
+			name:   "Feedback salvo com sucesso",
+			input:  internal.UserFeedback{UserID: 1, Description: "Calçada quebrada", Category: "calçada", Latitude: -29.88, Longitude: -50.27},
+			wantID: 1,
+		},
+		{
+			name:      "Descrição vazia",
+			input:     internal.UserFeedback{UserID: 1, Category: "calçada"},
+			wantError: ErrFeedbackDescriptionEmpty,
+		},
+		{
+			name:      "Categoria vazia",
+			input:     internal.UserFeedback{UserID: 1, Description: "Calçada quebrada"},
+			wantError: ErrFeedbackCategoryEmpty,
+		},
+		{
+			name:      "Localização inválida",
+			input:     internal.UserFeedback{UserID: 1, Description: "Calçada quebrada", Category: "calçada", Longitude: 200},
+			wantError: ErrFeedbackLocationInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockFeedbackRepository{
+				InsertFeedbackFunc: func(ctx context.Context, feedback internal.UserFeedback) (int64, error) {
+					return 1, nil
+				},
+			}
+			service := NewFeedbackService(mockRepo)
+
+			id, err := service.InsertFeedback(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+
+			if id != tt.wantID {
+				t.Errorf("[%s] ID esperado: %d, recebido: %d", tt.name, tt.wantID, id)
+			}
+		})
+	}
+}
+
+func TestVote(t *testing.T) {
+	votes := map[int64]internal.VoteType{}
+
+	mockRepo := &mockFeedbackRepository{
+		GetFeedbackFunc: func(ctx context.Context, feedbackID int64) (internal.UserFeedback, error) {
+			if feedbackID != 1 {
+				return internal.UserFeedback{}, nil
+			}
+			feedback := internal.UserFeedback{ID: 1, UserID: 10}
+			for _, voteType := range votes {
+				if voteType == internal.UPVOTE {
+					feedback.Upvotes++
+				} else {
+					feedback.Downvotes++
+				}
+			}
+			feedback.Score = feedback.Upvotes - feedback.Downvotes
+			return feedback, nil
+		},
+		SaveVoteFunc: func(ctx context.Context, vote internal.UserVotes) (int64, error) {
+			votes[vote.UserID] = *vote.VoteType
+			return 1, nil
+		},
+	}
+	service := NewFeedbackService(mockRepo)
+	upvote, downvote, invalid := internal.UPVOTE, internal.DOWNVOTE, internal.VoteType(5)
+
+	tests := []struct {
+		name      string
+		vote      internal.UserVotes
+		wantScore int64
+		wantError error
+	}{
+		{name: "Voto positivo", vote: internal.UserVotes{UserID: 20, FeedbackID: 1, VoteType: &upvote}, wantScore: 1},
+		{name: "Voto repetido conta uma vez", vote: internal.UserVotes{UserID: 20, FeedbackID: 1, VoteType: &upvote}, wantScore: 1},
+		{name: "Voto alterado", vote: internal.UserVotes{UserID: 20, FeedbackID: 1, VoteType: &downvote}, wantScore: -1},
+		{name: "Autor não pode votar", vote: internal.UserVotes{UserID: 10, FeedbackID: 1, VoteType: &upvote}, wantError: ErrFeedbackOwnVote},
+		{name: "Feedback inexistente", vote: internal.UserVotes{UserID: 20, FeedbackID: 2, VoteType: &upvote}, wantError: ErrFeedbackNotFound},
+		{name: "Tipo de voto inválido", vote: internal.UserVotes{UserID: 20, FeedbackID: 1, VoteType: &invalid}, wantError: ErrVoteTypeInvalid},
+		{name: "Tipo de voto ausente", vote: internal.UserVotes{UserID: 20, FeedbackID: 1}, wantError: ErrVoteTypeInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.Vote(context.Background(), tt.vote)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+
+			if got.Score != tt.wantScore {
+				t.Errorf("[%s] Pontuação esperada: %d, recebida: %d", tt.name, tt.wantScore, got.Score)
+			}
+		})
+	}
+}
+
+func TestListFeedbackLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		wantLimit int
+	}{
+		{name: "Limite padrão", limit: internal.ZERO, wantLimit: DEFAULT_LIST_LIMIT},
+		{name: "Limite informado", limit: 10, wantLimit: 10},
+		{name: "Limite máximo", limit: 1000, wantLimit: MAX_LIST_LIMIT},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockFeedbackRepository{
+				ListFeedbackFunc: func(ctx context.Context, category string, limit int) ([]internal.UserFeedback, error) {
+					if limit != tt.wantLimit {
+						t.Errorf("[%s] Limite esperado: %d, recebido: %d", tt.name, tt.wantLimit, limit)
+					}
+					return []internal.UserFeedback{}, nil
+				},
+			}
+			service := NewFeedbackService(mockRepo)
+
+			if _, err := service.ListFeedback(context.Background(), internal.EMPTY, tt.limit); err != nil {
+				t.Errorf("[%s] Erro inesperado: %v", tt.name, err)
+			}
+		})
+	}
+}
+
+var (
+	ErrInsertFeedbackFuncNotImplemented = errors.New("InsertFeedbackFunc not implemented")
+	ErrGetFeedbackFuncNotImplemented    = errors.New("GetFeedbackFunc not implemented")
+	ErrListFeedbackFuncNotImplemented   = errors.New("ListFeedbackFunc not implemented")
+	ErrSaveVoteFuncNotImplemented       = errors.New("SaveVoteFunc not implemented")
+	ErrDeleteVoteFuncNotImplemented     = errors.New("DeleteVoteFunc not implemented")
+)
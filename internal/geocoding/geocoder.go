@@ -0,0 +1,211 @@
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+// Precision tells how much of the address the coordinates were resolved
+// from.
+const (
+	PRECISION_NUMBER = "number"
+	PRECISION_STREET = "street"
+	PRECISION_CEP    = "cep"
+)
+
+type Result struct {
+	Latitude  float64
+	Longitude float64
+	Precision string
+}
+
+type IGeocoder interface {
+	Geocode(ctx context.Context, cep, street, number string) (Result, error)
+}
+
+// entry is a row of the dataset: a point for a CEP, optionally for a street
+// and a range of numbers within it.
+type entry struct {
+	street      string
+	numberStart int
+	numberEnd   int
+	latitude    float64
+	longitude   float64
+}
+
+type CSVGeocoder struct {
+	entries map[string][]entry
+}
+
+// NewCSVGeocoder reads a CSV with a header naming the columns cep, latitude
+// and longitude, and optionally street, number_start and number_end. Empty
+// number bounds leave the range open on that side.
+func NewCSVGeocoder(r io.Reader) (*CSVGeocoder, error) {
+	table, err := utils.NewCSVTable(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if missing := table.Missing("cep", "latitude", "longitude"); missing != internal.EMPTY {
+		return nil, fmt.Errorf("%w: %s", ErrGeocodingColumnMissing, missing)
+	}
+
+	g := &CSVGeocoder{entries: map[string][]entry{}}
+	for {
+		record, err := table.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		cep := utils.NormalizeCEP(record.Field("cep"))
+		if len(cep) != 8 {
+			return nil, fmt.Errorf("%w: line %d", ErrGeocodingCEPInvalid, record.Line)
+		}
+
+		e := entry{street: normalizeStreet(record.Field("street"))}
+		if e.latitude, err = strconv.ParseFloat(record.Field("latitude"), 64); err != nil {
+			return nil, fmt.Errorf("%w: line %d", ErrGeocodingLocationInvalid, record.Line)
+		}
+		if e.longitude, err = strconv.ParseFloat(record.Field("longitude"), 64); err != nil {
+			return nil, fmt.Errorf("%w: line %d", ErrGeocodingLocationInvalid, record.Line)
+		}
+		if !utils.ValidCoordinates(e.latitude, e.longitude) {
+			return nil, fmt.Errorf("%w: line %d", ErrGeocodingLocationInvalid, record.Line)
+		}
+
+		if e.numberStart, err = parseNumber(record.Field("number_start"), internal.ZERO); err != nil {
+			return nil, fmt.Errorf("%w: line %d", ErrGeocodingNumberInvalid, record.Line)
+		}
+		if e.numberEnd, err = parseNumber(record.Field("number_end"), int(^uint(0)>>1)); err != nil {
+			return nil, fmt.Errorf("%w: line %d", ErrGeocodingNumberInvalid, record.Line)
+		}
+
+		g.entries[cep] = append(g.entries[cep], e)
+	}
+
+	return g, nil
+}
+
+func LoadCSVGeocoder(path string) (*CSVGeocoder, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return NewCSVGeocoder(file)
+}
+
+// FromEnv loads the dataset at GEOCODING_CSV, returning no geocoder when the
+// variable is not set so addresses are saved without coordinates.
+func FromEnv() (IGeocoder, error) {
+	path := os.Getenv("GEOCODING_CSV")
+	if path == internal.EMPTY {
+		return nil, nil
+	}
+
+	g, err := LoadCSVGeocoder(path)
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Geocode resolves the address to the row of its CEP whose street and number
+// range match, falling back to the center of the rows of the street and then
+// of the whole CEP.
+func (g *CSVGeocoder) Geocode(ctx context.Context, cep, street, number string) (Result, error) {
+	entries := g.entries[utils.NormalizeCEP(cep)]
+	if len(entries) == internal.ZERO {
+		return Result{}, ErrGeocodeNotFound
+	}
+
+	street = normalizeStreet(street)
+	onStreet := []entry{}
+	for _, e := range entries {
+		if e.street != internal.EMPTY && e.street == street {
+			onStreet = append(onStreet, e)
+		}
+	}
+
+	if n, err := strconv.Atoi(strings.TrimSpace(number)); err == nil {
+		for _, e := range onStreet {
+			if n >= e.numberStart && n <= e.numberEnd {
+				return Result{Latitude: e.latitude, Longitude: e.longitude, Precision: PRECISION_NUMBER}, nil
+			}
+		}
+	}
+
+	if len(onStreet) > internal.ZERO {
+		return center(onStreet, PRECISION_STREET), nil
+	}
+	return center(entries, PRECISION_CEP), nil
+}
+
+func center(entries []entry, precision string) Result {
+	result := Result{Precision: precision}
+	for _, e := range entries {
+		result.Latitude += e.latitude
+		result.Longitude += e.longitude
+	}
+	result.Latitude /= float64(len(entries))
+	result.Longitude /= float64(len(entries))
+	return result
+}
+
+func parseNumber(value string, fallback int) (int, error) {
+	if value == internal.EMPTY {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+var abbreviations = map[string]string{
+	"r":    "rua",
+	"av":   "avenida",
+	"avda": "avenida",
+	"al":   "alameda",
+	"tv":   "travessa",
+	"trav": "travessa",
+	"pc":   "praca",
+	"pca":  "praca",
+	"rod":  "rodovia",
+	"est":  "estrada",
+	"estr": "estrada",
+}
+
+// normalizeStreet lowercases the street, drops accents and punctuation and
+// expands the usual abbreviation of its type, so "R. São João" and
+// "Rua Sao Joao" are the same street.
+func normalizeStreet(street string) string {
+	street = utils.FoldText(street)
+	words := strings.FieldsFunc(street, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > internal.ZERO {
+		if full, ok := abbreviations[words[0]]; ok {
+			words[0] = full
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+var (
+	ErrGeocodeNotFound          = errors.New("address cep not found in the geocoding dataset")
+	ErrGeocodingColumnMissing   = errors.New("geocoding dataset column missing")
+	ErrGeocodingCEPInvalid      = errors.New("geocoding dataset cep must contain 8 digits")
+	ErrGeocodingLocationInvalid = errors.New("geocoding dataset latitude or longitude missing or out of range")
+	ErrGeocodingNumberInvalid   = errors.New("geocoding dataset number range must contain only digits")
+)
@@ -0,0 +1,76 @@
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+const dataset = `cep,street,number_start,number_end,latitude,longitude
+93510-000,Avenida Pedro Adams Filho,1,999,-29.6800,-51.1300
+93510000,Avenida Pedro Adams Filho,1000,,-29.6900,-51.1200
+93510000,Rua Júlio de Castilhos,,,-29.6850,-51.1250
+95520000,,,,-29.8800,-50.2700
+`
+
+func TestGeocode(t *testing.T) {
+	geocoder, err := NewCSVGeocoder(strings.NewReader(dataset))
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		cep       string
+		street    string
+		number    string
+		want      Result
+		wantError error
+	}{
+		{name: "Número na faixa", cep: "93510000", street: "Av. Pedro Adams Filho", number: "1500",
+			want: Result{Latitude: -29.6900, Longitude: -51.1200, Precision: PRECISION_NUMBER}},
+		{name: "Rua sem acentos", cep: "93510-000", street: "R Julio de Castilhos", number: "10",
+			want: Result{Latitude: -29.6850, Longitude: -51.1250, Precision: PRECISION_NUMBER}},
+		{name: "Rua sem número", cep: "93510000", street: "Avenida Pedro Adams Filho", number: "s/n",
+			want: Result{Latitude: -29.6850, Longitude: -51.1250, Precision: PRECISION_STREET}},
+		{name: "Somente CEP", cep: "95520000", street: "Rua Desconhecida", number: "1",
+			want: Result{Latitude: -29.8800, Longitude: -50.2700, Precision: PRECISION_CEP}},
+		{name: "CEP desconhecido", cep: "01001000", street: "Praça da Sé", number: "1", wantError: ErrGeocodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := geocoder.Geocode(context.Background(), tt.cep, tt.street, tt.number)
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+
+			if got.Precision != tt.want.Precision || math.Abs(got.Latitude-tt.want.Latitude) > 1e-9 ||
+				math.Abs(got.Longitude-tt.want.Longitude) > 1e-9 {
+				t.Errorf("[%s] Resultado esperado: %+v, recebido: %+v", tt.name, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewCSVGeocoderInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		csv       string
+		wantError error
+	}{
+		{name: "Sem coluna de latitude", csv: "cep,longitude\n95520000,-50.27\n", wantError: ErrGeocodingColumnMissing},
+		{name: "CEP curto", csv: "cep,latitude,longitude\n9552,-29.88,-50.27\n", wantError: ErrGeocodingCEPInvalid},
+		{name: "Latitude fora do intervalo", csv: "cep,latitude,longitude\n95520000,-95,-50.27\n", wantError: ErrGeocodingLocationInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCSVGeocoder(strings.NewReader(tt.csv)); !errors.Is(err, tt.wantError) {
+				t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package gtfs
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+const (
+	LOCATION_TYPE_STOP    = 0
+	LOCATION_TYPE_STATION = 1
+	ROUTE_TYPE_BUS        = 3
+	DATE_LAYOUT           = "20060102"
+)
+
+type Stop struct {
+	ID            string
+	Name          string
+	Latitude      float64
+	Longitude     float64
+	LocationType  int
+	ParentStation string
+}
+
+type Route struct {
+	ID        string
+	ShortName string
+	LongName  string
+	Type      int
+}
+
+type Trip struct {
+	ID          string
+	RouteID     string
+	ServiceID   string
+	DirectionID int
+	StopTimes   []StopTime
+}
+
+type StopTime struct {
+	StopID        string
+	Sequence      int
+	ArrivalTime   time.Duration
+	DepartureTime time.Duration
+}
+
+type Calendar struct {
+	ServiceID string
+	Days      []time.Weekday
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+type Agency struct {
+	ID       string
+	Name     string
+	URL      string
+	Timezone string
+}
+
+type Feed struct {
+	Agency    Agency
+	Stops     map[string]Stop
+	Routes    map[string]Route
+	Trips     map[string]*Trip
+	Calendars map[string]Calendar
+}
+
+// IsBus and IsMetro accept both the basic route types of the GTFS reference
+// and the extended (Google) route types used by some agencies.
+func (r Route) IsBus() bool {
+	return r.Type == ROUTE_TYPE_BUS || (r.Type >= 700 && r.Type < 800)
+}
+
+func (r Route) IsMetro() bool {
+	return r.Type == 1 || (r.Type >= 400 && r.Type < 500)
+}
+
+func (r Route) Name() string {
+	shortName := strings.TrimSpace(r.ShortName)
+	longName := strings.TrimSpace(r.LongName)
+
+	if shortName == internal.EMPTY {
+		return longName
+	} else if longName == internal.EMPTY {
+		return shortName
+	}
+	return shortName + " - " + longName
+}
+
+func ReadFeed(path string) (*Feed, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	return ParseFeed(&archive.Reader)
+}
+
+func ParseFeed(archive *zip.Reader) (*Feed, error) {
+	feed := &Feed{
+		Stops:     map[string]Stop{},
+		Routes:    map[string]Route{},
+		Trips:     map[string]*Trip{},
+		Calendars: map[string]Calendar{},
+	}
+
+	if err := readFile(archive, "stops.txt", true, feed.parseStop); err != nil {
+		return nil, err
+	}
+	if err := readFile(archive, "routes.txt", true, feed.parseRoute); err != nil {
+		return nil, err
+	}
+	if err := readFile(archive, "trips.txt", true, feed.parseTrip); err != nil {
+		return nil, err
+	}
+	if err := readFile(archive, "stop_times.txt", true, feed.parseStopTime); err != nil {
+		return nil, err
+	}
+	if err := readFile(archive, "calendar.txt", false, feed.parseCalendar); err != nil {
+		return nil, err
+	}
+
+	for _, trip := range feed.Trips {
+		sort.Slice(trip.StopTimes, func(i, j int) bool {
+			return trip.StopTimes[i].Sequence < trip.StopTimes[j].Sequence
+		})
+	}
+
+	return feed, nil
+}
+
+// ParseTime parses a GTFS HH:MM:SS time. Hours may exceed 23 for trips that
+// run past midnight of their service day, so the result is a duration since
+// the start of that day rather than a clock time.
+func ParseTime(value string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 3 {
+		return internal.ZERO, fmt.Errorf("%w: %q", ErrTimeInvalid, value)
+	}
+
+	values := [3]int{}
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < internal.ZERO || (i > 0 && number > 59) {
+			return internal.ZERO, fmt.Errorf("%w: %q", ErrTimeInvalid, value)
+		}
+		values[i] = number
+	}
+
+	return time.Duration(values[0])*time.Hour +
+		time.Duration(values[1])*time.Minute +
+		time.Duration(values[2])*time.Second, nil
+}
+
+func readFile(archive *zip.Reader, name string, required bool, parse func(utils.CSVRecord) error) error {
+	file, err := archive.Open(name)
+	if err != nil {
+		if !required {
+			return nil
+		}
+		return fmt.Errorf("%w: %s", ErrFileMissing, name)
+	}
+	defer file.Close()
+
+	table, err := utils.NewCSVTable(file)
+	if err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	for {
+		values, err := table.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		if err := parse(values); err != nil {
+			return fmt.Errorf("%s line %d: %w", name, values.Line, err)
+		}
+	}
+}
+
+func (f *Feed) parseStop(values utils.CSVRecord) error {
+	stop := Stop{
+		ID:            values.Field("stop_id"),
+		Name:          values.Field("stop_name"),
+		ParentStation: values.Field("parent_station"),
+	}
+	if stop.ID == internal.EMPTY {
+		return ErrIDEmpty
+	}
+
+	var err error
+	if values.Field("stop_lat") != internal.EMPTY {
+		if stop.Latitude, err = strconv.ParseFloat(values.Field("stop_lat"), 64); err != nil {
+			return err
+		}
+	}
+	if values.Field("stop_lon") != internal.EMPTY {
+		if stop.Longitude, err = strconv.ParseFloat(values.Field("stop_lon"), 64); err != nil {
+			return err
+		}
+	}
+	if values.Field("location_type") != internal.EMPTY {
+		if stop.LocationType, err = strconv.Atoi(values.Field("location_type")); err != nil {
+			return err
+		}
+	}
+
+	f.Stops[stop.ID] = stop
+	return nil
+}
+
+func (f *Feed) parseRoute(values utils.CSVRecord) error {
+	route := Route{
+		ID:        values.Field("route_id"),
+		ShortName: values.Field("route_short_name"),
+		LongName:  values.Field("route_long_name"),
+	}
+	if route.ID == internal.EMPTY {
+		return ErrIDEmpty
+	}
+
+	routeType, err := strconv.Atoi(values.Field("route_type"))
+	if err != nil {
+		return err
+	}
+	route.Type = routeType
+
+	f.Routes[route.ID] = route
+	return nil
+}
+
+func (f *Feed) parseTrip(values utils.CSVRecord) error {
+	trip := &Trip{
+		ID:        values.Field("trip_id"),
+		RouteID:   values.Field("route_id"),
+		ServiceID: values.Field("service_id"),
+	}
+	if trip.ID == internal.EMPTY {
+		return ErrIDEmpty
+	}
+
+	if values.Field("direction_id") != internal.EMPTY {
+		directionID, err := strconv.Atoi(values.Field("direction_id"))
+		if err != nil {
+			return err
+		}
+		trip.DirectionID = directionID
+	}
+
+	f.Trips[trip.ID] = trip
+	return nil
+}
+
+func (f *Feed) parseStopTime(values utils.CSVRecord) error {
+	trip, ok := f.Trips[values.Field("trip_id")]
+	if !ok {
+		return nil
+	}
+
+	sequence, err := strconv.Atoi(values.Field("stop_sequence"))
+	if err != nil {
+		return err
+	}
+	stopTime := StopTime{StopID: values.Field("stop_id"), Sequence: sequence}
+
+	// Only timepoints are required to have times; the others are
+	// interpolated by consumers and carry no schedule of their own.
+	arrival, departure := values.Field("arrival_time"), values.Field("departure_time")
+	if arrival == internal.EMPTY {
+		arrival = departure
+	} else if departure == internal.EMPTY {
+		departure = arrival
+	}
+	if arrival == internal.EMPTY {
+		stopTime.ArrivalTime, stopTime.DepartureTime = -1, -1
+	} else {
+		if stopTime.ArrivalTime, err = ParseTime(arrival); err != nil {
+			return err
+		}
+		if stopTime.DepartureTime, err = ParseTime(departure); err != nil {
+			return err
+		}
+	}
+
+	trip.StopTimes = append(trip.StopTimes, stopTime)
+	return nil
+}
+
+func (f *Feed) parseCalendar(values utils.CSVRecord) error {
+	calendar := Calendar{ServiceID: values.Field("service_id")}
+	if calendar.ServiceID == internal.EMPTY {
+		return ErrIDEmpty
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if values.Field(strings.ToLower(day.String())) == "1" {
+			calendar.Days = append(calendar.Days, day)
+		}
+	}
+
+	var err error
+	if calendar.StartDate, err = time.Parse(DATE_LAYOUT, values.Field("start_date")); err != nil {
+		return err
+	}
+	if calendar.EndDate, err = time.Parse(DATE_LAYOUT, values.Field("end_date")); err != nil {
+		return err
+	}
+
+	f.Calendars[calendar.ServiceID] = calendar
+	return nil
+}
+
+var (
+	ErrFileMissing = errors.New("gtfs feed is missing a required file")
+	ErrIDEmpty     = errors.New("gtfs record id is empty")
+	ErrTimeInvalid = errors.New("gtfs time must be in HH:MM:SS format")
+)
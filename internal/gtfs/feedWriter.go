@@ -0,0 +1,150 @@
+package gtfs
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+// WriteFeed writes the feed as a GTFS zip archive. Rows are sorted by id so
+// exporting the same data twice produces the same files.
+func WriteFeed(w io.Writer, feed *Feed) error {
+	archive := zip.NewWriter(w)
+
+	if err := writeFile(archive, "agency.txt",
+		[]string{"agency_id", "agency_name", "agency_url", "agency_timezone"},
+		[][]string{{feed.Agency.ID, feed.Agency.Name, feed.Agency.URL, feed.Agency.Timezone}}); err != nil {
+		return err
+	}
+
+	stops := [][]string{}
+	for _, id := range sortedMapKeys(feed.Stops) {
+		stop := feed.Stops[id]
+		stops = append(stops, []string{stop.ID, stop.Name,
+			strconv.FormatFloat(stop.Latitude, 'f', -1, 64),
+			strconv.FormatFloat(stop.Longitude, 'f', -1, 64),
+			strconv.Itoa(stop.LocationType), stop.ParentStation})
+	}
+	if err := writeFile(archive, "stops.txt",
+		[]string{"stop_id", "stop_name", "stop_lat", "stop_lon", "location_type", "parent_station"}, stops); err != nil {
+		return err
+	}
+
+	routes := [][]string{}
+	for _, id := range sortedMapKeys(feed.Routes) {
+		route := feed.Routes[id]
+		routes = append(routes, []string{route.ID, feed.Agency.ID, route.ShortName, route.LongName, strconv.Itoa(route.Type)})
+	}
+	if err := writeFile(archive, "routes.txt",
+		[]string{"route_id", "agency_id", "route_short_name", "route_long_name", "route_type"}, routes); err != nil {
+		return err
+	}
+
+	trips := [][]string{}
+	stopTimes := [][]string{}
+	for _, id := range sortedMapKeys(feed.Trips) {
+		trip := feed.Trips[id]
+		trips = append(trips, []string{trip.RouteID, trip.ServiceID, trip.ID, strconv.Itoa(trip.DirectionID)})
+		for _, stopTime := range trip.StopTimes {
+			stopTimes = append(stopTimes, []string{trip.ID, FormatTime(stopTime.ArrivalTime),
+				FormatTime(stopTime.DepartureTime), stopTime.StopID, strconv.Itoa(stopTime.Sequence)})
+		}
+	}
+	if err := writeFile(archive, "trips.txt",
+		[]string{"route_id", "service_id", "trip_id", "direction_id"}, trips); err != nil {
+		return err
+	}
+	if err := writeFile(archive, "stop_times.txt",
+		[]string{"trip_id", "arrival_time", "departure_time", "stop_id", "stop_sequence"}, stopTimes); err != nil {
+		return err
+	}
+
+	calendars := [][]string{}
+	for _, id := range sortedMapKeys(feed.Calendars) {
+		calendar := feed.Calendars[id]
+		row := []string{calendar.ServiceID}
+		for _, day := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday,
+			time.Thursday, time.Friday, time.Saturday, time.Sunday} {
+			row = append(row, strconv.Itoa(calendar.runsOn(day)))
+		}
+		row = append(row, calendar.StartDate.Format(DATE_LAYOUT), calendar.EndDate.Format(DATE_LAYOUT))
+		calendars = append(calendars, row)
+	}
+	if err := writeFile(archive, "calendar.txt",
+		[]string{"service_id", "monday", "tuesday", "wednesday", "thursday", "friday",
+			"saturday", "sunday", "start_date", "end_date"}, calendars); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// FormatTime is the inverse of ParseTime. Negative durations stand for stops
+// without a scheduled time and are written as an empty field.
+func FormatTime(value time.Duration) string {
+	if value < internal.ZERO {
+		return internal.EMPTY
+	}
+
+	seconds := int(value / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+func (c Calendar) runsOn(day time.Weekday) int {
+	for _, d := range c.Days {
+		if d == day {
+			return 1
+		}
+	}
+	return internal.ZERO
+}
+
+func writeFile(archive *zip.Writer, name string, header []string, rows [][]string) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func sortedMapKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return naturalLess(keys[i], keys[j])
+	})
+	return keys
+}
+
+// naturalLess orders ids such as "B2" before "B10".
+func naturalLess(a, b string) bool {
+	prefixA := strings.TrimRight(a, "0123456789")
+	prefixB := strings.TrimRight(b, "0123456789")
+	if prefixA != prefixB {
+		return a < b
+	}
+
+	numberA, errA := strconv.Atoi(a[len(prefixA):])
+	numberB, errB := strconv.Atoi(b[len(prefixB):])
+	if errA != nil || errB != nil || numberA == numberB {
+		return a < b
+	}
+	return numberA < numberB
+}
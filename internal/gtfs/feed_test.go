@@ -0,0 +1,127 @@
+package gtfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func buildArchive(t *testing.T, files map[string]string) *zip.Reader {
+	t.Helper()
+
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := file.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reader
+}
+
+func sampleFeedFiles() map[string]string {
+	return map[string]string{
+		"stops.txt": "\ufeffstop_id,stop_name,stop_lat,stop_lon,location_type,parent_station\n" +
+			"S1,Centro,-29.88,-50.27,0,\n" +
+			"S2,\"Rodoviária, Plataforma 2\",-29.89,-50.28,0,\n" +
+			"M1,Estação Mercado,-30.02,-51.22,1,\n" +
+			"M1P,Mercado Plataforma,-30.02,-51.22,0,M1\n" +
+			"M2,Estação Aeroporto,-29.99,-51.17,0,\n",
+		"routes.txt": "route_id,route_short_name,route_long_name,route_type\n" +
+			"R1,101,Centro - Rodoviária,3\n" +
+			"T1,,Trensurb,1\n",
+		"trips.txt": "route_id,service_id,trip_id,direction_id\n" +
+			"R1,WK,R1-1,0\n" +
+			"T1,WK,T1-1,0\n",
+		"stop_times.txt": "trip_id,arrival_time,departure_time,stop_id,stop_sequence\n" +
+			"R1-1,25:40:00,25:40:00,S2,2\n" +
+			"R1-1,25:10:00,25:10:00,S1,1\n" +
+			"T1-1,06:00:00,06:00:00,M1P,1\n" +
+			"T1-1,06:12:00,06:12:00,M2,2\n",
+		"calendar.txt": "service_id,monday,tuesday,wednesday,thursday,friday,saturday,sunday,start_date,end_date\n" +
+			"WK,0,0,0,0,1,0,0,20250101,20251231\n",
+	}
+}
+
+func TestParseFeed(t *testing.T) {
+	feed, err := ParseFeed(buildArchive(t, sampleFeedFiles()))
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if len(feed.Stops) != 5 {
+		t.Errorf("Paradas esperadas: 5, recebidas: %d", len(feed.Stops))
+	}
+
+	if feed.Stops["S2"].Name != "Rodoviária, Plataforma 2" {
+		t.Errorf("Nome esperado: Rodoviária, Plataforma 2, recebido: %s", feed.Stops["S2"].Name)
+	}
+
+	if feed.Stops["M1"].LocationType != LOCATION_TYPE_STATION || feed.Stops["M1P"].ParentStation != "M1" {
+		t.Errorf("Estação e plataforma não foram lidas: %+v %+v", feed.Stops["M1"], feed.Stops["M1P"])
+	}
+
+	trip := feed.Trips["R1-1"]
+	if trip == nil || len(trip.StopTimes) != 2 {
+		t.Fatalf("Viagem R1-1 não foi lida: %+v", trip)
+	}
+
+	if trip.StopTimes[0].StopID != "S1" || trip.StopTimes[0].DepartureTime != 25*time.Hour+10*time.Minute {
+		t.Errorf("Horários não ordenados pela sequência: %+v", trip.StopTimes)
+	}
+
+	if days := feed.Calendars["WK"].Days; len(days) != 1 || days[0] != time.Friday {
+		t.Errorf("Dias esperados: [Friday], recebidos: %v", days)
+	}
+}
+
+func TestParseFeedMissingFile(t *testing.T) {
+	files := sampleFeedFiles()
+	delete(files, "stop_times.txt")
+
+	if _, err := ParseFeed(buildArchive(t, files)); !errors.Is(err, ErrFileMissing) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrFileMissing, err)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      time.Duration
+		wantError error
+	}{
+		{name: "Horário comum", input: "08:05:30", want: 8*time.Hour + 5*time.Minute + 30*time.Second},
+		{name: "Horário com uma casa", input: "7:00:00", want: 7 * time.Hour},
+		{name: "Horário após meia-noite", input: "24:30:00", want: 24*time.Hour + 30*time.Minute},
+		{name: "Minutos inválidos", input: "08:75:00", wantError: ErrTimeInvalid},
+		{name: "Formato inválido", input: "08:00", wantError: ErrTimeInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTime(tt.input)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("[%s] Esperado: %v, recebido: %v", tt.name, tt.want, got)
+			}
+		})
+	}
+}
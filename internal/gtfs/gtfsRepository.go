@@ -0,0 +1,311 @@
+package gtfs
+
+import (
+	"context"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const EXPORT_TIMEOUT = 30 * time.Second
+
+type UpsertResult int
+
+const (
+	CREATED UpsertResult = iota
+	UPDATED
+	SKIPPED
+)
+
+type IGtfsRepository interface {
+	UpsertBusStop(ctx context.Context, gtfsID string, stop internal.BusStop) (int64, UpsertResult, error)
+	UpsertBusLine(ctx context.Context, gtfsID string, line internal.BusLine) (int64, UpsertResult, error)
+	UpsertBusSchedule(ctx context.Context, gtfsID string, schedule internal.BusSchedules) (int64, UpsertResult, error)
+	UpsertMetroStation(ctx context.Context, gtfsID string, station internal.MetroStation) (int64, UpsertResult, error)
+	ListBusStops(ctx context.Context) ([]internal.BusStop, error)
+	ListBusLines(ctx context.Context) ([]internal.BusLine, error)
+	ListMetroStations(ctx context.Context) ([]internal.MetroStation, error)
+}
+
+type gtfsRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewGtfsRepository(connection *pgxpool.Pool) IGtfsRepository {
+	return &gtfsRepository{Conn: connection}
+}
+
+func (r *gtfsRepository) UpsertBusStop(ctx context.Context, gtfsID string, stop internal.BusStop) (int64, UpsertResult, error) {
+	return r.upsert(ctx,
+		`INSERT INTO bus_stop (gtfs_id, name, latitude, longitude) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (gtfs_id) DO UPDATE SET name = EXCLUDED.name, latitude = EXCLUDED.latitude,
+				longitude = EXCLUDED.longitude, updated_at = NOW(), deleted_at = NULL
+			WHERE (bus_stop.name, bus_stop.latitude, bus_stop.longitude, bus_stop.deleted_at)
+				IS DISTINCT FROM (EXCLUDED.name, EXCLUDED.latitude, EXCLUDED.longitude, NULL)
+			RETURNING id, xmax = 0;`,
+		`SELECT id FROM bus_stop WHERE gtfs_id = $1;`,
+		gtfsID, stop.Name, stop.Latitude, stop.Longitude)
+}
+
+func (r *gtfsRepository) UpsertBusLine(ctx context.Context, gtfsID string, line internal.BusLine) (int64, UpsertResult, error) {
+	return r.upsert(ctx,
+		`INSERT INTO bus_line (gtfs_id, name, bus_init, bus_end) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (gtfs_id) DO UPDATE SET name = EXCLUDED.name, bus_init = EXCLUDED.bus_init,
+				bus_end = EXCLUDED.bus_end, updated_at = NOW(), deleted_at = NULL
+			WHERE (bus_line.name, bus_line.bus_init, bus_line.bus_end, bus_line.deleted_at)
+				IS DISTINCT FROM (EXCLUDED.name, EXCLUDED.bus_init, EXCLUDED.bus_end, NULL)
+			RETURNING id, xmax = 0;`,
+		`SELECT id FROM bus_line WHERE gtfs_id = $1;`,
+		gtfsID, line.Name, line.BusInit.ID, line.BusEnd.ID)
+}
+
+func (r *gtfsRepository) UpsertBusSchedule(ctx context.Context, gtfsID string, schedule internal.BusSchedules) (int64, UpsertResult, error) {
+	return r.upsert(ctx,
+		`INSERT INTO bus_schedule (gtfs_id, bus_line_id, day_of_week, start_time, end_time) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (gtfs_id) DO UPDATE SET bus_line_id = EXCLUDED.bus_line_id, day_of_week = EXCLUDED.day_of_week,
+				start_time = EXCLUDED.start_time, end_time = EXCLUDED.end_time, updated_at = NOW(), deleted_at = NULL
+			WHERE (bus_schedule.bus_line_id, bus_schedule.day_of_week, bus_schedule.start_time,
+					bus_schedule.end_time, bus_schedule.deleted_at)
+				IS DISTINCT FROM (EXCLUDED.bus_line_id, EXCLUDED.day_of_week, EXCLUDED.start_time,
+					EXCLUDED.end_time, NULL)
+			RETURNING id, xmax = 0;`,
+		`SELECT id FROM bus_schedule WHERE gtfs_id = $1;`,
+		gtfsID, schedule.BusLineID, schedule.DayOfWeek, schedule.StartTime, schedule.EndTime)
+}
+
+func (r *gtfsRepository) UpsertMetroStation(ctx context.Context, gtfsID string, station internal.MetroStation) (int64, UpsertResult, error) {
+	return r.upsert(ctx,
+		`INSERT INTO metro (gtfs_id, station_name, latitude, longitude) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (gtfs_id) DO UPDATE SET station_name = EXCLUDED.station_name, latitude = EXCLUDED.latitude,
+				longitude = EXCLUDED.longitude, updated_at = NOW(), deleted_at = NULL
+			WHERE (metro.station_name, metro.latitude, metro.longitude, metro.deleted_at)
+				IS DISTINCT FROM (EXCLUDED.station_name, EXCLUDED.latitude, EXCLUDED.longitude, NULL)
+			RETURNING id, xmax = 0;`,
+		`SELECT id FROM metro WHERE gtfs_id = $1;`,
+		gtfsID, station.StationName, station.Latitude, station.Longitude)
+}
+
+// upsert runs an INSERT ... ON CONFLICT DO UPDATE ... WHERE statement that
+// returns the row id and whether it was inserted (xmax = 0). When the WHERE
+// clause filters the update out the row is already up to date, so nothing is
+// returned and the id is read back with the lookup query instead.
+func (r *gtfsRepository) upsert(ctx context.Context, query, lookup string, args ...any) (int64, UpsertResult, error) {
+	var id int64
+	var created bool
+
+	if err :=
+		r.Conn.QueryRow(ctx, query, args...).Scan(&id, &created); err != nil {
+		if err != pgx.ErrNoRows {
+			return internal.ZERO, SKIPPED, err
+		}
+
+		if err :=
+			r.Conn.QueryRow(ctx, lookup, args[0]).Scan(&id); err != nil {
+			return internal.ZERO, SKIPPED, err
+		}
+		return id, SKIPPED, nil
+	}
+
+	if created {
+		return id, CREATED, nil
+	}
+	return id, UPDATED, nil
+}
+
+func (r *gtfsRepository) ListBusStops(ctx context.Context) ([]internal.BusStop, error) {
+	ctx, cancel := context.WithTimeout(ctx, EXPORT_TIMEOUT)
+	defer cancel()
+
+	stopChannel := make(chan []internal.BusStop)
+	errorChannel := make(chan error)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT id, name, latitude, longitude FROM bus_stop
+					WHERE deleted_at IS NULL ORDER BY id;`)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		stops := []internal.BusStop{}
+		for rows.Next() {
+			s := internal.BusStop{}
+			if err := rows.Scan(
+				&s.ID,
+				&s.Name,
+				&s.Latitude,
+				&s.Longitude); err != nil {
+				errorChannel <- err
+				return
+			}
+			stops = append(stops, s)
+		}
+		stopChannel <- stops
+	}()
+
+	select {
+	case stops := <-stopChannel:
+		return stops, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ListBusLines returns every bus line with its ordered stops and schedules,
+// reading each table once instead of once per line.
+func (r *gtfsRepository) ListBusLines(ctx context.Context) ([]internal.BusLine, error) {
+	ctx, cancel := context.WithTimeout(ctx, EXPORT_TIMEOUT)
+	defer cancel()
+
+	lineChannel := make(chan []internal.BusLine)
+	errorChannel := make(chan error)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT id, name, bus_init, bus_end FROM bus_line
+					WHERE deleted_at IS NULL ORDER BY id;`)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		lines := []internal.BusLine{}
+		index := map[int64]int{}
+		for rows.Next() {
+			l := internal.BusLine{}
+			if err := rows.Scan(
+				&l.ID,
+				&l.Name,
+				&l.BusInit.ID,
+				&l.BusEnd.ID); err != nil {
+				rows.Close()
+				errorChannel <- err
+				return
+			}
+			index[l.ID] = len(lines)
+			lines = append(lines, l)
+		}
+		rows.Close()
+
+		rows, err =
+			r.Conn.Query(
+				ctx,
+				`SELECT ls.bus_line_id, ls.sequence, ls.travel_time_offset, ls.bus_stop_id
+					FROM bus_line_stop ls JOIN bus_stop s ON s.id = ls.bus_stop_id
+					WHERE ls.deleted_at IS NULL AND s.deleted_at IS NULL
+					ORDER BY ls.bus_line_id, ls.sequence;`)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		for rows.Next() {
+			s := internal.BusLineStop{}
+			if err := rows.Scan(
+				&s.BusLineID,
+				&s.Sequence,
+				&s.TravelTimeOffset,
+				&s.BusStop.ID); err != nil {
+				rows.Close()
+				errorChannel <- err
+				return
+			}
+			if i, ok := index[s.BusLineID]; ok {
+				lines[i].Stops = append(lines[i].Stops, s)
+			}
+		}
+		rows.Close()
+
+		rows, err =
+			r.Conn.Query(
+				ctx,
+				`SELECT id, bus_line_id, day_of_week, start_time, end_time FROM bus_schedule
+					WHERE deleted_at IS NULL ORDER BY bus_line_id, start_time;`)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			s := internal.BusSchedules{}
+			if err := rows.Scan(
+				&s.ID,
+				&s.BusLineID,
+				&s.DayOfWeek,
+				&s.StartTime,
+				&s.EndTime); err != nil {
+				errorChannel <- err
+				return
+			}
+			if i, ok := index[s.BusLineID]; ok {
+				lines[i].Schedules = append(lines[i].Schedules, s)
+			}
+		}
+		lineChannel <- lines
+	}()
+
+	select {
+	case lines := <-lineChannel:
+		return lines, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *gtfsRepository) ListMetroStations(ctx context.Context) ([]internal.MetroStation, error) {
+	ctx, cancel := context.WithTimeout(ctx, EXPORT_TIMEOUT)
+	defer cancel()
+
+	stationChannel := make(chan []internal.MetroStation)
+	errorChannel := make(chan error)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT id, station_name, latitude, longitude FROM metro
+					WHERE deleted_at IS NULL ORDER BY id;`)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		stations := []internal.MetroStation{}
+		for rows.Next() {
+			s := internal.MetroStation{}
+			if err := rows.Scan(
+				&s.ID,
+				&s.StationName,
+				&s.Latitude,
+				&s.Longitude); err != nil {
+				errorChannel <- err
+				return
+			}
+			stations = append(stations, s)
+		}
+		stationChannel <- stations
+	}()
+
+	select {
+	case stations := <-stationChannel:
+		return stations, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,423 @@
+package gtfs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/bus"
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+const (
+	MAX_NAME_LENGTH   = 255
+	DEFAULT_AGENCY_ID = "1"
+)
+
+type ImportCount struct {
+	Created int
+	Updated int
+	Skipped int
+}
+
+type ImportReport struct {
+	BusStops      ImportCount
+	BusLines      ImportCount
+	BusSchedules  ImportCount
+	MetroStations ImportCount
+}
+
+type IGtfsService interface {
+	Import(ctx context.Context, feed *Feed) (ImportReport, error)
+	Export(ctx context.Context, agency Agency) (*Feed, error)
+}
+
+type gtfsService struct {
+	repository IGtfsRepository
+}
+
+func NewGtfsService(repo IGtfsRepository) IGtfsService {
+	return &gtfsService{repository: repo}
+}
+
+func (c *ImportCount) add(result UpsertResult) {
+	switch result {
+	case CREATED:
+		c.Created++
+	case UPDATED:
+		c.Updated++
+	default:
+		c.Skipped++
+	}
+}
+
+// Import upserts the bus and metro data of a feed keyed by their GTFS ids, so
+// importing the same feed twice only reports skipped rows. Stops are imported
+// as bus stops or metro stations depending on the routes that serve them, and
+// each bus trip becomes one schedule per weekday of its service calendar.
+func (s *gtfsService) Import(ctx context.Context, feed *Feed) (ImportReport, error) {
+	report := ImportReport{}
+	if feed == nil {
+		return report, ErrFeedEmpty
+	}
+
+	busTrips := map[string][]*Trip{}
+	busStopIDs := map[string]bool{}
+	metroStationIDs := map[string]bool{}
+
+	for _, trip := range feed.Trips {
+		route, ok := feed.Routes[trip.RouteID]
+		if !ok {
+			continue
+		}
+
+		if route.IsBus() {
+			busTrips[route.ID] = append(busTrips[route.ID], trip)
+			for _, stopTime := range trip.StopTimes {
+				busStopIDs[stopTime.StopID] = true
+			}
+		} else if route.IsMetro() {
+			for _, stopTime := range trip.StopTimes {
+				metroStationIDs[feed.station(stopTime.StopID)] = true
+			}
+		}
+	}
+
+	busStops := map[string]int64{}
+	for _, gtfsID := range sortedMapKeys(busStopIDs) {
+		stop, ok := feed.Stops[gtfsID]
+		if !ok || !validStop(stop) {
+			report.BusStops.Skipped++
+			continue
+		}
+
+		id, result, err := s.repository.UpsertBusStop(ctx, gtfsID, internal.BusStop{
+			Name:      truncate(stop.Name),
+			Latitude:  stop.Latitude,
+			Longitude: stop.Longitude,
+		})
+		if err != nil {
+			return report, err
+		}
+		busStops[gtfsID] = id
+		report.BusStops.add(result)
+	}
+
+	for _, gtfsID := range sortedMapKeys(metroStationIDs) {
+		stop, ok := feed.Stops[gtfsID]
+		if !ok || !validStop(stop) {
+			report.MetroStations.Skipped++
+			continue
+		}
+
+		_, result, err := s.repository.UpsertMetroStation(ctx, gtfsID, internal.MetroStation{
+			StationName: truncate(stop.Name),
+			Latitude:    stop.Latitude,
+			Longitude:   stop.Longitude,
+		})
+		if err != nil {
+			return report, err
+		}
+		report.MetroStations.add(result)
+	}
+
+	for _, routeID := range sortedMapKeys(busTrips) {
+		trips := busTrips[routeID]
+		sort.Slice(trips, func(i, j int) bool { return trips[i].ID < trips[j].ID })
+
+		route := feed.Routes[routeID]
+		pattern := representativeTrip(trips)
+		if pattern == nil || route.Name() == internal.EMPTY {
+			report.BusLines.Skipped++
+			continue
+		}
+
+		busInit, okInit := busStops[pattern.StopTimes[0].StopID]
+		busEnd, okEnd := busStops[pattern.StopTimes[len(pattern.StopTimes)-1].StopID]
+		if !okInit || !okEnd {
+			report.BusLines.Skipped++
+			continue
+		}
+
+		busLineID, result, err := s.repository.UpsertBusLine(ctx, routeID, internal.BusLine{
+			Name:    truncate(route.Name()),
+			BusInit: internal.BusStop{ID: busInit},
+			BusEnd:  internal.BusStop{ID: busEnd},
+		})
+		if err != nil {
+			return report, err
+		}
+		report.BusLines.add(result)
+
+		for _, trip := range trips {
+			calendar, ok := feed.Calendars[trip.ServiceID]
+			start, end, timed := tripTimes(trip)
+			if !ok || !timed || len(calendar.Days) == internal.ZERO {
+				report.BusSchedules.Skipped++
+				continue
+			}
+
+			for _, day := range calendar.Days {
+				startDay, startTime := clockTime(day, start)
+				_, endTime := clockTime(day, end)
+
+				_, result, err := s.repository.UpsertBusSchedule(ctx, trip.ID+":"+day.String(), internal.BusSchedules{
+					BusLineID: busLineID,
+					DayOfWeek: startDay.String(),
+					StartTime: &startTime,
+					EndTime:   &endTime,
+				})
+				if err != nil {
+					return report, err
+				}
+				report.BusSchedules.add(result)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// Export builds a feed from the stored bus and metro data. Ids are derived
+// from the database ids with a prefix per table, since bus stops and metro
+// stations share the stops.txt namespace. Metro stations are exported as
+// stops only because there is no metro line topology to build routes from.
+func (s *gtfsService) Export(ctx context.Context, agency Agency) (*Feed, error) {
+	if agency.ID == internal.EMPTY {
+		agency.ID = DEFAULT_AGENCY_ID
+	}
+
+	feed := &Feed{
+		Agency:    agency,
+		Stops:     map[string]Stop{},
+		Routes:    map[string]Route{},
+		Trips:     map[string]*Trip{},
+		Calendars: map[string]Calendar{},
+	}
+
+	busStops, err := s.repository.ListBusStops(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, stop := range busStops {
+		id := busStopID(stop.ID)
+		feed.Stops[id] = Stop{ID: id, Name: stop.Name, Latitude: stop.Latitude, Longitude: stop.Longitude}
+	}
+
+	metroStations, err := s.repository.ListMetroStations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, station := range metroStations {
+		id := "M" + strconv.FormatInt(station.ID, 10)
+		feed.Stops[id] = Stop{ID: id, Name: station.StationName, Latitude: station.Latitude, Longitude: station.Longitude}
+	}
+
+	busLines, err := s.repository.ListBusLines(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate := time.Now().Truncate(24 * time.Hour)
+	for _, line := range busLines {
+		stops := lineStops(line)
+		if _, ok := feed.Stops[stops[0].StopID]; !ok {
+			continue
+		}
+		if _, ok := feed.Stops[stops[len(stops)-1].StopID]; !ok {
+			continue
+		}
+
+		routeID := "L" + strconv.FormatInt(line.ID, 10)
+		hasTrips := false
+		for _, schedule := range line.Schedules {
+			day, err := bus.ParseDayOfWeek(schedule.DayOfWeek)
+			if err != nil || schedule.StartTime == nil || schedule.EndTime == nil {
+				continue
+			}
+
+			trip := &Trip{
+				ID:        "T" + strconv.FormatInt(schedule.ID, 10),
+				RouteID:   routeID,
+				ServiceID: day.String(),
+				StopTimes: tripStopTimes(stops, sinceMidnight(*schedule.StartTime), sinceMidnight(*schedule.EndTime)),
+			}
+			feed.Trips[trip.ID] = trip
+			hasTrips = true
+
+			if _, ok := feed.Calendars[trip.ServiceID]; !ok {
+				feed.Calendars[trip.ServiceID] = Calendar{
+					ServiceID: trip.ServiceID,
+					Days:      []time.Weekday{day},
+					StartDate: startDate,
+					EndDate:   startDate.AddDate(1, 0, 0),
+				}
+			}
+		}
+
+		if hasTrips {
+			feed.Routes[routeID] = Route{ID: routeID, LongName: line.Name, Type: ROUTE_TYPE_BUS}
+		}
+	}
+
+	return feed, nil
+}
+
+func busStopID(id int64) string {
+	return "B" + strconv.FormatInt(id, 10)
+}
+
+// lineStops returns the ordered stops of a line with the scheduled offset of
+// each one, falling back to the first and last stop when the line has no
+// ordered stops. Offsets are left negative when any travel time up to that
+// stop is unknown.
+func lineStops(line internal.BusLine) []StopTime {
+	if len(line.Stops) < 2 {
+		return []StopTime{
+			{StopID: busStopID(line.BusInit.ID), Sequence: 1, ArrivalTime: internal.ZERO},
+			{StopID: busStopID(line.BusEnd.ID), Sequence: 2, ArrivalTime: -1},
+		}
+	}
+
+	stops := []StopTime{}
+	offset := time.Duration(internal.ZERO)
+	for i, stop := range line.Stops {
+		if i > 0 {
+			if offset >= internal.ZERO && stop.TravelTimeOffset != nil {
+				offset += time.Duration(*stop.TravelTimeOffset) * time.Second
+			} else {
+				offset = -1
+			}
+		}
+		stops = append(stops, StopTime{StopID: busStopID(stop.BusStop.ID), Sequence: stop.Sequence, ArrivalTime: offset})
+	}
+	return stops
+}
+
+// tripStopTimes anchors the offsets of a line to one schedule. The first and
+// last stops always use the schedule start and end, which GTFS requires, and
+// intermediate stops whose offset is unknown or past the end are left blank.
+func tripStopTimes(stops []StopTime, start, end time.Duration) []StopTime {
+	if end < start {
+		end += 24 * time.Hour
+	}
+
+	stopTimes := make([]StopTime, len(stops))
+	for i, stop := range stops {
+		value := time.Duration(-1)
+		if i == 0 {
+			value = start
+		} else if i == len(stops)-1 {
+			value = end
+		} else if stop.ArrivalTime >= internal.ZERO && start+stop.ArrivalTime <= end {
+			value = start + stop.ArrivalTime
+		}
+		stopTimes[i] = StopTime{StopID: stop.StopID, Sequence: stop.Sequence, ArrivalTime: value, DepartureTime: value}
+	}
+	return stopTimes
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+}
+
+// station resolves a platform to its parent station, since metro stations are
+// stored once rather than once per platform.
+func (f *Feed) station(stopID string) string {
+	stop, ok := f.Stops[stopID]
+	if !ok || stop.ParentStation == internal.EMPTY {
+		return stopID
+	}
+	if _, ok := f.Stops[stop.ParentStation]; !ok {
+		return stopID
+	}
+	return stop.ParentStation
+}
+
+// representativeTrip picks the trip with the most stops, preferring the
+// outbound direction, to define the first and last stop of a line.
+func representativeTrip(trips []*Trip) *Trip {
+	var pattern *Trip
+	for _, trip := range trips {
+		if len(trip.StopTimes) < 2 {
+			continue
+		}
+		if pattern == nil || len(trip.StopTimes) > len(pattern.StopTimes) ||
+			(len(trip.StopTimes) == len(pattern.StopTimes) && trip.DirectionID < pattern.DirectionID) {
+			pattern = trip
+		}
+	}
+	return pattern
+}
+
+func tripTimes(trip *Trip) (time.Duration, time.Duration, bool) {
+	if len(trip.StopTimes) < 2 {
+		return internal.ZERO, internal.ZERO, false
+	}
+
+	start := trip.StopTimes[0].DepartureTime
+	end := trip.StopTimes[len(trip.StopTimes)-1].ArrivalTime
+	if start < internal.ZERO || end < start {
+		return internal.ZERO, internal.ZERO, false
+	}
+	return start, end, true
+}
+
+// clockTime converts an offset from the start of a service day into the
+// weekday and time of day it actually happens, so a 25:10:00 departure of a
+// Friday service is stored as Saturday 01:10.
+func clockTime(day time.Weekday, offset time.Duration) (time.Weekday, time.Time) {
+	days := int(offset / (24 * time.Hour))
+	offset -= time.Duration(days) * 24 * time.Hour
+
+	return time.Weekday((int(day) + days) % 7),
+		time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC).Add(offset)
+}
+
+func validStop(stop Stop) bool {
+	return stop.Name != internal.EMPTY &&
+		!(stop.Latitude == internal.ZERO && stop.Longitude == internal.ZERO) &&
+		utils.ValidCoordinates(stop.Latitude, stop.Longitude)
+}
+
+func truncate(value string) string {
+	if utf8.RuneCountInString(value) <= MAX_NAME_LENGTH {
+		return value
+	}
+	return string([]rune(value)[:MAX_NAME_LENGTH])
+}
+
+// AgencyFromEnv reads the agency written to agency.txt on export.
+func AgencyFromEnv() Agency {
+	agency := Agency{
+		ID:       os.Getenv("GTFS_AGENCY_ID"),
+		Name:     os.Getenv("GTFS_AGENCY_NAME"),
+		URL:      os.Getenv("GTFS_AGENCY_URL"),
+		Timezone: os.Getenv("GTFS_AGENCY_TIMEZONE"),
+	}
+
+	if agency.ID == internal.EMPTY {
+		agency.ID = DEFAULT_AGENCY_ID
+	}
+	if agency.Name == internal.EMPTY {
+		agency.Name = "Move Easy"
+	}
+	if agency.URL == internal.EMPTY {
+		agency.URL = "https://github.com/amarantec/move-easy"
+	}
+	if agency.Timezone == internal.EMPTY {
+		agency.Timezone = "America/Sao_Paulo"
+	}
+	return agency
+}
+
+var (
+	ErrFeedEmpty = errors.New("gtfs feed is empty")
+)
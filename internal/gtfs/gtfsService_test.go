@@ -0,0 +1,201 @@
+package gtfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+// mockGtfsRepository keeps the upserted rows in memory and reports a row as
+// skipped when it is upserted again without changes, like the database does.
+type mockGtfsRepository struct {
+	rows          map[string]any
+	ids           map[string]int64
+	busStops      []internal.BusStop
+	busLines      []internal.BusLine
+	metroStations []internal.MetroStation
+}
+
+func newMockGtfsRepository() *mockGtfsRepository {
+	return &mockGtfsRepository{rows: map[string]any{}, ids: map[string]int64{}}
+}
+
+func (m *mockGtfsRepository) upsert(key string, row any) (int64, UpsertResult, error) {
+	previous, ok := m.rows[key]
+	m.rows[key] = row
+	if !ok {
+		m.ids[key] = int64(len(m.ids) + 1)
+		return m.ids[key], CREATED, nil
+	} else if previous != row {
+		return m.ids[key], UPDATED, nil
+	}
+	return m.ids[key], SKIPPED, nil
+}
+
+func (m *mockGtfsRepository) UpsertBusStop(ctx context.Context, gtfsID string, stop internal.BusStop) (int64, UpsertResult, error) {
+	return m.upsert("bus_stop:"+gtfsID, stop)
+}
+
+func (m *mockGtfsRepository) UpsertBusLine(ctx context.Context, gtfsID string, line internal.BusLine) (int64, UpsertResult, error) {
+	return m.upsert("bus_line:"+gtfsID, [3]any{line.Name, line.BusInit.ID, line.BusEnd.ID})
+}
+
+func (m *mockGtfsRepository) UpsertBusSchedule(ctx context.Context, gtfsID string, schedule internal.BusSchedules) (int64, UpsertResult, error) {
+	return m.upsert("bus_schedule:"+gtfsID, [4]any{schedule.BusLineID, schedule.DayOfWeek,
+		schedule.StartTime.Format("15:04:05"), schedule.EndTime.Format("15:04:05")})
+}
+
+func (m *mockGtfsRepository) UpsertMetroStation(ctx context.Context, gtfsID string, station internal.MetroStation) (int64, UpsertResult, error) {
+	return m.upsert("metro:"+gtfsID, station)
+}
+
+func (m *mockGtfsRepository) ListBusStops(ctx context.Context) ([]internal.BusStop, error) {
+	return m.busStops, nil
+}
+
+func (m *mockGtfsRepository) ListBusLines(ctx context.Context) ([]internal.BusLine, error) {
+	return m.busLines, nil
+}
+
+func (m *mockGtfsRepository) ListMetroStations(ctx context.Context) ([]internal.MetroStation, error) {
+	return m.metroStations, nil
+}
+
+func TestImport(t *testing.T) {
+	feed, err := ParseFeed(buildArchive(t, sampleFeedFiles()))
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	mockRepo := newMockGtfsRepository()
+	service := NewGtfsService(mockRepo)
+
+	report, err := service.Import(context.Background(), feed)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	want := ImportReport{
+		BusStops:      ImportCount{Created: 2},
+		BusLines:      ImportCount{Created: 1},
+		BusSchedules:  ImportCount{Created: 1},
+		MetroStations: ImportCount{Created: 2},
+	}
+	if report != want {
+		t.Errorf("Relatório esperado: %+v, recebido: %+v", want, report)
+	}
+
+	if _, ok := mockRepo.rows["metro:M1"]; !ok {
+		t.Errorf("Plataforma M1P deveria ser importada como a estação M1")
+	}
+
+	schedule := mockRepo.rows["bus_schedule:R1-1:Friday"].([4]any)
+	if schedule[1] != "Saturday" || schedule[2] != "01:10:00" || schedule[3] != "01:40:00" {
+		t.Errorf("Horário após meia-noite não normalizado: %v", schedule)
+	}
+
+	report, err = service.Import(context.Background(), feed)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	want = ImportReport{
+		BusStops:      ImportCount{Skipped: 2},
+		BusLines:      ImportCount{Skipped: 1},
+		BusSchedules:  ImportCount{Skipped: 1},
+		MetroStations: ImportCount{Skipped: 2},
+	}
+	if report != want {
+		t.Errorf("Relatório da segunda importação esperado: %+v, recebido: %+v", want, report)
+	}
+
+	stop := feed.Stops["S1"]
+	stop.Name = "Centro Histórico"
+	feed.Stops["S1"] = stop
+
+	report, _ = service.Import(context.Background(), feed)
+	if report.BusStops != (ImportCount{Updated: 1, Skipped: 1}) {
+		t.Errorf("Paradas esperadas: 1 atualizada e 1 ignorada, recebido: %+v", report.BusStops)
+	}
+}
+
+func TestExport(t *testing.T) {
+	start := time.Date(2000, time.January, 1, 23, 30, 0, 0, time.UTC)
+	end := time.Date(2000, time.January, 1, 0, 20, 0, 0, time.UTC)
+	offset := int64(600)
+
+	mockRepo := newMockGtfsRepository()
+	mockRepo.busStops = []internal.BusStop{
+		{ID: 1, Name: "Centro", Latitude: -29.88, Longitude: -50.27},
+		{ID: 2, Name: "Hospital", Latitude: -29.885, Longitude: -50.275},
+		{ID: 3, Name: "Rodoviária", Latitude: -29.89, Longitude: -50.28},
+	}
+	mockRepo.metroStations = []internal.MetroStation{
+		{ID: 1, StationName: "Estação Mercado", Latitude: -30.02, Longitude: -51.22},
+	}
+	mockRepo.busLines = []internal.BusLine{
+		{
+			ID:      7,
+			Name:    "Centro - Rodoviária",
+			BusInit: internal.BusStop{ID: 1},
+			BusEnd:  internal.BusStop{ID: 3},
+			Stops: []internal.BusLineStop{
+				{BusStop: internal.BusStop{ID: 1}, Sequence: 1},
+				{BusStop: internal.BusStop{ID: 2}, Sequence: 2, TravelTimeOffset: &offset},
+				{BusStop: internal.BusStop{ID: 3}, Sequence: 3},
+			},
+			Schedules: []internal.BusSchedules{
+				{ID: 40, DayOfWeek: "Friday", StartTime: &start, EndTime: &end},
+			},
+		},
+		{ID: 8, Name: "Linha sem horários", BusInit: internal.BusStop{ID: 1}, BusEnd: internal.BusStop{ID: 3}},
+	}
+
+	feed, err := NewGtfsService(mockRepo).Export(context.Background(), Agency{Name: "Move Easy", URL: "https://example.com", Timezone: "America/Sao_Paulo"})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := WriteFeed(buffer, feed); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	parsed, err := ParseFeed(archive)
+	if err != nil {
+		t.Fatalf("Feed exportado não pôde ser lido: %v", err)
+	}
+
+	if len(parsed.Stops) != 4 {
+		t.Errorf("Paradas esperadas: 4, recebidas: %d", len(parsed.Stops))
+	}
+
+	if len(parsed.Routes) != 1 || !parsed.Routes["L7"].IsBus() {
+		t.Errorf("Apenas a linha com horários deveria ser exportada: %+v", parsed.Routes)
+	}
+
+	trip := parsed.Trips["T40"]
+	if trip == nil || len(trip.StopTimes) != 3 {
+		t.Fatalf("Viagem T40 não foi exportada: %+v", trip)
+	}
+
+	want := []time.Duration{23*time.Hour + 30*time.Minute, 23*time.Hour + 40*time.Minute, 24*time.Hour + 20*time.Minute}
+	for i, stopTime := range trip.StopTimes {
+		if stopTime.DepartureTime != want[i] {
+			t.Errorf("Horário esperado na parada %s: %s, recebido: %s", stopTime.StopID, FormatTime(want[i]), FormatTime(stopTime.DepartureTime))
+		}
+	}
+
+	if days := parsed.Calendars[trip.ServiceID].Days; len(days) != 1 || days[0] != time.Friday {
+		t.Errorf("Dias esperados: [Friday], recebidos: %v", days)
+	}
+}
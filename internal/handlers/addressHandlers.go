@@ -3,7 +3,9 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/amarantec/move-easy/internal"
@@ -69,6 +71,9 @@ func (h *AddressHandler) AddOrUpdateAddress(w http.ResponseWriter, r *http.Reque
 	}
 
 	response, err := h.service.AddOrUpdateAddress(ctxTimeout, addr)
+	if writeAddressValidationError(w, err) {
+		return
+	}
 	if err != nil {
 		http.Error(w,
 			"could not save this address, error: "+err.Error(),
@@ -82,3 +87,191 @@ func (h *AddressHandler) AddOrUpdateAddress(w http.ResponseWriter, r *http.Reque
 		"response": response,
 	})
 }
+
+func (h *AddressHandler) ListAddresses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	response, err := h.service.ListAddresses(ctxTimeout, userID)
+	if err != nil {
+		http.Error(w,
+			"could not get the address list, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *AddressHandler) CreateAddress(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	var addr internal.Address
+	if err :=
+		json.NewDecoder(r.Body).Decode(&addr); err != nil {
+		http.Error(w,
+			"could not decode this request, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+	addr.UserID = userID
+
+	response, err := h.service.CreateAddress(ctxTimeout, addr)
+	if writeAddressValidationError(w, err) {
+		return
+	}
+	if err != nil {
+		http.Error(w,
+			"could not create this address, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *AddressHandler) UpdateAddress(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	var addr internal.Address
+	if err :=
+		json.NewDecoder(r.Body).Decode(&addr); err != nil {
+		http.Error(w,
+			"could not decode this request, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+	addr.UserID = userID
+
+	response, err := h.service.UpdateAddress(ctxTimeout, addr)
+	if writeAddressValidationError(w, err) {
+		return
+	}
+	if err != nil {
+		http.Error(w,
+			"could not update this address, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *AddressHandler) DeleteAddress(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	addressID, err := strconv.ParseInt(r.PathValue("addressID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.DeleteAddress(ctxTimeout, userID, addressID)
+	if err != nil {
+		http.Error(w,
+			"could not delete this address, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNoContent)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *AddressHandler) SetPrimaryAddress(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	addressID, err := strconv.ParseInt(r.PathValue("addressID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.SetPrimaryAddress(ctxTimeout, userID, addressID)
+	if err != nil {
+		http.Error(w,
+			"could not set the primary address, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+// writeAddressValidationError answers with the fields that disagree with the
+// postal directory, reporting whether err was such an error.
+func writeAddressValidationError(w http.ResponseWriter, err error) bool {
+	var validationErr *address.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": validationErr.Fields,
+	})
+	return true
+}
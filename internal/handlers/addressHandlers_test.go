@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/address"
 	"github.com/amarantec/move-easy/internal/middleware"
 )
 
@@ -18,6 +19,11 @@ import (
 type mockAddressService struct {
 	GetAddressFunc         func(ctx context.Context, userID int64) (internal.Address, error)
 	AddOrUpdateAddressFunc func(ctx context.Context, address internal.Address) (int64, error)
+	ListAddressesFunc      func(ctx context.Context, userID int64) ([]internal.Address, error)
+	CreateAddressFunc      func(ctx context.Context, address internal.Address) (int64, error)
+	UpdateAddressFunc      func(ctx context.Context, address internal.Address) (bool, error)
+	DeleteAddressFunc      func(ctx context.Context, userID, addressID int64) (bool, error)
+	SetPrimaryAddressFunc  func(ctx context.Context, userID, addressID int64) (bool, error)
 }
 
 func (m *mockAddressService) GetAddress(ctx context.Context, userID int64) (internal.Address, error) {
@@ -28,6 +34,26 @@ func (m *mockAddressService) AddOrUpdateAddress(ctx context.Context, address int
 	return m.AddOrUpdateAddressFunc(ctx, address)
 }
 
+func (m *mockAddressService) ListAddresses(ctx context.Context, userID int64) ([]internal.Address, error) {
+	return m.ListAddressesFunc(ctx, userID)
+}
+
+func (m *mockAddressService) CreateAddress(ctx context.Context, address internal.Address) (int64, error) {
+	return m.CreateAddressFunc(ctx, address)
+}
+
+func (m *mockAddressService) UpdateAddress(ctx context.Context, address internal.Address) (bool, error) {
+	return m.UpdateAddressFunc(ctx, address)
+}
+
+func (m *mockAddressService) DeleteAddress(ctx context.Context, userID, addressID int64) (bool, error) {
+	return m.DeleteAddressFunc(ctx, userID, addressID)
+}
+
+func (m *mockAddressService) SetPrimaryAddress(ctx context.Context, userID, addressID int64) (bool, error) {
+	return m.SetPrimaryAddressFunc(ctx, userID, addressID)
+}
+
 // Test do handler GetAddress
 func TestAddressHandler_GetAddress(t *testing.T) {
 	mockService := &mockAddressService{
@@ -64,7 +90,7 @@ func TestAddressHandler_GetAddress(t *testing.T) {
 			name:       "Address Get Successfully",
 			userID:     1,
 			wantStatus: http.StatusOK,
-			wantResp:   `{"response":{"ID":1,"UserID":1,"Street":"General Osório","Number":"2211","CEP":"95520000","Neighborhood":"Glória","City":"Osório","State":"RS","CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":null,"DeletedAt":null}}`,
+			wantResp:   `{"response":{"ID":1,"UserID":1,"Label":"","IsPrimary":false,"Street":"General Osório","Number":"2211","CEP":"95520000","Neighborhood":"Glória","City":"Osório","State":"RS","Latitude":null,"Longitude":null,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":null,"DeletedAt":null}}`,
 		},
 		{
 			name:       "Missing userID",
@@ -135,6 +161,10 @@ func TestAddressHandler_AddOrUpdateAddress(t *testing.T) {
 				return internal.ZERO, ErrMissingAddressState
 			}
 
+			if address.CEP == "00000000" {
+				return internal.ZERO, ErrUnknownAddressCEP
+			}
+
 			return 1, nil
 		},
 	}
@@ -162,6 +192,13 @@ func TestAddressHandler_AddOrUpdateAddress(t *testing.T) {
 			wantStatus: http.StatusInternalServerError,
 			wantResp:   "could not save this address, error: missing user id",
 		},
+		{
+			name:       "Unknown CEP",
+			userID:     1,
+			inputBody:  `{"street": "General Osório", "number": "2211", "cep": "00000000", "neighborhood": "Glória", "city": "Osório", "state": "RS"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantResp:   `{"errors":[{"Field":"CEP","Message":"cep not found in the postal directory"}]}`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -195,3 +232,6 @@ var ErrMissingAddressCEP = errors.New("missing address cep")
 var ErrMissingAddressNeighborhood = errors.New("missing address neighborhood")
 var ErrMissingAddressCity = errors.New("missing address city")
 var ErrMissingAddressState = errors.New("missing address state")
+var ErrUnknownAddressCEP = &address.ValidationError{Fields: []address.FieldError{
+	{Field: "CEP", Message: "cep not found in the postal directory"},
+}}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/amarantec/move-easy/internal/alert"
+	"github.com/amarantec/move-easy/internal/middleware"
+)
+
+type AlertHandler struct {
+	service alert.IAlertService
+}
+
+func NewAlertHandler(service alert.IAlertService) *AlertHandler {
+	return &AlertHandler{service: service}
+}
+
+// SOS alerts the emergency contacts of the user. The body may carry the
+// current Latitude and Longitude; without them the last reported location is
+// sent. A user who alerted the contacts moments ago gets 429.
+func (h *AlertHandler) SOS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	var location struct {
+		Latitude  *float64
+		Longitude *float64
+	}
+	if err :=
+		json.NewDecoder(r.Body).Decode(&location); err != nil && err != io.EOF {
+		http.Error(w,
+			"could not decode this request, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.SendSOS(ctxTimeout, userID, location.Latitude, location.Longitude)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, alert.ErrAlertLocationInvalid):
+			status = http.StatusBadRequest
+		case errors.Is(err, alert.ErrAlertContactsEmpty):
+			status = http.StatusUnprocessableEntity
+		case errors.Is(err, alert.ErrAlertNotifierMissing):
+			status = http.StatusServiceUnavailable
+		case errors.Is(err, alert.ErrAlertNotDelivered):
+			status = http.StatusBadGateway
+		case errors.Is(err, alert.ErrAlertCooldown):
+			status = http.StatusTooManyRequests
+		}
+		http.Error(w,
+			"could not alert your contacts, error: "+err.Error(),
+			status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
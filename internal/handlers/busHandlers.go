@@ -128,3 +128,335 @@ func (h *BusHandler) GetBusStop(w http.ResponseWriter, r *http.Request) {
 		"response": response,
 	})
 }
+
+func (h *BusHandler) ListBusSchedules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	busLineID, err := strconv.ParseInt(r.PathValue("busLineID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.ListBusSchedules(ctxTimeout, busLineID)
+	if err != nil {
+		http.Error(w,
+			"could not list the bus schedules, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *BusHandler) AddBusSchedules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	h.saveBusSchedules(w, r, h.service.AddBusSchedules, http.StatusCreated)
+}
+
+func (h *BusHandler) ReplaceBusSchedules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	h.saveBusSchedules(w, r, h.service.ReplaceBusSchedules, http.StatusOK)
+}
+
+func (h *BusHandler) saveBusSchedules(w http.ResponseWriter, r *http.Request,
+	save func(ctx context.Context, busLineID int64, schedules []internal.BusSchedules) ([]int64, error), status int) {
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	busLineID, err := strconv.ParseInt(r.PathValue("busLineID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	schedules := []internal.BusSchedules{}
+	if err :=
+		json.NewDecoder(r.Body).Decode(&schedules); err != nil {
+		http.Error(w,
+			"could not decode this request, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := save(ctxTimeout, busLineID, schedules)
+	if err != nil {
+		http.Error(w,
+			"could not save the bus schedules, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *BusHandler) DeleteBusSchedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	busLineID, err := strconv.ParseInt(r.PathValue("busLineID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	scheduleID, err := strconv.ParseInt(r.PathValue("scheduleID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.DeleteBusSchedule(ctxTimeout, busLineID, scheduleID)
+	if err != nil {
+		http.Error(w,
+			"could not delete this bus schedule, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNoContent)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *BusHandler) NextDepartures(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	busLineID, err := strconv.ParseInt(r.PathValue("busLineID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	after := time.Time{}
+	if value := r.URL.Query().Get("after"); value != internal.EMPTY {
+		after, err = time.Parse("15:04", value)
+		if err != nil {
+			http.Error(w,
+				"invalid parameter, error: "+err.Error(),
+				http.StatusBadRequest)
+			return
+		}
+	}
+
+	response, err := h.service.NextDepartures(ctxTimeout, busLineID, r.URL.Query().Get("day"), after)
+	if err != nil {
+		http.Error(w,
+			"could not get the next departures, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *BusHandler) ListBusLineStops(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	busLineID, err := strconv.ParseInt(r.PathValue("busLineID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.ListBusLineStops(ctxTimeout, busLineID)
+	if err != nil {
+		http.Error(w,
+			"could not list the bus line stops, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *BusHandler) SetBusLineStops(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	busLineID, err := strconv.ParseInt(r.PathValue("busLineID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	stops := []internal.BusLineStop{}
+	if err :=
+		json.NewDecoder(r.Body).Decode(&stops); err != nil {
+		http.Error(w,
+			"could not decode this request, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.SetBusLineStops(ctxTimeout, busLineID, stops)
+	if err != nil {
+		http.Error(w,
+			"could not save the bus line stops, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *BusHandler) ListBusLinesByStop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	busStopID, err := strconv.ParseInt(r.PathValue("busStopID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.ListBusLinesByStop(ctxTimeout, busStopID)
+	if err != nil {
+		http.Error(w,
+			"could not list the bus lines of this stop, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *BusHandler) ListNearbyBusStops(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	latitude, err := queryFloat(r, "lat", true)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	longitude, err := queryFloat(r, "lon", true)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	radius, err := queryFloat(r, "radius", false)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.ListNearbyBusStops(ctxTimeout, latitude, longitude, radius)
+	if err != nil {
+		http.Error(w,
+			"could not list the nearby bus stops, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/commute"
+	"github.com/amarantec/move-easy/internal/middleware"
+)
+
+type CommuteHandler struct {
+	service commute.ICommuteService
+}
+
+func NewCommuteHandler(service commute.ICommuteService) *CommuteHandler {
+	return &CommuteHandler{service: service}
+}
+
+func (h *CommuteHandler) SavePlace(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	var place internal.SavedPlace
+	if err :=
+		json.NewDecoder(r.Body).Decode(&place); err != nil {
+		http.Error(w,
+			"could not decode this request, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+	place.UserID = userID
+
+	response, err := h.service.SavePlace(ctxTimeout, place)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, commute.ErrSavedPlaceNameEmpty) || errors.Is(err, commute.ErrSavedPlaceNameInvalid) ||
+			errors.Is(err, commute.ErrSavedPlaceLocationInvalid) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w,
+			"could not save this place, error: "+err.Error(),
+			status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *CommuteHandler) ListPlaces(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	response, err := h.service.ListPlaces(ctxTimeout, userID)
+	if err != nil {
+		http.Error(w,
+			"could not get the place list, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *CommuteHandler) DeletePlace(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	placeID, err := strconv.ParseInt(r.PathValue("placeID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.DeletePlace(ctxTimeout, userID, placeID)
+	if err != nil {
+		http.Error(w,
+			"could not delete this place, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNoContent)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+// MyCommute reads the names of the saved places to travel between, Home and
+// Work when omitted.
+func (h *CommuteHandler) MyCommute(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	response, err := h.service.MyCommute(ctxTimeout, userID, r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, commute.ErrSavedPlaceNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w,
+			"could not get this commute, error: "+err.Error(),
+			status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/amarantec/move-easy/internal/eta"
+)
+
+type EtaHandler struct {
+	service eta.IEtaService
+}
+
+func NewEtaHandler(service eta.IEtaService) *EtaHandler {
+	return &EtaHandler{service: service}
+}
+
+func (h *EtaHandler) ListArrivals(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	busStopID, err := strconv.ParseInt(r.PathValue("busStopID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.ListArrivals(ctxTimeout, busStopID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, eta.ErrArrivalStopNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w,
+			"could not list the arrivals of this stop, error: "+err.Error(),
+			status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/feedback"
+	"github.com/amarantec/move-easy/internal/middleware"
+)
+
+type FeedbackHandler struct {
+	service feedback.IFeedbackService
+}
+
+func NewFeedbackHandler(service feedback.IFeedbackService) *FeedbackHandler {
+	return &FeedbackHandler{service: service}
+}
+
+func (h *FeedbackHandler) InsertFeedback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	var userFeedback internal.UserFeedback
+
+	if err :=
+		json.NewDecoder(r.Body).Decode(&userFeedback); err != nil {
+		http.Error(w,
+			"could not decode this request, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+	userFeedback.UserID = userID
+
+	response, err := h.service.InsertFeedback(ctxTimeout, userFeedback)
+	if err != nil {
+		http.Error(w,
+			"could not insert this feedback, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *FeedbackHandler) GetFeedback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	feedbackID, err := strconv.ParseInt(r.PathValue("feedbackID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.GetFeedback(ctxTimeout, feedbackID)
+	if err != nil {
+		http.Error(w,
+			"could not get this feedback, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *FeedbackHandler) ListFeedback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	limit := internal.ZERO
+	if value := r.URL.Query().Get("limit"); value != internal.EMPTY {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			http.Error(w,
+				"invalid parameter, error: "+err.Error(),
+				http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	response, err := h.service.ListFeedback(ctxTimeout, r.URL.Query().Get("category"), limit)
+	if err != nil {
+		http.Error(w,
+			"could not list the feedback, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *FeedbackHandler) Vote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	feedbackID, err := strconv.ParseInt(r.PathValue("feedbackID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	var vote internal.UserVotes
+
+	if err :=
+		json.NewDecoder(r.Body).Decode(&vote); err != nil {
+		http.Error(w,
+			"could not decode this request, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+	vote.UserID = userID
+	vote.FeedbackID = feedbackID
+
+	response, err := h.service.Vote(ctxTimeout, vote)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, feedback.ErrFeedbackNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, feedback.ErrFeedbackOwnVote) {
+			status = http.StatusForbidden
+		} else if errors.Is(err, feedback.ErrVoteTypeInvalid) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w,
+			"could not save this vote, error: "+err.Error(),
+			status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *FeedbackHandler) DeleteVote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	feedbackID, err := strconv.ParseInt(r.PathValue("feedbackID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.DeleteVote(ctxTimeout, userID, feedbackID)
+	if err != nil {
+		http.Error(w,
+			"could not delete this vote, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNoContent)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/amarantec/move-easy/internal/gtfs"
+)
+
+type GtfsHandler struct {
+	service gtfs.IGtfsService
+	agency  gtfs.Agency
+}
+
+func NewGtfsHandler(service gtfs.IGtfsService, agency gtfs.Agency) *GtfsHandler {
+	return &GtfsHandler{service: service, agency: agency}
+}
+
+func (h *GtfsHandler) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	feed, err := h.service.Export(ctxTimeout, h.agency)
+	if err != nil {
+		http.Error(w,
+			"could not export the gtfs feed, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="gtfs.zip"`)
+	w.WriteHeader(http.StatusOK)
+
+	// The status line is already sent, so a failure here can only be logged.
+	if err := gtfs.WriteFeed(w, feed); err != nil {
+		log.Printf("could not write the gtfs feed, error: %v\n", err)
+	}
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/middleware"
+	"github.com/amarantec/move-easy/internal/occurrence"
+)
+
+type OccurrenceHandler struct {
+	service occurrence.IOccurrenceService
+}
+
+func NewOccurrenceHandler(service occurrence.IOccurrenceService) *OccurrenceHandler {
+	return &OccurrenceHandler{service: service}
+}
+
+func (h *OccurrenceHandler) InsertOccurrence(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	var occurrence internal.Occurrence
+
+	if err :=
+		json.NewDecoder(r.Body).Decode(&occurrence); err != nil {
+		http.Error(w,
+			"could not decode this request, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+	occurrence.UserID = userID
+
+	response, err := h.service.InsertOccurrence(ctxTimeout, occurrence)
+	if err != nil {
+		http.Error(w,
+			"could not insert this occurrence, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *OccurrenceHandler) GetOccurrence(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	occurrenceID, err := strconv.ParseInt(r.PathValue("occurrenceID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.GetOccurrence(ctxTimeout, occurrenceID)
+	if err != nil {
+		http.Error(w,
+			"could not get this occurrence, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *OccurrenceHandler) ListOccurrences(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := internal.OccurrenceFilter{}
+	query := r.URL.Query()
+
+	if value := query.Get("type"); value != internal.EMPTY {
+		occurrenceType, err := strconv.Atoi(value)
+		if err != nil {
+			http.Error(w,
+				"invalid parameter, error: "+err.Error(),
+				http.StatusBadRequest)
+			return
+		}
+		t := internal.OccurrenceType(occurrenceType)
+		filter.Type = &t
+	}
+
+	if value := query.Get("from"); value != internal.EMPTY {
+		from, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w,
+				"invalid parameter, error: "+err.Error(),
+				http.StatusBadRequest)
+			return
+		}
+		filter.From = from
+	}
+
+	if value := query.Get("to"); value != internal.EMPTY {
+		to, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w,
+				"invalid parameter, error: "+err.Error(),
+				http.StatusBadRequest)
+			return
+		}
+		filter.To = to
+	}
+
+	response, err := h.service.ListOccurrences(ctxTimeout, filter)
+	if err != nil {
+		http.Error(w,
+			"could not list the occurrences, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *OccurrenceHandler) ConfirmOccurrence(w http.ResponseWriter, r *http.Request) {
+	h.saveConfirmation(w, r, h.service.ConfirmOccurrence)
+}
+
+func (h *OccurrenceHandler) DisputeOccurrence(w http.ResponseWriter, r *http.Request) {
+	h.saveConfirmation(w, r, h.service.DisputeOccurrence)
+}
+
+func (h *OccurrenceHandler) saveConfirmation(w http.ResponseWriter, r *http.Request,
+	save func(ctx context.Context, userID, occurrenceID int64) (internal.Occurrence, error)) {
+	if r.Method != http.MethodPost {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	occurrenceID, err := strconv.ParseInt(r.PathValue("occurrenceID"), 10, 64)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := save(ctxTimeout, userID, occurrenceID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, occurrence.ErrOccurrenceNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, occurrence.ErrOccurrenceOwnConfirmation) {
+			status = http.StatusForbidden
+		}
+		http.Error(w,
+			"could not save this confirmation, error: "+err.Error(),
+			status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/middleware"
+	"github.com/amarantec/move-easy/internal/occurrence"
+)
+
+type mockOccurrenceService struct {
+	InsertOccurrenceFunc func(ctx context.Context, occurrence internal.Occurrence) (int64, error)
+	GetOccurrenceFunc    func(ctx context.Context, occurrenceID int64) (internal.Occurrence, error)
+	ListOccurrencesFunc  func(ctx context.Context, filter internal.OccurrenceFilter) ([]internal.Occurrence, error)
+	ConfirmFunc          func(ctx context.Context, userID, occurrenceID int64) (internal.Occurrence, error)
+	DisputeFunc          func(ctx context.Context, userID, occurrenceID int64) (internal.Occurrence, error)
+}
+
+func (s *mockOccurrenceService) InsertOccurrence(ctx context.Context, occurrence internal.Occurrence) (int64, error) {
+	if s.InsertOccurrenceFunc != nil {
+		return s.InsertOccurrenceFunc(ctx, occurrence)
+	}
+	return internal.ZERO, ErrInsertOccurrenceFuncNotImplemented
+}
+
+func (s *mockOccurrenceService) GetOccurrence(ctx context.Context, occurrenceID int64) (internal.Occurrence, error) {
+	if s.GetOccurrenceFunc != nil {
+		return s.GetOccurrenceFunc(ctx, occurrenceID)
+	}
+	return internal.Occurrence{}, ErrGetOccurrenceFuncNotImplemented
+}
+
+func (s *mockOccurrenceService) ListOccurrences(ctx context.Context, filter internal.OccurrenceFilter) ([]internal.Occurrence, error) {
+	if s.ListOccurrencesFunc != nil {
+		return s.ListOccurrencesFunc(ctx, filter)
+	}
+	return []internal.Occurrence{}, ErrListOccurrencesFuncNotImplemented
+}
+
+func (s *mockOccurrenceService) ConfirmOccurrence(ctx context.Context, userID, occurrenceID int64) (internal.Occurrence, error) {
+	if s.ConfirmFunc != nil {
+		return s.ConfirmFunc(ctx, userID, occurrenceID)
+	}
+	return internal.Occurrence{}, ErrConfirmFuncNotImplemented
+}
+
+func (s *mockOccurrenceService) DisputeOccurrence(ctx context.Context, userID, occurrenceID int64) (internal.Occurrence, error) {
+	if s.DisputeFunc != nil {
+		return s.DisputeFunc(ctx, userID, occurrenceID)
+	}
+	return internal.Occurrence{}, ErrDisputeFuncNotImplemented
+}
+
+func (s *mockOccurrenceService) ExpireUnconfirmedOccurrences(ctx context.Context, maxAge time.Duration) (int64, error) {
+	return internal.ZERO, nil
+}
+
+func TestOccurrenceHandler_InsertOccurrence(t *testing.T) {
+	mockService := &mockOccurrenceService{
+		InsertOccurrenceFunc: func(ctx context.Context, occurrence internal.Occurrence) (int64, error) {
+			if occurrence.UserID <= internal.ZERO {
+				return internal.ZERO, ErrMissingOccurrenceUserID
+			}
+			if !occurrence.Type.IsValid() {
+				return internal.ZERO, ErrInvalidOccurrenceType
+			}
+			return 1, nil
+		},
+	}
+
+	handler := NewOccurrenceHandler(mockService)
+
+	tests := []struct {
+		name       string
+		userID     int64
+		inputBody  string
+		wantStatus int
+		wantResp   string
+	}{
+		{
+			name:       "Ocorrência registrada com sucesso",
+			userID:     1,
+			inputBody:  `{"type": 2, "description": "ônibus parado", "latitude": -29.88, "longitude": -50.27}`,
+			wantStatus: http.StatusCreated,
+			wantResp:   `{"response":1}`,
+		},
+		{
+			name:       "UserID do corpo é ignorado",
+			userID:     internal.ZERO,
+			inputBody:  `{"userid": 5, "type": 1, "latitude": -29.88, "longitude": -50.27}`,
+			wantStatus: http.StatusInternalServerError,
+			wantResp:   "could not insert this occurrence, error: Error missing user ID",
+		},
+		{
+			name:       "Tipo inválido",
+			userID:     1,
+			inputBody:  `{"type": 7, "latitude": -29.88, "longitude": -50.27}`,
+			wantStatus: http.StatusInternalServerError,
+			wantResp:   "could not insert this occurrence, error: Error invalid occurrence type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/insert-occurrence", bytes.NewBufferString(tt.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+			ctx := context.WithValue(req.Context(), middleware.UserIDKey, tt.userID)
+			req = req.WithContext(ctx)
+			rec := httptest.NewRecorder()
+			handler.InsertOccurrence(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+			if res.StatusCode != tt.wantStatus {
+				t.Errorf("[%s] Status esperado %d, recebido %d", tt.name, tt.wantStatus, res.StatusCode)
+			}
+
+			var respBody bytes.Buffer
+			respBody.ReadFrom(res.Body)
+			respStr := respBody.String()
+			if respStr != tt.wantResp+"\n" {
+				t.Errorf("[%s] Resposta esperada: %s, recebida: %s", tt.name, tt.wantResp, respStr)
+			}
+		})
+	}
+}
+
+func TestOccurrenceHandler_ListOccurrences(t *testing.T) {
+	mockService := &mockOccurrenceService{
+		ListOccurrencesFunc: func(ctx context.Context, filter internal.OccurrenceFilter) ([]internal.Occurrence, error) {
+			if filter.Type != nil && *filter.Type != internal.ACCIDENT {
+				return nil, ErrInvalidOccurrenceType
+			}
+			return []internal.Occurrence{}, nil
+		},
+	}
+
+	handler := NewOccurrenceHandler(mockService)
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+	}{
+		{name: "Listagem sem filtros", url: "/list-occurrences", wantStatus: http.StatusOK},
+		{name: "Listagem por tipo", url: "/list-occurrences?type=1", wantStatus: http.StatusOK},
+		{name: "Tipo não numérico", url: "/list-occurrences?type=abc", wantStatus: http.StatusBadRequest},
+		{name: "Data inválida", url: "/list-occurrences?from=ontem", wantStatus: http.StatusBadRequest},
+		{
+			name:       "Janela de tempo",
+			url:        "/list-occurrences?from=2025-01-01T00:00:00Z&to=2025-01-02T00:00:00Z",
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rec := httptest.NewRecorder()
+			handler.ListOccurrences(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+			if res.StatusCode != tt.wantStatus {
+				t.Errorf("[%s] Status esperado %d, recebido %d", tt.name, tt.wantStatus, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestOccurrenceHandler_ConfirmOccurrence(t *testing.T) {
+	mockService := &mockOccurrenceService{
+		ConfirmFunc: func(ctx context.Context, userID, occurrenceID int64) (internal.Occurrence, error) {
+			switch {
+			case occurrenceID == 2:
+				return internal.Occurrence{}, occurrence.ErrOccurrenceNotFound
+			case userID == 10:
+				return internal.Occurrence{}, occurrence.ErrOccurrenceOwnConfirmation
+			}
+			return internal.Occurrence{ID: occurrenceID, Confirmation: 1}, nil
+		},
+	}
+
+	handler := NewOccurrenceHandler(mockService)
+
+	tests := []struct {
+		name         string
+		userID       int64
+		occurrenceID string
+		wantStatus   int
+	}{
+		{name: "Confirmação registrada", userID: 20, occurrenceID: "1", wantStatus: http.StatusOK},
+		{name: "Ocorrência inexistente", userID: 20, occurrenceID: "2", wantStatus: http.StatusNotFound},
+		{name: "Autor da ocorrência", userID: 10, occurrenceID: "1", wantStatus: http.StatusForbidden},
+		{name: "ID inválido", userID: 20, occurrenceID: "abc", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/confirm-occurrence/"+tt.occurrenceID, nil)
+			req.SetPathValue("occurrenceID", tt.occurrenceID)
+			ctx := context.WithValue(req.Context(), middleware.UserIDKey, tt.userID)
+			req = req.WithContext(ctx)
+			rec := httptest.NewRecorder()
+			handler.ConfirmOccurrence(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+			if res.StatusCode != tt.wantStatus {
+				t.Errorf("[%s] Status esperado %d, recebido %d", tt.name, tt.wantStatus, res.StatusCode)
+			}
+		})
+	}
+}
+
+var (
+	ErrConfirmFuncNotImplemented          = errors.New("Error ConfirmFunc not implemented")
+	ErrDisputeFuncNotImplemented          = errors.New("Error DisputeFunc not implemented")
+	ErrMissingOccurrenceUserID            = errors.New("Error missing user ID")
+	ErrInvalidOccurrenceType              = errors.New("Error invalid occurrence type")
+	ErrInsertOccurrenceFuncNotImplemented = errors.New("Error InsertOccurrenceFunc not implemented")
+	ErrGetOccurrenceFuncNotImplemented    = errors.New("Error GetOccurrenceFunc not implemented")
+	ErrListOccurrencesFuncNotImplemented  = errors.New("Error ListOccurrencesFunc not implemented")
+)
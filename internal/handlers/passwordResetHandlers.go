@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/user"
+)
+
+type PasswordResetHandler struct {
+	service user.IPasswordResetService
+}
+
+func NewPasswordResetHandler(service user.IPasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{service: service}
+}
+
+// ForgotPassword answers the same whether the e-mail is registered or not,
+// with 429 when the e-mail or the client asked too many times.
+func (h *PasswordResetHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var request struct {
+		Email string
+	}
+
+	if err :=
+		json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w,
+			"could not decode this request, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	clientIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		clientIP = r.RemoteAddr
+	}
+
+	if err := h.service.ForgotPassword(ctxTimeout, request.Email, clientIP); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, user.ErrResetEmailEmpty):
+			status = http.StatusBadRequest
+		case errors.Is(err, user.ErrMailerMissing):
+			status = http.StatusServiceUnavailable
+		case errors.Is(err, user.ErrResetThrottled):
+			status = http.StatusTooManyRequests
+		}
+		http.Error(w,
+			"could not start the password reset, error: "+err.Error(),
+			status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": "if this e-mail is registered, a reset code was sent to it",
+	})
+}
+
+func (h *PasswordResetHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var reset internal.PasswordReset
+	if err :=
+		json.NewDecoder(r.Body).Decode(&reset); err != nil {
+		http.Error(w,
+			"could not decode this request, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.ResetPassword(ctxTimeout, reset)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, user.ErrResetTokenInvalid) || errors.Is(err, user.ErrPasswordTooShort) ||
+			errors.Is(err, user.ErrPasswordTooLong) || errors.Is(err, user.ErrPasswordWeak) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w,
+			"could not reset the password, error: "+err.Error(),
+			status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/planner"
+)
+
+type PlannerHandler struct {
+	service planner.IPlannerService
+}
+
+func NewPlannerHandler(service planner.IPlannerService) *PlannerHandler {
+	return &PlannerHandler{service: service}
+}
+
+// Plan reads the from and to points as "latitude,longitude", an optional
+// RFC 3339 depart time and an optional limit of journeys.
+func (h *PlannerHandler) Plan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	from, err := queryPoint(r, "from")
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	to, err := queryPoint(r, "to")
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	depart := time.Time{}
+	if value := r.URL.Query().Get("depart"); value != internal.EMPTY {
+		if depart, err = time.Parse(time.RFC3339, value); err != nil {
+			http.Error(w,
+				"invalid parameter, error: "+err.Error(),
+				http.StatusBadRequest)
+			return
+		}
+	}
+
+	limit := internal.ZERO
+	if value := r.URL.Query().Get("limit"); value != internal.EMPTY {
+		if limit, err = strconv.Atoi(value); err != nil {
+			http.Error(w,
+				"invalid parameter, error: "+err.Error(),
+				http.StatusBadRequest)
+			return
+		}
+	}
+
+	response, err := h.service.Plan(ctxTimeout, from, to, depart, limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, planner.ErrPlanLocationInvalid) || errors.Is(err, planner.ErrPlanLimitInvalid) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w,
+			"could not plan this journey, error: "+err.Error(),
+			status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
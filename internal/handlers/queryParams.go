@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+func queryFloat(r *http.Request, name string, required bool) (float64, error) {
+	value := r.URL.Query().Get(name)
+	if value == internal.EMPTY {
+		if required {
+			return internal.ZERO, fmt.Errorf("query parameter %s is required", name)
+		}
+		return internal.ZERO, nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return internal.ZERO, fmt.Errorf("query parameter %s must be a number", name)
+	}
+
+	return parsed, nil
+}
+
+// queryPoint reads a required "latitude,longitude" query parameter.
+func queryPoint(r *http.Request, name string) (internal.JourneyPlace, error) {
+	value := r.URL.Query().Get(name)
+	if value == internal.EMPTY {
+		return internal.JourneyPlace{}, fmt.Errorf("query parameter %s is required", name)
+	}
+
+	latitude, longitude, found := strings.Cut(value, ",")
+	if !found {
+		return internal.JourneyPlace{}, fmt.Errorf("query parameter %s must be latitude,longitude", name)
+	}
+
+	point := internal.JourneyPlace{}
+	var err error
+	if point.Latitude, err = strconv.ParseFloat(strings.TrimSpace(latitude), 64); err != nil {
+		return internal.JourneyPlace{}, fmt.Errorf("query parameter %s must be latitude,longitude", name)
+	}
+	if point.Longitude, err = strconv.ParseFloat(strings.TrimSpace(longitude), 64); err != nil {
+		return internal.JourneyPlace{}, fmt.Errorf("query parameter %s must be latitude,longitude", name)
+	}
+
+	return point, nil
+}
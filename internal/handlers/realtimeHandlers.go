@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/location"
+	"github.com/amarantec/move-easy/internal/realtime"
+	"github.com/amarantec/move-easy/internal/sharedVehicle"
+)
+
+// HEARTBEAT_INTERVAL keeps idle streams open through proxies that close
+// connections without traffic.
+const HEARTBEAT_INTERVAL = 25 * time.Second
+
+type RealtimeHandler struct {
+	hub                  *realtime.Hub
+	locationService      location.ILocationService
+	sharedVehicleService sharedVehicle.ISharedVehicleService
+}
+
+func NewRealtimeHandler(hub *realtime.Hub, locationService location.ILocationService, sharedVehicleService sharedVehicle.ISharedVehicleService) *RealtimeHandler {
+	return &RealtimeHandler{hub: hub, locationService: locationService, sharedVehicleService: sharedVehicleService}
+}
+
+// Stream sends, as Server-Sent Events, every event inside the bounding box
+// given by the min_lat, min_lon, max_lat and max_lon query parameters until
+// the client disconnects.
+func (h *RealtimeHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+
+	box, err := queryBoundingBox(r)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	controller := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	if err := controller.Flush(); err != nil {
+		return
+	}
+
+	subscription := h.hub.Subscribe(box)
+	defer h.hub.Unsubscribe(subscription)
+
+	heartbeat := time.NewTicker(HEARTBEAT_INTERVAL)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(event.Data)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		}
+
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func queryBoundingBox(r *http.Request) (internal.BoundingBox, error) {
+	box := internal.BoundingBox{}
+	params := []struct {
+		name  string
+		value *float64
+	}{
+		{"min_lat", &box.MinLatitude},
+		{"min_lon", &box.MinLongitude},
+		{"max_lat", &box.MaxLatitude},
+		{"max_lon", &box.MaxLongitude},
+	}
+
+	for _, param := range params {
+		value, err := queryFloat(r, param.name, true)
+		if err != nil {
+			return internal.BoundingBox{}, err
+		}
+		*param.value = value
+	}
+
+	if !box.IsValid() {
+		return internal.BoundingBox{}, errors.New("bounding box is out of range or its minimum is above its maximum")
+	}
+
+	return box, nil
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/middleware"
+	"github.com/amarantec/move-easy/internal/realtime"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	SOCKET_WRITE_TIMEOUT = 10 * time.Second
+	// SOCKET_PONG_TIMEOUT closes connections that stopped answering pings,
+	// which are sent every SOCKET_PING_INTERVAL.
+	SOCKET_PONG_TIMEOUT  = 60 * time.Second
+	SOCKET_PING_INTERVAL = 50 * time.Second
+	SOCKET_MESSAGE_LIMIT = 4096
+	// SOCKET_OUTBOX is how many replies may wait for the writer before the
+	// connection is considered too slow and closed.
+	SOCKET_OUTBOX = 16
+)
+
+// upgrader keeps the default same-origin check: the socket is authenticated
+// by cookie, so a page from another origin must not be able to open it.
+// Native clients send no Origin header and are accepted.
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// Socket upgrades an authenticated request to a WebSocket where the client
+// publishes location pings and shared vehicle moves, and receives the events
+// of the bounding box it subscribed to.
+func (h *RealtimeHandler) Socket(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	outbox := make(chan internal.RealtimeMessage, SOCKET_OUTBOX)
+	subscribe := make(chan internal.BoundingBox)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	defer close(stopped)
+
+	go func() {
+		defer close(done)
+		h.readSocket(conn, userID, outbox, subscribe, stopped)
+	}()
+
+	h.writeSocket(conn, outbox, subscribe, done)
+}
+
+// readSocket handles the client requests one at a time until the connection
+// fails. Only the writer touches the subscription, so new bounds are handed
+// over through subscribe, unless the writer already stopped.
+func (h *RealtimeHandler) readSocket(conn *websocket.Conn, userID int64, outbox chan<- internal.RealtimeMessage, subscribe chan<- internal.BoundingBox, stopped <-chan struct{}) {
+	conn.SetReadLimit(SOCKET_MESSAGE_LIMIT)
+	conn.SetReadDeadline(time.Now().Add(SOCKET_PONG_TIMEOUT))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(SOCKET_PONG_TIMEOUT))
+	})
+
+	for {
+		message := internal.RealtimeMessage{}
+		if err := conn.ReadJSON(&message); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived, websocket.CloseAbnormalClosure) {
+				log.Printf("could not read the realtime message of user %d: %v\n", userID, err)
+			}
+			return
+		}
+
+		reply := h.handleSocketMessage(userID, message)
+		if reply.Type == internal.ACK && message.Type == internal.SUBSCRIBE {
+			select {
+			case subscribe <- *message.Bounds:
+			case <-stopped:
+				return
+			}
+		}
+
+		select {
+		case outbox <- reply:
+		default:
+			return
+		}
+	}
+}
+
+func (h *RealtimeHandler) handleSocketMessage(userID int64, message internal.RealtimeMessage) internal.RealtimeMessage {
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	reply := internal.RealtimeMessage{ID: message.ID, Type: internal.ACK}
+	fail := func(reason string) internal.RealtimeMessage {
+		return internal.RealtimeMessage{ID: message.ID, Type: internal.FAILURE, Error: reason}
+	}
+
+	switch message.Type {
+	case internal.SUBSCRIBE:
+		if message.Bounds == nil || !message.Bounds.IsValid() {
+			return fail("bounding box is out of range or its minimum is above its maximum")
+		}
+
+	case internal.REPORT_LOCATION:
+		if message.Location == nil {
+			return fail("location is required")
+		}
+
+		userLocation := *message.Location
+		userLocation.UserID = userID
+		response, err := h.locationService.ReportLocation(ctxTimeout, userLocation)
+		if err != nil {
+			return fail("could not report this location, error: " + err.Error())
+		}
+		reply.Response = response
+
+	case internal.UPDATE_SHARED_VEHICLE:
+		if message.SharedVehicle == nil {
+			return fail("shared vehicle is required")
+		}
+
+		vehicle := *message.SharedVehicle
+		vehicle.UserID = userID
+		response, err := h.sharedVehicleService.UpdateSharedVehicleLocation(ctxTimeout, vehicle)
+		if err != nil {
+			return fail("could not update this shared vehicle location, error: " + err.Error())
+		}
+		reply.Response = response
+
+	default:
+		return fail("unknown message type " + message.Type)
+	}
+
+	return reply
+}
+
+// writeSocket is the only writer of the connection, as gorilla/websocket
+// requires. It sends replies, subscribed events and pings until the reader
+// stops.
+func (h *RealtimeHandler) writeSocket(conn *websocket.Conn, outbox <-chan internal.RealtimeMessage, subscribe <-chan internal.BoundingBox, done <-chan struct{}) {
+	var subscription *realtime.Subscription
+	var events <-chan internal.RealtimeEvent
+	defer func() {
+		if subscription != nil {
+			h.hub.Unsubscribe(subscription)
+		}
+	}()
+
+	ping := time.NewTicker(SOCKET_PING_INTERVAL)
+	defer ping.Stop()
+
+	write := func(message internal.RealtimeMessage) bool {
+		conn.SetWriteDeadline(time.Now().Add(SOCKET_WRITE_TIMEOUT))
+		return conn.WriteJSON(message) == nil
+	}
+
+	for {
+		select {
+		case <-done:
+			return
+
+		case box := <-subscribe:
+			if subscription != nil {
+				h.hub.Unsubscribe(subscription)
+			}
+			subscription = h.hub.Subscribe(box)
+			events = subscription.Events
+
+		case message := <-outbox:
+			if !write(message) {
+				return
+			}
+
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if !write(internal.RealtimeMessage{Type: string(event.Type), Data: event.Data}) {
+				return
+			}
+
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(SOCKET_WRITE_TIMEOUT))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
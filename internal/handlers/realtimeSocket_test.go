@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/location"
+	"github.com/amarantec/move-easy/internal/middleware"
+	"github.com/amarantec/move-easy/internal/realtime"
+	"github.com/gorilla/websocket"
+)
+
+type mockLocationService struct {
+	location.ILocationService
+	ReportLocationFunc func(ctx context.Context, location internal.UserLocation) (int64, error)
+}
+
+func (s *mockLocationService) ReportLocation(ctx context.Context, location internal.UserLocation) (int64, error) {
+	return s.ReportLocationFunc(ctx, location)
+}
+
+func TestRealtimeHandler_Socket(t *testing.T) {
+	hub := realtime.NewHub()
+	locationService := &mockLocationService{
+		ReportLocationFunc: func(ctx context.Context, location internal.UserLocation) (int64, error) {
+			if location.UserID != 7 {
+				t.Errorf("Usuário esperado: 7, recebido: %d", location.UserID)
+			}
+			return 42, nil
+		},
+	}
+	handler := NewRealtimeHandler(hub, locationService, nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), middleware.UserIDKey, int64(7))
+		handler.Socket(w, r.WithContext(ctx))
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Erro inesperado ao conectar: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	tests := []struct {
+		name     string
+		request  internal.RealtimeMessage
+		wantType string
+		wantResp interface{}
+	}{
+		{
+			name:     "Localização publicada",
+			request:  internal.RealtimeMessage{ID: 1, Type: internal.REPORT_LOCATION, Location: &internal.UserLocation{LineID: 2, Latitude: -29.88, Longitude: -50.27}},
+			wantType: internal.ACK,
+			wantResp: float64(42),
+		},
+		{
+			name:     "Localização ausente",
+			request:  internal.RealtimeMessage{ID: 2, Type: internal.REPORT_LOCATION},
+			wantType: internal.FAILURE,
+		},
+		{
+			name:     "Área inválida",
+			request:  internal.RealtimeMessage{ID: 3, Type: internal.SUBSCRIBE, Bounds: &internal.BoundingBox{MinLatitude: 10, MaxLatitude: -10}},
+			wantType: internal.FAILURE,
+		},
+		{
+			name:     "Tipo desconhecido",
+			request:  internal.RealtimeMessage{ID: 4, Type: "dance"},
+			wantType: internal.FAILURE,
+		},
+		{
+			name:     "Inscrição na área",
+			request:  internal.RealtimeMessage{ID: 5, Type: internal.SUBSCRIBE, Bounds: &internal.BoundingBox{MinLatitude: -30, MinLongitude: -51, MaxLatitude: -29, MaxLongitude: -50}},
+			wantType: internal.ACK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := conn.WriteJSON(tt.request); err != nil {
+				t.Fatalf("[%s] Erro inesperado ao enviar: %v", tt.name, err)
+			}
+
+			reply := internal.RealtimeMessage{}
+			if err := conn.ReadJSON(&reply); err != nil {
+				t.Fatalf("[%s] Erro inesperado ao receber: %v", tt.name, err)
+			}
+
+			if reply.ID != tt.request.ID || reply.Type != tt.wantType {
+				t.Errorf("[%s] Resposta esperada: %d %s, recebida: %d %s (%s)", tt.name, tt.request.ID, tt.wantType, reply.ID, reply.Type, reply.Error)
+			}
+
+			if reply.Response != tt.wantResp {
+				t.Errorf("[%s] Resposta esperada: %v, recebida: %v", tt.name, tt.wantResp, reply.Response)
+			}
+		})
+	}
+
+	// O escritor se inscreve antes de enviar o ACK, então o evento já é entregue.
+	hub.Publish(internal.RealtimeEvent{Type: internal.OCCURRENCE_CREATED, Bounds: internal.PointBox(-29.88, -50.27), Data: 9})
+
+	event := internal.RealtimeMessage{}
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("Erro inesperado ao receber evento: %v", err)
+	}
+
+	if event.Type != string(internal.OCCURRENCE_CREATED) || event.Data != float64(9) {
+		t.Errorf("Evento de ocorrência esperado: %+v", event)
+	}
+}
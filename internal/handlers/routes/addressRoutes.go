@@ -11,6 +11,11 @@ func addressRoutes(handler *handlers.AddressHandler) *http.ServeMux {
 
 	addrMux.HandleFunc("/get-address", middleware.Authenticate(handler.GetAddress))
 	addrMux.HandleFunc("/save-address", middleware.Authenticate(handler.AddOrUpdateAddress))
+	addrMux.HandleFunc("/list-addresses", middleware.Authenticate(handler.ListAddresses))
+	addrMux.HandleFunc("/create-address", middleware.Authenticate(handler.CreateAddress))
+	addrMux.HandleFunc("/update-address", middleware.Authenticate(handler.UpdateAddress))
+	addrMux.HandleFunc("/delete-address/{addressID}", middleware.Authenticate(handler.DeleteAddress))
+	addrMux.HandleFunc("/set-primary-address/{addressID}", middleware.Authenticate(handler.SetPrimaryAddress))
 
 	return addrMux
 }
@@ -1,18 +1,30 @@
 package routes
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/amarantec/move-easy/internal/handlers"
+	"github.com/amarantec/move-easy/internal/middleware"
 )
 
-func busRoutes(handler *handlers.BusHandler) *http.ServeMux {
+func busRoutes(handler *handlers.BusHandler, etaHandler *handlers.EtaHandler, isAdmin func(ctx context.Context, userID int64) (bool, error)) *http.ServeMux {
 	busMux := http.NewServeMux()
 
 	busMux.HandleFunc("/insert-new-bus-line", handler.InsertNewBusLine)
 	busMux.HandleFunc("/insert-bus-stop", handler.InsertBusStop)
 	busMux.HandleFunc("/get-bus-line/{busLineID}", handler.GetBusLine)
 	busMux.HandleFunc("/get-bus-stop/{busStopID}", handler.GetBusStop)
+	busMux.HandleFunc("/list-bus-schedules/{busLineID}", handler.ListBusSchedules)
+	busMux.HandleFunc("/add-bus-schedules/{busLineID}", middleware.Authenticate(middleware.RequireAdmin(isAdmin, handler.AddBusSchedules)))
+	busMux.HandleFunc("/replace-bus-schedules/{busLineID}", middleware.Authenticate(middleware.RequireAdmin(isAdmin, handler.ReplaceBusSchedules)))
+	busMux.HandleFunc("/delete-bus-schedule/{busLineID}/{scheduleID}", middleware.Authenticate(middleware.RequireAdmin(isAdmin, handler.DeleteBusSchedule)))
+	busMux.HandleFunc("/next-departures/{busLineID}", handler.NextDepartures)
+	busMux.HandleFunc("/list-bus-line-stops/{busLineID}", handler.ListBusLineStops)
+	busMux.HandleFunc("/set-bus-line-stops/{busLineID}", middleware.Authenticate(handler.SetBusLineStops))
+	busMux.HandleFunc("/list-bus-lines-by-stop/{busStopID}", handler.ListBusLinesByStop)
+	busMux.HandleFunc("/list-nearby-bus-stops", handler.ListNearbyBusStops)
+	busMux.HandleFunc("/stop/{busStopID}/arrivals", etaHandler.ListArrivals)
 
 	return busMux
 }
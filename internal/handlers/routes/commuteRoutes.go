@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/amarantec/move-easy/internal/handlers"
+	"github.com/amarantec/move-easy/internal/middleware"
+)
+
+func commuteRoutes(handler *handlers.CommuteHandler) *http.ServeMux {
+	commuteMux := http.NewServeMux()
+
+	commuteMux.HandleFunc("/save-place", middleware.Authenticate(handler.SavePlace))
+	commuteMux.HandleFunc("/list-places", middleware.Authenticate(handler.ListPlaces))
+	commuteMux.HandleFunc("/delete-place/{placeID}", middleware.Authenticate(handler.DeletePlace))
+	commuteMux.HandleFunc("/my-commute", middleware.Authenticate(handler.MyCommute))
+
+	return commuteMux
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/amarantec/move-easy/internal/middleware"
 )
 
-func contactRoutes(handler *handlers.ContactHandler) *http.ServeMux {
+func contactRoutes(handler *handlers.ContactHandler, alertHandler *handlers.AlertHandler) *http.ServeMux {
 	contactMux := http.NewServeMux()
 
 	contactMux.HandleFunc("/save-contact", middleware.Authenticate(handler.SaveContact))
@@ -15,6 +15,7 @@ func contactRoutes(handler *handlers.ContactHandler) *http.ServeMux {
 	contactMux.HandleFunc("/list-contacts", middleware.Authenticate(handler.ListContacts))
 	contactMux.HandleFunc("/update-contact", middleware.Authenticate(handler.UpdateContact))
 	contactMux.HandleFunc("/delete-contact/{contactID}", middleware.Authenticate(handler.DeleteContact))
+	contactMux.HandleFunc("/sos", middleware.Authenticate(alertHandler.SOS))
 
 	return contactMux
 }
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/amarantec/move-easy/internal/handlers"
+	"github.com/amarantec/move-easy/internal/middleware"
+)
+
+func feedbackRoutes(handler *handlers.FeedbackHandler) *http.ServeMux {
+	feedbackMux := http.NewServeMux()
+
+	feedbackMux.HandleFunc("/insert-feedback", middleware.Authenticate(handler.InsertFeedback))
+	feedbackMux.HandleFunc("/get-feedback/{feedbackID}", handler.GetFeedback)
+	feedbackMux.HandleFunc("/list-feedback", handler.ListFeedback)
+	feedbackMux.HandleFunc("/vote/{feedbackID}", middleware.Authenticate(handler.Vote))
+	feedbackMux.HandleFunc("/delete-vote/{feedbackID}", middleware.Authenticate(handler.DeleteVote))
+
+	return feedbackMux
+}
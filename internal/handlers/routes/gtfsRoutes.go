@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/amarantec/move-easy/internal/handlers"
+	"github.com/amarantec/move-easy/internal/middleware"
+)
+
+func gtfsRoutes(handler *handlers.GtfsHandler, isAdmin func(ctx context.Context, userID int64) (bool, error)) *http.ServeMux {
+	gtfsMux := http.NewServeMux()
+
+	gtfsMux.HandleFunc("/export", middleware.Authenticate(middleware.RequireAdmin(isAdmin, handler.Export)))
+
+	return gtfsMux
+}
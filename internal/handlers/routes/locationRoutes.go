@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/amarantec/move-easy/internal/handlers"
+	"github.com/amarantec/move-easy/internal/middleware"
+)
+
+func locationRoutes(handler *handlers.LocationHandler) *http.ServeMux {
+	locationMux := http.NewServeMux()
+
+	locationMux.HandleFunc("/report-location", middleware.Authenticate(handler.ReportLocation))
+	locationMux.HandleFunc("/bus-positions/{busLineID}", handler.EstimateBusPositions)
+
+	return locationMux
+}
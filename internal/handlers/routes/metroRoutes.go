@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/amarantec/move-easy/internal/handlers"
+	"github.com/amarantec/move-easy/internal/middleware"
+)
+
+func metroRoutes(handler *handlers.MetroHandler, isAdmin func(ctx context.Context, userID int64) (bool, error)) *http.ServeMux {
+	metroMux := http.NewServeMux()
+
+	metroMux.HandleFunc("/insert-metro-station", middleware.Authenticate(middleware.RequireAdmin(isAdmin, handler.InsertMetroStation)))
+	metroMux.HandleFunc("/get-metro-station/{stationID}", handler.GetMetroStation)
+	metroMux.HandleFunc("/list-metro-stations", handler.ListMetroStations)
+	metroMux.HandleFunc("/delete-metro-station/{stationID}", middleware.Authenticate(middleware.RequireAdmin(isAdmin, handler.DeleteMetroStation)))
+	metroMux.HandleFunc("/nearest-metro-stations", handler.ListNearestMetroStations)
+
+	return metroMux
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/amarantec/move-easy/internal/handlers"
+	"github.com/amarantec/move-easy/internal/middleware"
+)
+
+func occurrenceRoutes(handler *handlers.OccurrenceHandler) *http.ServeMux {
+	occurrenceMux := http.NewServeMux()
+
+	occurrenceMux.HandleFunc("/insert-occurrence", middleware.Authenticate(handler.InsertOccurrence))
+	occurrenceMux.HandleFunc("/get-occurrence/{occurrenceID}", handler.GetOccurrence)
+	occurrenceMux.HandleFunc("/list-occurrences", handler.ListOccurrences)
+	occurrenceMux.HandleFunc("/confirm-occurrence/{occurrenceID}", middleware.Authenticate(handler.ConfirmOccurrence))
+	occurrenceMux.HandleFunc("/dispute-occurrence/{occurrenceID}", middleware.Authenticate(handler.DisputeOccurrence))
+
+	return occurrenceMux
+}
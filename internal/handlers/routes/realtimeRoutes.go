@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/amarantec/move-easy/internal/handlers"
+	"github.com/amarantec/move-easy/internal/middleware"
+)
+
+func realtimeRoutes(handler *handlers.RealtimeHandler) *http.ServeMux {
+	realtimeMux := http.NewServeMux()
+
+	realtimeMux.HandleFunc("/stream", handler.Stream)
+	realtimeMux.HandleFunc("/ws", middleware.Authenticate(handler.Socket))
+
+	return realtimeMux
+}
@@ -1,34 +1,82 @@
 package routes
 
 import (
+	"log"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/amarantec/move-easy/internal/address"
+	"github.com/amarantec/move-easy/internal/alert"
 	"github.com/amarantec/move-easy/internal/bus"
+	"github.com/amarantec/move-easy/internal/commute"
 	"github.com/amarantec/move-easy/internal/contact"
+	"github.com/amarantec/move-easy/internal/eta"
+	"github.com/amarantec/move-easy/internal/feedback"
+	"github.com/amarantec/move-easy/internal/geocoding"
+	"github.com/amarantec/move-easy/internal/gtfs"
 	"github.com/amarantec/move-easy/internal/handlers"
+	"github.com/amarantec/move-easy/internal/location"
+	"github.com/amarantec/move-easy/internal/mailer"
+	"github.com/amarantec/move-easy/internal/metro"
+	"github.com/amarantec/move-easy/internal/middleware"
+	"github.com/amarantec/move-easy/internal/notifier"
+	"github.com/amarantec/move-easy/internal/occurrence"
+	"github.com/amarantec/move-easy/internal/planner"
+	"github.com/amarantec/move-easy/internal/postal"
+	"github.com/amarantec/move-easy/internal/realtime"
 	"github.com/amarantec/move-easy/internal/sharedVehicle"
 	"github.com/amarantec/move-easy/internal/user"
+	"github.com/amarantec/move-easy/internal/utils"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func SetRoutes(conn *pgxpool.Pool) *http.ServeMux {
+// SetRoutes wires the handlers on conn. The returned function waits for the
+// work the handlers left running in the background and is called on shutdown.
+func SetRoutes(conn *pgxpool.Pool) (*http.ServeMux, func()) {
 	mux := http.NewServeMux()
 
+	/*
+		Realtime Dependency Injection
+	*/
+	realtimeHub := realtime.NewHub()
+
 	/*
 	   Address Dependency Injection
 	*/
 	addrRepository := address.NewAddressRepository(conn)
-	addrService := address.NewAddressService(addrRepository)
+	geocoder, err := geocoding.FromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	directory, err := postal.FromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	addrService := address.NewAddressService(addrRepository, geocoder, directory)
 	addrHandler := handlers.NewAddressHandler(addrService)
 
+	/*
+		Mailer Dependency Injection
+	*/
+	mail, err := mailer.FromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	/*
 	   User Dependency Injection
 	*/
 
 	userRepository := user.NewUserRepository(conn)
-	userService := user.NewUserService(userRepository)
+	userService := user.NewUserService(userRepository,
+		utils.GetEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		utils.GetEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour))
+	middleware.SetSessionValidator(userService.IsSessionActive)
 	userHandler := handlers.NewUserHandler(userService)
+	passwordResetService := user.NewPasswordResetService(userRepository, mail,
+		utils.GetEnvDuration("PASSWORD_RESET_TTL", time.Hour), os.Getenv("PASSWORD_RESET_URL"))
+	passwordResetHandler := handlers.NewPasswordResetHandler(passwordResetService)
 
 	/*
 	   Contact Dependency Injection
@@ -38,12 +86,24 @@ func SetRoutes(conn *pgxpool.Pool) *http.ServeMux {
 	contactService := contact.NewContactService(contactRepository)
 	contactHandler := handlers.NewContactHandler(contactService)
 
+	/*
+		Alert Dependency Injection
+	*/
+	notifiers, err := notifier.FromEnv(mail)
+	if err != nil {
+		log.Fatal(err)
+	}
+	alertService := alert.NewAlertService(alert.NewAlertRepository(conn), contactService, notifiers,
+		utils.GetEnvDuration("ALERT_COOLDOWN", 5*time.Minute))
+	alertHandler := handlers.NewAlertHandler(alertService)
+
 	/*
 		Shared Vehicle Dependency Injection
 	*/
 
 	sharedVehicleRepository := sharedVehicle.NewSharedVehicleRepository(conn)
-	sharedVehicleService := sharedVehicle.NewSharedVehicleService(sharedVehicleRepository)
+	sharedVehicleService := sharedVehicle.NewSharedVehicleService(sharedVehicleRepository,
+		utils.GetEnvDuration("SHARED_VEHICLE_FRESHNESS", 30*time.Minute), realtimeHub)
 	sharedVehicleHandler := handlers.NewSharedVehicleHandler(sharedVehicleService)
 
 	/*
@@ -53,14 +113,79 @@ func SetRoutes(conn *pgxpool.Pool) *http.ServeMux {
 	busService := bus.NewBusService(busRepository)
 	busHandler := handlers.NewBusHandler(busService)
 
+	/*
+		Metro Dependency Injection
+	*/
+	metroRepository := metro.NewMetroRepository(conn)
+	metroService := metro.NewMetroService(metroRepository)
+	metroHandler := handlers.NewMetroHandler(metroService)
+
+	/*
+		Occurrence Dependency Injection
+	*/
+	occurrenceRepository := occurrence.NewOccurrenceRepository(conn)
+	occurrenceService := occurrence.NewOccurrenceService(occurrenceRepository, realtimeHub, alertService)
+	occurrenceHandler := handlers.NewOccurrenceHandler(occurrenceService)
+
+	/*
+		Feedback Dependency Injection
+	*/
+	feedbackRepository := feedback.NewFeedbackRepository(conn)
+	feedbackService := feedback.NewFeedbackService(feedbackRepository)
+	feedbackHandler := handlers.NewFeedbackHandler(feedbackService)
+
+	/*
+		GTFS Dependency Injection
+	*/
+	gtfsRepository := gtfs.NewGtfsRepository(conn)
+	gtfsService := gtfs.NewGtfsService(gtfsRepository)
+	gtfsHandler := handlers.NewGtfsHandler(gtfsService, gtfs.AgencyFromEnv())
+
+	/*
+		Location Dependency Injection
+	*/
+	locationRepository := location.NewLocationRepository(conn)
+	locationService := location.NewLocationService(locationRepository, realtimeHub)
+	locationHandler := handlers.NewLocationHandler(locationService)
+
+	/*
+		ETA Dependency Injection
+	*/
+	etaService := eta.NewEtaService(busService, locationService)
+	etaHandler := handlers.NewEtaHandler(etaService)
+
+	/*
+		Planner Dependency Injection
+	*/
+	plannerService := planner.NewPlannerService(gtfsRepository)
+	plannerHandler := handlers.NewPlannerHandler(plannerService)
+
+	/*
+		Commute Dependency Injection
+	*/
+	commuteRepository := commute.NewCommuteRepository(conn)
+	commuteService := commute.NewCommuteService(commuteRepository, busService, addrService)
+	commuteHandler := handlers.NewCommuteHandler(commuteService)
+
+	realtimeHandler := handlers.NewRealtimeHandler(realtimeHub, locationService, sharedVehicleService)
+
 	/*
 	   Routes
 	*/
 
-	mux.Handle("/user/", http.StripPrefix("/user", userRoutes(userHandler)))
+	mux.Handle("/user/", http.StripPrefix("/user", userRoutes(userHandler, passwordResetHandler)))
 	mux.Handle("/address/", http.StripPrefix("/address", addressRoutes(addrHandler)))
-	mux.Handle("/contact/", http.StripPrefix("/contact", contactRoutes(contactHandler)))
+	mux.Handle("/contact/", http.StripPrefix("/contact", contactRoutes(contactHandler, alertHandler)))
 	mux.Handle("/shared-vehicle/", http.StripPrefix("/shared-vehicle", sharedVehicleRoutes(sharedVehicleHandler)))
-	mux.Handle("/bus/", http.StripPrefix("/bus", busRoutes(busHandler)))
-	return mux
+	mux.Handle("/bus/", http.StripPrefix("/bus", busRoutes(busHandler, etaHandler, userService.IsAdmin)))
+	mux.Handle("/metro/", http.StripPrefix("/metro", metroRoutes(metroHandler, userService.IsAdmin)))
+	mux.Handle("/occurrence/", http.StripPrefix("/occurrence", occurrenceRoutes(occurrenceHandler)))
+	mux.Handle("/feedback/", http.StripPrefix("/feedback", feedbackRoutes(feedbackHandler)))
+	mux.Handle("/location/", http.StripPrefix("/location", locationRoutes(locationHandler)))
+	mux.HandleFunc("/plan", plannerHandler.Plan)
+	mux.HandleFunc("/.well-known/jwks.json", handlers.JWKS)
+	mux.Handle("/commute/", http.StripPrefix("/commute", commuteRoutes(commuteHandler)))
+	mux.Handle("/realtime/", http.StripPrefix("/realtime", realtimeRoutes(realtimeHandler)))
+	mux.Handle("/gtfs/", http.StripPrefix("/gtfs", gtfsRoutes(gtfsHandler, userService.IsAdmin)))
+	return mux, passwordResetService.Wait
 }
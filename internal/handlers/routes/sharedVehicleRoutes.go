@@ -14,6 +14,7 @@ func sharedVehicleRoutes(handler *handlers.SharedVehicleHandler) *http.ServeMux
 	sharedVehicleMux.HandleFunc("/get-shared-vehicle/{vehicleID}", handler.GetSharedVehicle)
 	sharedVehicleMux.HandleFunc("/list-shared-vehicles", handler.ListAllSharedVehicles)
 	sharedVehicleMux.HandleFunc("/update-shared-vehicle-location", middleware.Authenticate(handler.UpdateSharedVehicleLocation))
+	sharedVehicleMux.HandleFunc("/nearby", handler.ListNearbySharedVehicles)
 
 	return sharedVehicleMux
 }
@@ -2,14 +2,21 @@ package routes
 
 import (
 	"github.com/amarantec/move-easy/internal/handlers"
+	"github.com/amarantec/move-easy/internal/middleware"
 	"net/http"
 )
 
-func userRoutes(handler *handlers.UserHandler) *http.ServeMux {
+func userRoutes(handler *handlers.UserHandler, resetHandler *handlers.PasswordResetHandler) *http.ServeMux {
 	userMux := http.NewServeMux()
 
 	userMux.HandleFunc("/register", handler.Register)
 	userMux.HandleFunc("/login", handler.Login)
+	userMux.HandleFunc("/refresh", handler.Refresh)
+	userMux.HandleFunc("/logout", middleware.Authenticate(handler.Logout))
+	userMux.HandleFunc("/logout-all", middleware.Authenticate(handler.LogoutAll))
+	userMux.HandleFunc("/change-password", middleware.Authenticate(handler.ChangePassword))
+	userMux.HandleFunc("/forgot-password", resetHandler.ForgotPassword)
+	userMux.HandleFunc("/reset-password", resetHandler.ResetPassword)
 
 	return userMux
 }
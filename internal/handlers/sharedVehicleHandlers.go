@@ -155,3 +155,66 @@ func (h *SharedVehicleHandler) UpdateSharedVehicleLocation(w http.ResponseWriter
 		"response": response,
 	})
 }
+
+func (h *SharedVehicleHandler) ListNearbySharedVehicles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	latitude, err := queryFloat(r, "lat", true)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	longitude, err := queryFloat(r, "lon", true)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	radius, err := queryFloat(r, "radius", false)
+	if err != nil {
+		http.Error(w,
+			"invalid parameter, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	var vehicleType *internal.VehicleType
+	if value := r.URL.Query().Get("type"); value != internal.EMPTY {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			http.Error(w,
+				"invalid parameter, error: "+err.Error(),
+				http.StatusBadRequest)
+			return
+		}
+		t := internal.VehicleType(parsed)
+		vehicleType = &t
+	}
+
+	response, err := h.service.ListNearbySharedVehicles(ctxTimeout, latitude, longitude, radius, vehicleType)
+	if err != nil {
+		http.Error(w,
+			"could not list the nearby vehicles, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
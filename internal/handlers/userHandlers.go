@@ -3,10 +3,12 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/middleware"
 	"github.com/amarantec/move-easy/internal/user"
 )
 
@@ -80,16 +82,151 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if response == internal.EMPTY {
+	if response.AccessToken == internal.EMPTY {
 		http.Error(w,
 			"unauthorized",
 			http.StatusUnauthorized)
 		return
 	}
 
+	writeAuthTokens(w, response)
+}
+
+func (h *UserHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var request struct {
+		RefreshToken string
+	}
+
+	if err :=
+		json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w,
+			"could not decode this request, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.Refresh(ctxTimeout, request.RefreshToken)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, user.ErrRefreshTokenInvalid) || errors.Is(err, user.ErrRefreshTokenReused) {
+			status = http.StatusUnauthorized
+		}
+		http.Error(w,
+			"could not refresh this session, error: "+err.Error(),
+			status)
+		return
+	}
+
+	writeAuthTokens(w, response)
+}
+
+func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+	sessionID := r.Context().Value(middleware.SessionIDKey).(int64)
+
+	if _, err := h.service.Logout(ctxTimeout, userID, sessionID); err != nil {
+		http.Error(w,
+			"could not logout, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+
+	response, err := h.service.LogoutAll(ctxTimeout, userID)
+	if err != nil {
+		http.Error(w,
+			"could not logout from all sessions, error: "+err.Error(),
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w,
+			"invalid http method",
+			http.StatusMethodNotAllowed)
+		return
+	}
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	userID := r.Context().Value(middleware.UserIDKey).(int64)
+	sessionID := r.Context().Value(middleware.SessionIDKey).(int64)
+
+	var change internal.ChangePassword
+	if err :=
+		json.NewDecoder(r.Body).Decode(&change); err != nil {
+		http.Error(w,
+			"could not decode this request, error: "+err.Error(),
+			http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.ChangePassword(ctxTimeout, userID, sessionID, change)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, user.ErrCurrentPasswordWrong):
+			status = http.StatusForbidden
+		case errors.Is(err, user.ErrPasswordTooShort) || errors.Is(err, user.ErrPasswordTooLong) ||
+			errors.Is(err, user.ErrPasswordWeak) || errors.Is(err, user.ErrPasswordUnchanged):
+			status = http.StatusBadRequest
+		}
+		http.Error(w,
+			"could not change the password, error: "+err.Error(),
+			status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": response,
+	})
+}
+
+func writeAuthTokens(w http.ResponseWriter, tokens internal.AuthTokens) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"token": response,
+		"token":        tokens.AccessToken,
+		"refreshToken": tokens.RefreshToken,
+		"expiresAt":    tokens.ExpiresAt,
 	})
 }
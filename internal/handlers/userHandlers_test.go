@@ -7,24 +7,52 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/user"
 )
 
 // Mock do IUserService
 type mockUserService struct {
 	RegisterFunc            func(ctx context.Context, user internal.UserRegister) (int64, error)
-	ValidateCredentialsFunc func(ctx context.Context, user internal.UserLogin) (string, error)
+	ValidateCredentialsFunc func(ctx context.Context, user internal.UserLogin) (internal.AuthTokens, error)
+	RefreshFunc             func(ctx context.Context, refreshToken string) (internal.AuthTokens, error)
+	IsAdminFunc             func(ctx context.Context, userID int64) (bool, error)
 }
 
 func (m *mockUserService) Register(ctx context.Context, user internal.UserRegister) (int64, error) {
 	return m.RegisterFunc(ctx, user)
 }
 
-func (m *mockUserService) ValidateCredentials(ctx context.Context, user internal.UserLogin) (string, error) {
+func (m *mockUserService) ValidateCredentials(ctx context.Context, user internal.UserLogin) (internal.AuthTokens, error) {
 	return m.ValidateCredentialsFunc(ctx, user)
 }
 
+func (m *mockUserService) Refresh(ctx context.Context, refreshToken string) (internal.AuthTokens, error) {
+	return m.RefreshFunc(ctx, refreshToken)
+}
+
+func (m *mockUserService) Logout(ctx context.Context, userID, sessionID int64) (bool, error) {
+	return true, nil
+}
+
+func (m *mockUserService) LogoutAll(ctx context.Context, userID int64) (int64, error) {
+	return 1, nil
+}
+
+func (m *mockUserService) ChangePassword(ctx context.Context, userID, sessionID int64, change internal.ChangePassword) (bool, error) {
+	return true, nil
+}
+
+func (m *mockUserService) IsSessionActive(ctx context.Context, userID, sessionID int64) (bool, error) {
+	return true, nil
+}
+
+func (m *mockUserService) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+	return m.IsAdminFunc(ctx, userID)
+}
+
 // Teste do handler Register
 func TestUserHandler_Register(t *testing.T) {
 	mockService := &mockUserService{
@@ -96,24 +124,24 @@ func TestUserHandler_Register(t *testing.T) {
 // Teste do handler Login
 func TestUserHandler_Login(t *testing.T) {
 	mockService := &mockUserService{
-		ValidateCredentialsFunc: func(ctx context.Context, user internal.UserLogin) (string, error) {
+		ValidateCredentialsFunc: func(ctx context.Context, user internal.UserLogin) (internal.AuthTokens, error) {
 			if user.Email == "invalid@example.com" {
-				return internal.EMPTY, ErrInvalidCredentials
+				return internal.AuthTokens{}, ErrInvalidCredentials
 			}
 
             if user.Password == "wrongpass" {
-                return internal.EMPTY, ErrInvalidCredentials
+                return internal.AuthTokens{}, ErrInvalidCredentials
             }
 
             if user.Email == internal.EMPTY {
-                return internal.EMPTY, ErrMissingEmail
+                return internal.AuthTokens{}, ErrMissingEmail
             }
 
             if user.Password == internal.EMPTY {
-                return internal.EMPTY, ErrMissingPassword
+                return internal.AuthTokens{}, ErrMissingPassword
             }
 
-			return "mocked_token_123", nil
+			return mockedTokens, nil
 		},
 	}
 
@@ -129,7 +157,7 @@ func TestUserHandler_Login(t *testing.T) {
 			name:       "Successful login",
 			inputBody:  `{"email": "john@example.com", "password": "securepass"}`,
 			wantStatus: http.StatusOK,
-			wantResp:   `{"token":"mocked_token_123"}`,
+			wantResp:   `{"expiresAt":"2026-01-01T00:15:00Z","refreshToken":"mocked_refresh_123","token":"mocked_token_123"}`,
 		},
 		{
 			name:       "Invalid credentials",
@@ -183,6 +211,77 @@ func TestUserHandler_Login(t *testing.T) {
 	}
 }
 
+// Teste do handler Refresh
+func TestUserHandler_Refresh(t *testing.T) {
+	mockService := &mockUserService{
+		RefreshFunc: func(ctx context.Context, refreshToken string) (internal.AuthTokens, error) {
+			switch refreshToken {
+			case "mocked_refresh_123":
+				return mockedTokens, nil
+			case "used_refresh":
+				return internal.AuthTokens{}, user.ErrRefreshTokenReused
+			}
+			return internal.AuthTokens{}, user.ErrRefreshTokenInvalid
+		},
+	}
+
+	handler := NewUserHandler(mockService)
+
+	tests := []struct {
+		name       string
+		inputBody  string
+		wantStatus int
+		wantResp   string
+	}{
+		{
+			name:       "Successful refresh",
+			inputBody:  `{"refreshToken": "mocked_refresh_123"}`,
+			wantStatus: http.StatusOK,
+			wantResp:   `{"expiresAt":"2026-01-01T00:15:00Z","refreshToken":"mocked_refresh_123","token":"mocked_token_123"}`,
+		},
+		{
+			name:       "Reused refresh token",
+			inputBody:  `{"refreshToken": "used_refresh"}`,
+			wantStatus: http.StatusUnauthorized,
+			wantResp:   `could not refresh this session, error: ` + user.ErrRefreshTokenReused.Error(),
+		},
+		{
+			name:       "Unknown refresh token",
+			inputBody:  `{"refreshToken": "unknown"}`,
+			wantStatus: http.StatusUnauthorized,
+			wantResp:   `could not refresh this session, error: ` + user.ErrRefreshTokenInvalid.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/refresh", bytes.NewBufferString(tt.inputBody))
+			rec := httptest.NewRecorder()
+
+			handler.Refresh(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.wantStatus {
+				t.Errorf("[%s] Esperado status %d, recebeu %d", tt.name, tt.wantStatus, res.StatusCode)
+			}
+
+			var respBody bytes.Buffer
+			respBody.ReadFrom(res.Body)
+			if respBody.String() != tt.wantResp+"\n" {
+				t.Errorf("[%s] Resposta esperada: %s, recebeu: %s", tt.name, tt.wantResp, respBody.String())
+			}
+		})
+	}
+}
+
+var mockedTokens = internal.AuthTokens{
+	AccessToken:  "mocked_token_123",
+	RefreshToken: "mocked_refresh_123",
+	ExpiresAt:    time.Date(2026, 1, 1, 0, 15, 0, 0, time.UTC),
+}
+
 var ErrMissingEmail = errors.New("email is required")
 var ErrMissingPassword = errors.New("password is required")
 var ErrInvalidCredentials = errors.New("invalid credentials")
@@ -0,0 +1,39 @@
+package internal
+
+import "time"
+
+type JourneyMode string
+
+const (
+	WALK	JourneyMode = "walk"
+	BUS		JourneyMode = "bus"
+	METRO	JourneyMode = "metro"
+)
+
+type JourneyPlace struct {
+	Name			string
+	BusStopID		*int64
+	MetroStationID	*int64
+	Latitude		float64
+	Longitude		float64
+}
+
+type JourneyLeg struct {
+	Mode		JourneyMode
+	From		JourneyPlace
+	To			JourneyPlace
+	Departure	time.Time
+	Arrival		time.Time
+	Distance	float64
+	LineID		int64
+	LineName	string
+	Stops		int
+}
+
+type Journey struct {
+	Departure		time.Time
+	Arrival			time.Time
+	Transfers		int
+	WalkDistance	float64
+	Legs			[]JourneyLeg
+}
@@ -0,0 +1,26 @@
+package location
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+// StartPruneJob deletes, every interval, the location pings older than
+// maxAge.
+func StartPruneJob(ctx context.Context, service ILocationService, maxAge, interval time.Duration) {
+	utils.RunPeriodically(ctx, interval, func(ctx context.Context) error {
+		deleted, err := service.PruneUserLocations(ctx, maxAge)
+		if err != nil {
+			return fmt.Errorf("could not prune user locations, error: %w", err)
+		}
+
+		if deleted > 0 {
+			log.Printf("%d user locations pruned\n", deleted)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,98 @@
+package location
+
+import (
+	"context"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ILocationRepository interface {
+	InsertUserLocation(ctx context.Context, location internal.UserLocation) (int64, error)
+	ListLatestLineLocations(ctx context.Context, lineID int64, since time.Time) ([]internal.UserLocation, error)
+	DeleteUserLocations(ctx context.Context, reportedBefore time.Time) (int64, error)
+}
+
+type locationRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewLocationRepository(connection *pgxpool.Pool) ILocationRepository {
+	return &locationRepository{Conn: connection}
+}
+
+func (r *locationRepository) InsertUserLocation(ctx context.Context, location internal.UserLocation) (int64, error) {
+	if err :=
+		r.Conn.QueryRow(
+			ctx,
+			`INSERT INTO user_location (user_id, line_id, latitude, longitude, time_stamp)
+				VALUES ($1, $2, $3, $4, $5) RETURNING id;`, location.UserID, location.LineID,
+			location.Latitude, location.Longitude, location.TimeStamp).Scan(&location.ID); err != nil {
+		return internal.ZERO, err
+	}
+
+	return location.ID, nil
+}
+
+// ListLatestLineLocations returns only the most recent ping of each rider, so
+// a rider streaming every few seconds counts once in the aggregation.
+func (r *locationRepository) ListLatestLineLocations(ctx context.Context, lineID int64, since time.Time) ([]internal.UserLocation, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	locationChannel := make(chan []internal.UserLocation)
+	errorChannel := make(chan error)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT DISTINCT ON (user_id) id, user_id, line_id, latitude, longitude, time_stamp
+					FROM user_location WHERE line_id = $1 AND time_stamp >= $2
+					ORDER BY user_id, time_stamp DESC;`, lineID, since)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		locations := []internal.UserLocation{}
+		for rows.Next() {
+			l := internal.UserLocation{}
+			if err := rows.Scan(
+				&l.ID,
+				&l.UserID,
+				&l.LineID,
+				&l.Latitude,
+				&l.Longitude,
+				&l.TimeStamp); err != nil {
+				errorChannel <- err
+				return
+			}
+			locations = append(locations, l)
+		}
+		locationChannel <- locations
+	}()
+
+	select {
+	case locations := <-locationChannel:
+		return locations, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *locationRepository) DeleteUserLocations(ctx context.Context, reportedBefore time.Time) (int64, error) {
+	result, err :=
+		r.Conn.Exec(
+			ctx,
+			`DELETE FROM user_location WHERE time_stamp < $1;`, reportedBefore)
+	if err != nil {
+		return internal.ZERO, err
+	}
+
+	return result.RowsAffected(), nil
+}
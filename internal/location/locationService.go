@@ -0,0 +1,175 @@
+package location
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/realtime"
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+const (
+	// PING_WINDOW is how long a ping is considered when estimating positions.
+	PING_WINDOW = 2 * time.Minute
+	// MAX_PING_AGE bounds how late a ping may be delivered, so a phone that
+	// was offline cannot move a bus back to where it was minutes ago.
+	MAX_PING_AGE = 5 * time.Minute
+	// CLUSTER_RADIUS is the distance in meters under which riders are
+	// considered to be on the same bus.
+	CLUSTER_RADIUS = 150.0
+)
+
+type ILocationService interface {
+	ReportLocation(ctx context.Context, location internal.UserLocation) (int64, error)
+	EstimateBusPositions(ctx context.Context, lineID int64) ([]internal.BusPosition, error)
+	PruneUserLocations(ctx context.Context, maxAge time.Duration) (int64, error)
+}
+
+type locationService struct {
+	repository ILocationRepository
+	publisher  realtime.IPublisher
+}
+
+// NewLocationService sends the estimated bus positions of a line to publisher
+// after every ping on it, when publisher is not nil.
+func NewLocationService(repo ILocationRepository, publisher realtime.IPublisher) ILocationService {
+	return &locationService{repository: repo, publisher: publisher}
+}
+
+func (s *locationService) ReportLocation(ctx context.Context, location internal.UserLocation) (int64, error) {
+	now := time.Now()
+	if location.TimeStamp.IsZero() {
+		location.TimeStamp = now
+	}
+
+	if location.UserID <= internal.ZERO {
+		return internal.ZERO, ErrLocationUserIDInvalid
+	}
+
+	if location.LineID <= internal.ZERO {
+		return internal.ZERO, ErrLocationLineIDInvalid
+	}
+
+	if !utils.ValidCoordinates(location.Latitude, location.Longitude) {
+		return internal.ZERO, ErrLocationInvalid
+	}
+
+	if location.TimeStamp.After(now.Add(time.Minute)) || location.TimeStamp.Before(now.Add(-MAX_PING_AGE)) {
+		return internal.ZERO, ErrLocationTimeStampInvalid
+	}
+
+	id, err := s.repository.InsertUserLocation(ctx, location)
+	if err != nil {
+		return internal.ZERO, err
+	}
+
+	if s.publisher != nil {
+		s.publishBusPositions(ctx, location.LineID)
+	}
+	return id, nil
+}
+
+func (s *locationService) EstimateBusPositions(ctx context.Context, lineID int64) ([]internal.BusPosition, error) {
+	if lineID <= internal.ZERO {
+		return []internal.BusPosition{}, ErrLocationLineIDInvalid
+	}
+
+	locations, err := s.repository.ListLatestLineLocations(ctx, lineID, time.Now().Add(-PING_WINDOW))
+	if err != nil {
+		return []internal.BusPosition{}, err
+	}
+
+	return ClusterLocations(lineID, locations), nil
+}
+
+func (s *locationService) PruneUserLocations(ctx context.Context, maxAge time.Duration) (int64, error) {
+	if maxAge < PING_WINDOW {
+		return internal.ZERO, ErrLocationMaxAgeInvalid
+	}
+	return s.repository.DeleteUserLocations(ctx, time.Now().Add(-maxAge))
+}
+
+// publishBusPositions sends every bus of the line in one event, so clients
+// replace the positions of the line instead of accumulating them. A failure
+// is only logged since the ping itself was saved.
+func (s *locationService) publishBusPositions(ctx context.Context, lineID int64) {
+	positions, err := s.EstimateBusPositions(ctx, lineID)
+	if err != nil {
+		log.Printf("could not estimate the positions of bus line %d: %v\n", lineID, err)
+		return
+	}
+
+	if len(positions) == internal.ZERO {
+		return
+	}
+
+	bounds := internal.PointBox(positions[0].Latitude, positions[0].Longitude)
+	for _, position := range positions[1:] {
+		bounds = bounds.Extend(position.Latitude, position.Longitude)
+	}
+
+	s.publisher.Publish(internal.RealtimeEvent{
+		Type:   internal.BUS_POSITIONS,
+		Bounds: bounds,
+		Data:   internal.LineBusPositions{LineID: lineID, Positions: positions},
+	})
+}
+
+// ClusterLocations groups the riders of a line into estimated buses. Pings
+// are visited from the most recent and each one joins the first cluster whose
+// centroid is within CLUSTER_RADIUS, moving that centroid; otherwise it starts
+// a new cluster. Buses with more riders come first since they are the most
+// reliable estimates.
+func ClusterLocations(lineID int64, locations []internal.UserLocation) []internal.BusPosition {
+	sorted := make([]internal.UserLocation, len(locations))
+	copy(sorted, locations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].TimeStamp.After(sorted[j].TimeStamp)
+	})
+
+	positions := []internal.BusPosition{}
+	for _, location := range sorted {
+		joined := false
+		for i := range positions {
+			p := &positions[i]
+			if utils.HaversineDistance(p.Latitude, p.Longitude, location.Latitude, location.Longitude) > CLUSTER_RADIUS {
+				continue
+			}
+
+			riders := float64(p.Riders)
+			p.Latitude = (p.Latitude*riders + location.Latitude) / (riders + 1)
+			p.Longitude = (p.Longitude*riders + location.Longitude) / (riders + 1)
+			p.Riders++
+			joined = true
+			break
+		}
+
+		if !joined {
+			positions = append(positions, internal.BusPosition{
+				LineID:    lineID,
+				Latitude:  location.Latitude,
+				Longitude: location.Longitude,
+				Riders:    1,
+				LastSeen:  location.TimeStamp,
+			})
+		}
+	}
+
+	sort.SliceStable(positions, func(i, j int) bool {
+		return positions[i].Riders > positions[j].Riders
+	})
+
+	return positions
+}
+
+var (
+	ErrLocationUserIDInvalid    = errors.New("location user id is empty or negative")
+	ErrLocationLineIDInvalid    = errors.New("location line id is empty or negative")
+	ErrLocationInvalid          = errors.New("location latitude or longitude out of range")
+	ErrLocationTimeStampInvalid = errors.New("location timestamp must be within the last 5 minutes")
+	ErrLocationMaxAgeInvalid    = errors.New("location max age must be at least the ping window")
+)
@@ -0,0 +1,184 @@
+package location
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+type mockLocationRepository struct {
+	InsertUserLocationFunc      func(ctx context.Context, location internal.UserLocation) (int64, error)
+	ListLatestLineLocationsFunc func(ctx context.Context, lineID int64, since time.Time) ([]internal.UserLocation, error)
+	DeleteUserLocationsFunc     func(ctx context.Context, reportedBefore time.Time) (int64, error)
+}
+
+func (m *mockLocationRepository) InsertUserLocation(ctx context.Context, location internal.UserLocation) (int64, error) {
+	if m.InsertUserLocationFunc != nil {
+		return m.InsertUserLocationFunc(ctx, location)
+	}
+	return internal.ZERO, ErrInsertUserLocationFuncNotImplemented
+}
+
+func (m *mockLocationRepository) ListLatestLineLocations(ctx context.Context, lineID int64, since time.Time) ([]internal.UserLocation, error) {
+	if m.ListLatestLineLocationsFunc != nil {
+		return m.ListLatestLineLocationsFunc(ctx, lineID, since)
+	}
+	return nil, ErrListLatestLineLocationsFuncNotImplemented
+}
+
+func (m *mockLocationRepository) DeleteUserLocations(ctx context.Context, reportedBefore time.Time) (int64, error) {
+	if m.DeleteUserLocationsFunc != nil {
+		return m.DeleteUserLocationsFunc(ctx, reportedBefore)
+	}
+	return internal.ZERO, ErrDeleteUserLocationsFuncNotImplemented
+}
+
+func TestReportLocation(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     internal.UserLocation
+		wantID    int64
+		wantError error
+	}{
+		{
+			name:   "Localização salva com sucesso",
+			input:  internal.UserLocation{UserID: 1, LineID: 2, Latitude: -29.88, Longitude: -50.27},
+			wantID: 1,
+		},
+		{
+			name:      "Linha inválida",
+			input:     internal.UserLocation{UserID: 1, Latitude: -29.88, Longitude: -50.27},
+			wantError: ErrLocationLineIDInvalid,
+		},
+		{
+			name:      "Coordenadas inválidas",
+			input:     internal.UserLocation{UserID: 1, LineID: 2, Latitude: -95, Longitude: -50.27},
+			wantError: ErrLocationInvalid,
+		},
+		{
+			name:      "Localização antiga",
+			input:     internal.UserLocation{UserID: 1, LineID: 2, Latitude: -29.88, Longitude: -50.27, TimeStamp: time.Now().Add(-time.Hour)},
+			wantError: ErrLocationTimeStampInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockLocationRepository{
+				InsertUserLocationFunc: func(ctx context.Context, location internal.UserLocation) (int64, error) {
+					if location.TimeStamp.IsZero() {
+						t.Errorf("[%s] Horário deveria ser preenchido", tt.name)
+					}
+					return 1, nil
+				},
+			}
+			service := NewLocationService(mockRepo, nil)
+
+			id, err := service.ReportLocation(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+
+			if id != tt.wantID {
+				t.Errorf("[%s] ID esperado: %d, recebido: %d", tt.name, tt.wantID, id)
+			}
+		})
+	}
+}
+
+type recordingPublisher struct {
+	events []internal.RealtimeEvent
+}
+
+func (p *recordingPublisher) Publish(event internal.RealtimeEvent) {
+	p.events = append(p.events, event)
+}
+
+func TestReportLocationPublishesBusPositions(t *testing.T) {
+	mockRepo := &mockLocationRepository{
+		InsertUserLocationFunc: func(ctx context.Context, location internal.UserLocation) (int64, error) {
+			return 1, nil
+		},
+		ListLatestLineLocationsFunc: func(ctx context.Context, lineID int64, since time.Time) ([]internal.UserLocation, error) {
+			return []internal.UserLocation{
+				{UserID: 1, Latitude: -29.8800, Longitude: -50.2700, TimeStamp: time.Now()},
+				{UserID: 2, Latitude: -29.8900, Longitude: -50.2800, TimeStamp: time.Now()},
+			}, nil
+		},
+	}
+	publisher := &recordingPublisher{}
+	service := NewLocationService(mockRepo, publisher)
+
+	if _, err := service.ReportLocation(context.Background(), internal.UserLocation{UserID: 1, LineID: 2, Latitude: -29.88, Longitude: -50.27}); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if len(publisher.events) != 1 {
+		t.Fatalf("Eventos esperados: 1, recebidos: %d", len(publisher.events))
+	}
+
+	event := publisher.events[0]
+	positions, ok := event.Data.(internal.LineBusPositions)
+	if event.Type != internal.BUS_POSITIONS || !ok || positions.LineID != 2 || len(positions.Positions) != 2 {
+		t.Errorf("Evento com os dois ônibus da linha 2 esperado: %+v", event)
+	}
+
+	want := internal.BoundingBox{MinLatitude: -29.89, MinLongitude: -50.28, MaxLatitude: -29.88, MaxLongitude: -50.27}
+	if event.Bounds != want {
+		t.Errorf("Área esperada: %+v, recebida: %+v", want, event.Bounds)
+	}
+}
+
+func TestEstimateBusPositions(t *testing.T) {
+	now := time.Now()
+
+	mockRepo := &mockLocationRepository{
+		ListLatestLineLocationsFunc: func(ctx context.Context, lineID int64, since time.Time) ([]internal.UserLocation, error) {
+			if age := now.Sub(since); age < PING_WINDOW-time.Second || age > PING_WINDOW+time.Minute {
+				t.Errorf("Janela esperada: %v, recebida: %v", PING_WINDOW, age)
+			}
+			return []internal.UserLocation{
+				// Ônibus com dois passageiros, a cerca de 30 metros um do outro.
+				{UserID: 1, Latitude: -29.8800, Longitude: -50.2700, TimeStamp: now.Add(-10 * time.Second)},
+				{UserID: 2, Latitude: -29.8802, Longitude: -50.2702, TimeStamp: now.Add(-20 * time.Second)},
+				// Outro ônibus da mesma linha, a mais de um quilômetro.
+				{UserID: 3, Latitude: -29.8900, Longitude: -50.2800, TimeStamp: now.Add(-5 * time.Second)},
+			}, nil
+		},
+	}
+	service := NewLocationService(mockRepo, nil)
+
+	positions, err := service.EstimateBusPositions(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if len(positions) != 2 {
+		t.Fatalf("Ônibus esperados: 2, recebidos: %d", len(positions))
+	}
+
+	if positions[0].Riders != 2 || positions[0].LineID != 7 {
+		t.Errorf("Primeiro ônibus deveria ter 2 passageiros na linha 7: %+v", positions[0])
+	}
+
+	if positions[0].Latitude > -29.8800 || positions[0].Latitude < -29.8802 {
+		t.Errorf("Posição deveria ser a média dos passageiros: %+v", positions[0])
+	}
+
+	if !positions[0].LastSeen.Equal(now.Add(-10 * time.Second)) {
+		t.Errorf("Último registro esperado: %v, recebido: %v", now.Add(-10*time.Second), positions[0].LastSeen)
+	}
+
+	if _, err := service.EstimateBusPositions(context.Background(), internal.ZERO); !errors.Is(err, ErrLocationLineIDInvalid) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrLocationLineIDInvalid, err)
+	}
+}
+
+var (
+	ErrInsertUserLocationFuncNotImplemented      = errors.New("InsertUserLocationFunc not implemented")
+	ErrListLatestLineLocationsFuncNotImplemented = errors.New("ListLatestLineLocationsFunc not implemented")
+	ErrDeleteUserLocationsFuncNotImplemented     = errors.New("DeleteUserLocationsFunc not implemented")
+)
@@ -0,0 +1,33 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// FileMailer writes each mail to its own .eml file in a directory instead of
+// sending it, for local runs.
+type FileMailer struct {
+	dir   string
+	count atomic.Int64
+}
+
+func NewFileMailer(dir string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileMailer{dir: dir}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, mail Mail) error {
+	if err := validateMail(mail); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%d.eml", time.Now().Format("20060102T150405.000000000"), m.count.Add(1))
+	return os.WriteFile(filepath.Join(m.dir, name), buildMessage("move-easy@localhost", mail), 0600)
+}
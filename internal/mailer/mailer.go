@@ -0,0 +1,69 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+const (
+	SMTP   = "smtp"
+	FILE   = "file"
+	MEMORY = "memory"
+)
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type IMailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+// FromEnv builds the mailer named in MAILER: smtp, configured by the SMTP_*
+// variables, file, writing to MAILER_DIR, or memory. It returns nil when
+// MAILER is empty.
+func FromEnv() (IMailer, error) {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("MAILER"))) {
+	case internal.EMPTY:
+		return nil, nil
+	case SMTP:
+		config, err := smtpConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		return NewSMTPMailer(config), nil
+	case FILE:
+		dir := os.Getenv("MAILER_DIR")
+		if dir == internal.EMPTY {
+			return nil, fmt.Errorf("%w: MAILER_DIR", ErrMailerConfigMissing)
+		}
+		return NewFileMailer(dir)
+	case MEMORY:
+		return NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrMailerInvalid, os.Getenv("MAILER"))
+	}
+}
+
+func validateMail(mail Mail) error {
+	if mail.To == internal.EMPTY || strings.ContainsAny(mail.To+mail.Subject, "\r\n") {
+		return ErrMailInvalid
+	}
+	return nil
+}
+
+var (
+	ErrMailInvalid         = errors.New("mail must have a recipient and no line breaks in its headers")
+	ErrMailerInvalid       = errors.New("MAILER must be smtp, file or memory")
+	ErrMailerConfigMissing = errors.New("mailer configuration missing")
+	ErrMailerConfigInvalid = errors.New("mailer configuration invalid")
+	ErrSMTPAuthUnsupported = errors.New("smtp server does not support authentication")
+	ErrSMTPTLSUnsupported  = errors.New("smtp server does not offer STARTTLS, set SMTP_INSECURE to send without it")
+)
@@ -0,0 +1,170 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSMTPMailer(t *testing.T) {
+	var addr string
+	var msg []byte
+	m := &smtpMailer{
+		config: SMTPConfig{Host: "smtp.example.com", Port: "2525", From: "no-reply@example.com"},
+		send: func(ctx context.Context, a string, auth smtp.Auth, from string, to []string, message []byte, requireTLS bool) error {
+			if !requireTLS {
+				t.Errorf("STARTTLS deveria ser exigido de um servidor remoto")
+			}
+			addr, msg = a, message
+			return nil
+		},
+	}
+
+	if err := m.Send(context.Background(), Mail{To: "ana@example.com", Subject: "Redefinição de senha", Body: "Olá\nAna"}); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if addr != "smtp.example.com:2525" {
+		t.Errorf("Servidor esperado: smtp.example.com:2525, recebido: %s", addr)
+	}
+
+	if !strings.Contains(string(msg), "To: ana@example.com\r\n") || !strings.HasSuffix(string(msg), "\r\n\r\nOlá\r\nAna") ||
+		!strings.Contains(string(msg), "Subject: =?utf-8?q?") {
+		t.Errorf("Mensagem inesperada: %q", msg)
+	}
+
+	if err := m.Send(context.Background(), Mail{To: "ana@example.com\r\nBcc: eve@example.com"}); !errors.Is(err, ErrMailInvalid) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrMailInvalid, err)
+	}
+}
+
+func TestSMTPMailerTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	defer listener.Close()
+
+	// The server accepts the connection and never greets the client.
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(5 * time.Second)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	m := NewSMTPMailer(SMTPConfig{Host: host, Port: port, From: "no-reply@example.com"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := m.Send(ctx, Mail{To: "ana@example.com", Subject: "Teste", Body: "Olá"}); err == nil {
+		t.Errorf("Erro esperado por tempo esgotado, recebido: nil")
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("O envio deveria parar com o contexto, levou: %v", elapsed)
+	}
+}
+
+func TestSMTPMailerRequiresTLS(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	defer listener.Close()
+
+	// The server answers without offering STARTTLS.
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		conn.Write([]byte("220 mail.example.com ESMTP\r\n"))
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				conn.Write([]byte("250-mail.example.com\r\n250 8BITMIME\r\n"))
+			case strings.HasPrefix(line, "QUIT"):
+				conn.Write([]byte("221 bye\r\n"))
+				return
+			default:
+				conn.Write([]byte("250 ok\r\n"))
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err = sendMail(ctx, listener.Addr().String(), nil, "no-reply@example.com", []string{"ana@example.com"}, []byte("Olá"), true)
+	if !errors.Is(err, ErrSMTPTLSUnsupported) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrSMTPTLSUnsupported, err)
+	}
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewFileMailer(dir)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	for _, to := range []string{"ana@example.com", "joao@example.com"} {
+		if err := m.Send(context.Background(), Mail{To: to, Subject: "Teste", Body: "Olá"}); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil || len(files) != 2 {
+		t.Fatalf("Esperava 2 arquivos, recebeu %d (erro: %v)", len(files), err)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("MAILER", "")
+	if m, err := FromEnv(); m != nil || err != nil {
+		t.Errorf("Nenhum mailer esperado, recebido: %v (erro: %v)", m, err)
+	}
+
+	t.Setenv("MAILER", "smtp")
+	t.Setenv("SMTP_HOST", "")
+	if _, err := FromEnv(); !errors.Is(err, ErrMailerConfigMissing) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrMailerConfigMissing, err)
+	}
+
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("SMTP_FROM", "no-reply@example.com")
+	t.Setenv("SMTP_INSECURE", "talvez")
+	if _, err := FromEnv(); !errors.Is(err, ErrMailerConfigInvalid) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrMailerConfigInvalid, err)
+	}
+
+	t.Setenv("MAILER", "memory")
+	if m, err := FromEnv(); err != nil {
+		t.Errorf("Erro inesperado: %v", err)
+	} else if _, ok := m.(*MemoryMailer); !ok {
+		t.Errorf("MemoryMailer esperado, recebido: %T", m)
+	}
+
+	t.Setenv("MAILER", "pigeon")
+	if _, err := FromEnv(); !errors.Is(err, ErrMailerInvalid) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrMailerInvalid, err)
+	}
+}
@@ -0,0 +1,172 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+const DEFAULT_SMTP_PORT = "587"
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	// Insecure lets the mailer talk to a server without STARTTLS. Loopback
+	// servers never need it.
+	Insecure bool
+}
+
+type smtpMailer struct {
+	config SMTPConfig
+	send   func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte, requireTLS bool) error
+}
+
+func NewSMTPMailer(config SMTPConfig) IMailer {
+	if config.Port == internal.EMPTY {
+		config.Port = DEFAULT_SMTP_PORT
+	}
+	return &smtpMailer{config: config, send: sendMail}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, mail Mail) error {
+	if err := validateMail(mail); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.config.Username != internal.EMPTY {
+		auth = smtp.PlainAuth(internal.EMPTY, m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	return m.send(ctx, net.JoinHostPort(m.config.Host, m.config.Port), auth, m.config.From, []string{mail.To},
+		buildMessage(m.config.From, mail), !m.config.Insecure && !isLoopback(m.config.Host))
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// sendMail does what smtp.SendMail does, but the dial and the whole
+// conversation with the server stop when ctx is done. With requireTLS it
+// fails instead of sending in cleartext to a server that does not offer
+// STARTTLS, which an attacker on the path can strip from the reply.
+func sendMail(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte, requireTLS bool) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	} else if requireTLS {
+		return ErrSMTPTLSUnsupported
+	}
+
+	if a != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return ErrSMTPAuthUnsupported
+		}
+		if err := client.Auth(a); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func buildMessage(from string, mail Mail) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + mail.To + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", mail.Subject) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+func smtpConfigFromEnv() (SMTPConfig, error) {
+	config := SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+
+	if insecure := os.Getenv("SMTP_INSECURE"); insecure != internal.EMPTY {
+		var err error
+		if config.Insecure, err = strconv.ParseBool(insecure); err != nil {
+			return SMTPConfig{}, fmt.Errorf("%w: SMTP_INSECURE", ErrMailerConfigInvalid)
+		}
+	}
+
+	if config.Host == internal.EMPTY {
+		return SMTPConfig{}, fmt.Errorf("%w: SMTP_HOST", ErrMailerConfigMissing)
+	}
+
+	if config.From == internal.EMPTY {
+		return SMTPConfig{}, fmt.Errorf("%w: SMTP_FROM", ErrMailerConfigMissing)
+	}
+
+	return config, nil
+}
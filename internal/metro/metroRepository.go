@@ -0,0 +1,171 @@
+package metro
+
+import (
+	"context"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IMetroRepository interface {
+	InsertMetroStation(ctx context.Context, station internal.MetroStation) (int64, error)
+	GetMetroStation(ctx context.Context, stationID int64) (internal.MetroStation, error)
+	ListMetroStations(ctx context.Context) ([]internal.MetroStation, error)
+	DeleteMetroStation(ctx context.Context, stationID int64) (bool, error)
+	ListNearestMetroStations(ctx context.Context, latitude, longitude, radius float64, limit int) ([]internal.NearbyMetroStation, error)
+}
+
+type metroRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewMetroRepository(connection *pgxpool.Pool) IMetroRepository {
+	return &metroRepository{Conn: connection}
+}
+
+func (r *metroRepository) InsertMetroStation(ctx context.Context, station internal.MetroStation) (int64, error) {
+	if err :=
+		r.Conn.QueryRow(
+			ctx,
+			`INSERT INTO metro (station_name, latitude, longitude) VALUES ($1, $2, $3)
+				RETURNING id;`, station.StationName, station.Latitude, station.Longitude).Scan(&station.ID); err != nil {
+		return internal.ZERO, err
+	}
+
+	return station.ID, nil
+}
+
+func (r *metroRepository) GetMetroStation(ctx context.Context, stationID int64) (internal.MetroStation, error) {
+	station := internal.MetroStation{ID: stationID}
+	if err :=
+		r.Conn.QueryRow(
+			ctx,
+			`SELECT station_name, latitude, longitude, created_at FROM metro
+				WHERE id = $1 AND deleted_at IS NULL;`, stationID).Scan(&station.StationName,
+			&station.Latitude, &station.Longitude, &station.CreatedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			return internal.MetroStation{}, nil
+		}
+		return internal.MetroStation{}, err
+	}
+
+	return station, nil
+}
+
+func (r *metroRepository) ListMetroStations(ctx context.Context) ([]internal.MetroStation, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	stationChannel := make(chan []internal.MetroStation)
+	errorChannel := make(chan error)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT id, station_name, latitude, longitude, created_at FROM metro
+					WHERE deleted_at IS NULL ORDER BY station_name;`)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		stations := []internal.MetroStation{}
+		for rows.Next() {
+			s := internal.MetroStation{}
+			if err := rows.Scan(
+				&s.ID,
+				&s.StationName,
+				&s.Latitude,
+				&s.Longitude,
+				&s.CreatedAt); err != nil {
+				errorChannel <- err
+				return
+			}
+			stations = append(stations, s)
+		}
+		stationChannel <- stations
+	}()
+
+	select {
+	case stations := <-stationChannel:
+		return stations, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *metroRepository) DeleteMetroStation(ctx context.Context, stationID int64) (bool, error) {
+	result, err :=
+		r.Conn.Exec(
+			ctx,
+			`UPDATE metro SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL;`, stationID, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	if result.RowsAffected() == internal.ZERO {
+		return false, nil
+	} else {
+		return true, nil
+	}
+}
+
+func (r *metroRepository) ListNearestMetroStations(ctx context.Context, latitude, longitude, radius float64, limit int) ([]internal.NearbyMetroStation, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	stationChannel := make(chan []internal.NearbyMetroStation)
+	errorChannel := make(chan error)
+
+	minLat, minLon, maxLat, maxLon := utils.BoundingBox(latitude, longitude, radius)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT id, station_name, latitude, longitude, created_at, distance FROM (
+					SELECT id, station_name, latitude, longitude, created_at, `+utils.DISTANCE_SQL+` AS distance
+					FROM metro WHERE latitude BETWEEN $3 AND $4 AND longitude BETWEEN $5 AND $6
+						AND deleted_at IS NULL) AS nearby
+				WHERE distance <= $7 ORDER BY distance LIMIT $8;`, latitude, longitude,
+				minLat, maxLat, minLon, maxLon, radius, limit)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		stations := []internal.NearbyMetroStation{}
+		for rows.Next() {
+			s := internal.NearbyMetroStation{}
+			if err := rows.Scan(
+				&s.ID,
+				&s.StationName,
+				&s.Latitude,
+				&s.Longitude,
+				&s.CreatedAt,
+				&s.Distance); err != nil {
+				errorChannel <- err
+				return
+			}
+			stations = append(stations, s)
+		}
+		stationChannel <- stations
+	}()
+
+	select {
+	case stations := <-stationChannel:
+		return stations, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,93 @@
+package metro
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+const (
+	DEFAULT_NEAREST_RADIUS = 1000.0
+	MAX_NEAREST_RADIUS     = 50000.0
+	NEAREST_LIMIT          = 20
+)
+
+type IMetroService interface {
+	InsertMetroStation(ctx context.Context, station internal.MetroStation) (int64, error)
+	GetMetroStation(ctx context.Context, stationID int64) (internal.MetroStation, error)
+	ListMetroStations(ctx context.Context) ([]internal.MetroStation, error)
+	DeleteMetroStation(ctx context.Context, stationID int64) (bool, error)
+	ListNearestMetroStations(ctx context.Context, latitude, longitude, radius float64) ([]internal.NearbyMetroStation, error)
+}
+
+type metroService struct {
+	repository IMetroRepository
+}
+
+func NewMetroService(repo IMetroRepository) IMetroService {
+	return &metroService{repository: repo}
+}
+
+func (s *metroService) InsertMetroStation(ctx context.Context, station internal.MetroStation) (int64, error) {
+	if valid, err := validateMetroStation(station); err != nil || !valid {
+		return internal.ZERO, err
+	}
+	return s.repository.InsertMetroStation(ctx, station)
+}
+
+func (s *metroService) GetMetroStation(ctx context.Context, stationID int64) (internal.MetroStation, error) {
+	if stationID <= internal.ZERO {
+		return internal.MetroStation{}, ErrMetroStationIDInvalid
+	}
+	return s.repository.GetMetroStation(ctx, stationID)
+}
+
+func (s *metroService) ListMetroStations(ctx context.Context) ([]internal.MetroStation, error) {
+	return s.repository.ListMetroStations(ctx)
+}
+
+func (s *metroService) DeleteMetroStation(ctx context.Context, stationID int64) (bool, error) {
+	if stationID <= internal.ZERO {
+		return false, ErrMetroStationIDInvalid
+	}
+	return s.repository.DeleteMetroStation(ctx, stationID)
+}
+
+func (s *metroService) ListNearestMetroStations(ctx context.Context, latitude, longitude, radius float64) ([]internal.NearbyMetroStation, error) {
+	if !utils.ValidCoordinates(latitude, longitude) {
+		return []internal.NearbyMetroStation{}, ErrMetroStationLocationInvalid
+	}
+
+	if radius <= internal.ZERO {
+		radius = DEFAULT_NEAREST_RADIUS
+	} else if radius > MAX_NEAREST_RADIUS {
+		return []internal.NearbyMetroStation{}, ErrMetroStationRadiusInvalid
+	}
+
+	return s.repository.ListNearestMetroStations(ctx, latitude, longitude, radius, NEAREST_LIMIT)
+}
+
+func validateMetroStation(m internal.MetroStation) (bool, error) {
+	if m.StationName == internal.EMPTY {
+		return false, ErrMetroStationNameEmpty
+	} else if utf8.RuneCountInString(m.StationName) < 3 || utf8.RuneCountInString(m.StationName) > 255 {
+		return false, ErrMetroStationNameInvalid
+	}
+
+	if !utils.ValidCoordinates(m.Latitude, m.Longitude) {
+		return false, ErrMetroStationLocationInvalid
+	}
+
+	return true, nil
+}
+
+var (
+	ErrMetroStationIDInvalid       = errors.New("metro station id is empty or negative")
+	ErrMetroStationNameEmpty       = errors.New("metro station name is empty")
+	ErrMetroStationNameInvalid     = errors.New("metro station name must be between 3-255 characters")
+	ErrMetroStationLocationInvalid = errors.New("metro station latitude or longitude out of range")
+	ErrMetroStationRadiusInvalid   = errors.New("metro station search radius must be at most 50000 meters")
+)
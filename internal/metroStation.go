@@ -1,9 +1,18 @@
 package internal
 
+import "time"
+
 type MetroStation struct {
 	ID		    int64
 	StationName string
 	Latitude	float64
 	Longitude	float64
+	CreatedAt	time.Time
+	UpdatedAt	*time.Time
+	DeletedAt	*time.Time
 }
 
+type NearbyMetroStation struct {
+	MetroStation
+	Distance	float64
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+    "context"
+    "net/http"
+    "time"
+)
+
+// RequireAdmin must wrap a handler that is already behind Authenticate, since
+// it reads the user id that Authenticate puts in the request context.
+func RequireAdmin (isAdmin func(ctx context.Context, userID int64) (bool, error), next http.HandlerFunc) http.HandlerFunc {
+    return func (w http.ResponseWriter, r *http.Request) {
+        userID, ok := r.Context().Value(UserIDKey).(int64)
+        if !ok {
+            http.Error(w,
+                "user is not authenticated",
+                http.StatusUnauthorized)
+            return
+        }
+
+        ctxTimeout, cancel := context.WithTimeout(r.Context(), 5 * time.Second)
+        defer cancel()
+
+        admin, err := isAdmin(ctxTimeout, userID)
+        if err != nil {
+            http.Error(w,
+                "could not check user permissions, error: " + err.Error(),
+                http.StatusInternalServerError)
+            return
+        }
+
+        if !admin {
+            http.Error(w,
+                "user is not an administrator",
+                http.StatusForbidden)
+            return
+        }
+
+        next(w, r)
+    }
+}
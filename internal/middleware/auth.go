@@ -3,11 +3,28 @@ package middleware
 import (
     "context"
     "net/http"
+    "sync"
+    "time"
     "github.com/amarantec/move-easy/internal/utils"
 )
 
 type contextKey string
 const UserIDKey contextKey = "userID"
+const SessionIDKey contextKey = "sessionID"
+
+var (
+    sessionValidatorMu  sync.RWMutex
+    sessionValidator    func(ctx context.Context, userID, sessionID int64) (bool, error)
+)
+
+// SetSessionValidator makes Authenticate reject tokens whose session is no
+// longer active, such as after a logout.
+func SetSessionValidator(validator func(ctx context.Context, userID, sessionID int64) (bool, error)) {
+    sessionValidatorMu.Lock()
+    defer sessionValidatorMu.Unlock()
+
+    sessionValidator = validator
+}
 
 func Authenticate (next http.HandlerFunc) http.HandlerFunc {
     return func (w http.ResponseWriter, r *http.Request) {
@@ -18,10 +35,10 @@ func Authenticate (next http.HandlerFunc) http.HandlerFunc {
                 http.StatusUnauthorized)
             return
         }
-    
+
         token := cookie.Value
 
-        userID, err := utils.VerifyToken(token)
+        claims, err := utils.VerifyToken(token)
         if err != nil {
             http.Error(w,
                 err.Error(),
@@ -29,7 +46,32 @@ func Authenticate (next http.HandlerFunc) http.HandlerFunc {
             return
         }
 
-        ctx := context.WithValue(r.Context(), UserIDKey, userID)
+        sessionValidatorMu.RLock()
+        validator := sessionValidator
+        sessionValidatorMu.RUnlock()
+
+        if validator != nil {
+            ctxTimeout, cancel := context.WithTimeout(r.Context(), 5 * time.Second)
+            defer cancel()
+
+            active, err := validator(ctxTimeout, claims.UserID, claims.SessionID)
+            if err != nil {
+                http.Error(w,
+                    "could not check this session, error: " + err.Error(),
+                    http.StatusInternalServerError)
+                return
+            }
+
+            if !active {
+                http.Error(w,
+                    "session has been revoked",
+                    http.StatusUnauthorized)
+                return
+            }
+        }
+
+        ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+        ctx = context.WithValue(ctx, SessionIDKey, claims.SessionID)
         next(w, r.WithContext(ctx))
     }
 }
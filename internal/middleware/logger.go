@@ -1,7 +1,9 @@
 package middleware
 
 import (
+    "bufio"
     "time"
+    "net"
     "net/http"
     "log"
 )
@@ -16,6 +18,22 @@ func (rw *responseWriterWrapper) WriteHeader(code int) {
     rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap lets http.ResponseController reach the flusher and hijacker of the
+// underlying writer, which streaming handlers need.
+func (rw *responseWriterWrapper) Unwrap() http.ResponseWriter {
+    return rw.ResponseWriter
+}
+
+// Hijack is needed by WebSocket upgraders, which check for http.Hijacker
+// directly instead of going through http.ResponseController.
+func (rw *responseWriterWrapper) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+    conn, buf, err := http.NewResponseController(rw.ResponseWriter).Hijack()
+    if err == nil {
+        rw.statusCode = http.StatusSwitchingProtocols
+    }
+    return conn, buf, err
+}
+
 func LoggerMiddleware(next http.Handler) http.Handler {
     return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
         start := time.Now()
@@ -0,0 +1,28 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/mailer"
+)
+
+type emailNotifier struct {
+	mailer mailer.IMailer
+}
+
+func NewEmailNotifier(m mailer.IMailer) INotifier {
+	return &emailNotifier{mailer: m}
+}
+
+func (n *emailNotifier) Channel() string {
+	return EMAIL
+}
+
+func (n *emailNotifier) Notify(ctx context.Context, contact internal.Contact, message Message) error {
+	if contact.Email == internal.EMPTY {
+		return ErrNotifierNoDestination
+	}
+
+	return n.mailer.Send(ctx, mailer.Mail{To: contact.Email, Subject: message.Subject, Body: message.Body})
+}
@@ -0,0 +1,45 @@
+package notifier
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+type Notification struct {
+	Contact internal.Contact
+	Message Message
+}
+
+// FakeNotifier keeps the notifications in memory and logs them instead of
+// sending, for tests and local runs.
+type FakeNotifier struct {
+	mu   sync.Mutex
+	sent []Notification
+}
+
+func NewFakeNotifier() *FakeNotifier {
+	return &FakeNotifier{}
+}
+
+func (n *FakeNotifier) Channel() string {
+	return FAKE
+}
+
+func (n *FakeNotifier) Notify(ctx context.Context, contact internal.Contact, message Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	log.Printf("notification to contact %d: %s", contact.ID, message.Body)
+	n.sent = append(n.sent, Notification{Contact: contact, Message: message})
+	return nil
+}
+
+func (n *FakeNotifier) Sent() []Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]Notification{}, n.sent...)
+}
@@ -0,0 +1,108 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+const (
+	DEFAULT_MESSAGING_API_URL = "https://api.twilio.com"
+	MESSAGING_TIMEOUT         = 10 * time.Second
+)
+
+// MessagingConfig points to a Twilio compatible messaging API, used for both
+// SMS and WhatsApp.
+type MessagingConfig struct {
+	BaseURL   string
+	AccountID string
+	Token     string
+	From      string
+}
+
+type messagingNotifier struct {
+	channel string
+	prefix  string
+	config  MessagingConfig
+	client  *http.Client
+}
+
+func NewSMSNotifier(config MessagingConfig) INotifier {
+	return newMessagingNotifier(SMS, internal.EMPTY, config)
+}
+
+// NewWhatsAppNotifier sends through the same API, which tells WhatsApp
+// numbers apart by their whatsapp: prefix.
+func NewWhatsAppNotifier(config MessagingConfig) INotifier {
+	return newMessagingNotifier(WHATSAPP, "whatsapp:", config)
+}
+
+func newMessagingNotifier(channel, prefix string, config MessagingConfig) *messagingNotifier {
+	if config.BaseURL == internal.EMPTY {
+		config.BaseURL = DEFAULT_MESSAGING_API_URL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	return &messagingNotifier{
+		channel: channel,
+		prefix:  prefix,
+		config:  config,
+		client:  &http.Client{Timeout: MESSAGING_TIMEOUT},
+	}
+}
+
+func (n *messagingNotifier) Channel() string {
+	return n.channel
+}
+
+func (n *messagingNotifier) Notify(ctx context.Context, contact internal.Contact, message Message) error {
+	phone := PhoneNumber(contact)
+	if phone == internal.EMPTY {
+		return ErrNotifierNoDestination
+	}
+
+	form := url.Values{}
+	form.Set("To", n.prefix+phone)
+	form.Set("From", n.prefix+n.config.From)
+	form.Set("Body", message.Body)
+
+	endpoint := n.config.BaseURL + "/2010-04-01/Accounts/" + url.PathEscape(n.config.AccountID) + "/Messages.json"
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	request.SetBasicAuth(n.config.AccountID, n.config.Token)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s status %d", ErrNotifierRejected, n.channel, response.StatusCode)
+	}
+	return nil
+}
+
+func messagingConfigFromEnv(fromKey string) (MessagingConfig, error) {
+	values, err := requireEnv("MESSAGING_ACCOUNT_ID", "MESSAGING_TOKEN", fromKey)
+	if err != nil {
+		return MessagingConfig{}, err
+	}
+
+	return MessagingConfig{
+		BaseURL:   os.Getenv("MESSAGING_API_URL"),
+		AccountID: values["MESSAGING_ACCOUNT_ID"],
+		Token:     values["MESSAGING_TOKEN"],
+		From:      values[fromKey],
+	}, nil
+}
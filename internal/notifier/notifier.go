@@ -0,0 +1,92 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/mailer"
+)
+
+const (
+	SMS      = "sms"
+	WHATSAPP = "whatsapp"
+	EMAIL    = "email"
+	FAKE     = "fake"
+)
+
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// INotifier delivers a message to a contact over one channel. Notify returns
+// ErrNotifierNoDestination when the contact cannot be reached on it, such as
+// a contact without e-mail on the e-mail channel.
+type INotifier interface {
+	Channel() string
+	Notify(ctx context.Context, contact internal.Contact, message Message) error
+}
+
+// PhoneNumber formats the phone of a contact in E.164, dropping the leading
+// zeros the DDI and DDD are stored with.
+func PhoneNumber(contact internal.Contact) string {
+	if contact.PhoneNumber == internal.EMPTY {
+		return internal.EMPTY
+	}
+	return "+" + strings.TrimLeft(contact.DDI, "0") + strings.TrimLeft(contact.DDD, "0") + contact.PhoneNumber
+}
+
+// FromEnv builds the notifiers named in NOTIFIER_CHANNELS, a comma separated
+// list of sms, whatsapp, email and fake. No notifier is built when it is
+// empty. The email channel sends through m, which must not be nil.
+func FromEnv(m mailer.IMailer) ([]INotifier, error) {
+	notifiers := []INotifier{}
+	for _, channel := range strings.Split(os.Getenv("NOTIFIER_CHANNELS"), ",") {
+		switch strings.ToLower(strings.TrimSpace(channel)) {
+		case internal.EMPTY:
+		case SMS:
+			config, err := messagingConfigFromEnv("SMS_FROM")
+			if err != nil {
+				return nil, err
+			}
+			notifiers = append(notifiers, NewSMSNotifier(config))
+		case WHATSAPP:
+			config, err := messagingConfigFromEnv("WHATSAPP_FROM")
+			if err != nil {
+				return nil, err
+			}
+			notifiers = append(notifiers, NewWhatsAppNotifier(config))
+		case EMAIL:
+			if m == nil {
+				return nil, fmt.Errorf("%w: MAILER", ErrNotifierConfigMissing)
+			}
+			notifiers = append(notifiers, NewEmailNotifier(m))
+		case FAKE:
+			notifiers = append(notifiers, NewFakeNotifier())
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrNotifierChannelInvalid, channel)
+		}
+	}
+	return notifiers, nil
+}
+
+func requireEnv(keys ...string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, key := range keys {
+		if values[key] = os.Getenv(key); values[key] == internal.EMPTY {
+			return nil, fmt.Errorf("%w: %s", ErrNotifierConfigMissing, key)
+		}
+	}
+	return values, nil
+}
+
+var (
+	ErrNotifierNoDestination  = errors.New("contact cannot be reached on this channel")
+	ErrNotifierRejected       = errors.New("notification rejected by the provider")
+	ErrNotifierChannelInvalid = errors.New("notifier channel must be sms, whatsapp, email or fake")
+	ErrNotifierConfigMissing  = errors.New("notifier configuration missing")
+)
@@ -0,0 +1,115 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/mailer"
+)
+
+func TestPhoneNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		contact internal.Contact
+		want    string
+	}{
+		{name: "Com zeros à esquerda", contact: internal.Contact{DDI: "055", DDD: "051", PhoneNumber: "999998888"}, want: "+5551999998888"},
+		{name: "Sem zeros", contact: internal.Contact{DDI: "55", DDD: "51", PhoneNumber: "999998888"}, want: "+5551999998888"},
+		{name: "Sem telefone", contact: internal.Contact{DDI: "55", DDD: "51"}, want: internal.EMPTY},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PhoneNumber(tt.contact); got != tt.want {
+				t.Errorf("[%s] Telefone esperado: %s, recebido: %s", tt.name, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWhatsAppNotifier(t *testing.T) {
+	var to, from, body, account string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		account, _, _ = r.BasicAuth()
+		r.ParseForm()
+		to, from, body = r.Form.Get("To"), r.Form.Get("From"), r.Form.Get("Body")
+		if !strings.HasSuffix(r.URL.Path, "/Accounts/AC1/Messages.json") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	n := NewWhatsAppNotifier(MessagingConfig{BaseURL: server.URL, AccountID: "AC1", Token: "secret", From: "+5551000000000"})
+	contact := internal.Contact{DDI: "055", DDD: "051", PhoneNumber: "999998888"}
+	if err := n.Notify(context.Background(), contact, Message{Body: "Socorro"}); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if to != "whatsapp:+5551999998888" || from != "whatsapp:+5551000000000" || body != "Socorro" || account != "AC1" {
+		t.Errorf("Requisição inesperada: to=%s from=%s body=%s account=%s", to, from, body, account)
+	}
+
+	n = NewSMSNotifier(MessagingConfig{BaseURL: server.URL, AccountID: "AC2", Token: "secret", From: "+5551000000000"})
+	if err := n.Notify(context.Background(), contact, Message{Body: "Socorro"}); !errors.Is(err, ErrNotifierRejected) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrNotifierRejected, err)
+	}
+
+	if err := n.Notify(context.Background(), internal.Contact{}, Message{Body: "Socorro"}); !errors.Is(err, ErrNotifierNoDestination) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrNotifierNoDestination, err)
+	}
+}
+
+func TestEmailNotifier(t *testing.T) {
+	m := mailer.NewMemoryMailer()
+	n := NewEmailNotifier(m)
+
+	if err := n.Notify(context.Background(), internal.Contact{Name: "Ana"}, Message{Body: "Socorro"}); !errors.Is(err, ErrNotifierNoDestination) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrNotifierNoDestination, err)
+	}
+
+	if err := n.Notify(context.Background(), internal.Contact{Email: "ana@example.com"},
+		Message{Subject: "Alerta de emergência", Body: "Socorro"}); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	sent := m.Sent()
+	if len(sent) != 1 || sent[0].To != "ana@example.com" || sent[0].Subject != "Alerta de emergência" || sent[0].Body != "Socorro" {
+		t.Errorf("E-mail inesperado: %+v", sent)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("NOTIFIER_CHANNELS", "fake, sms")
+	t.Setenv("MESSAGING_ACCOUNT_ID", "AC1")
+	t.Setenv("MESSAGING_TOKEN", "secret")
+	if _, err := FromEnv(nil); !errors.Is(err, ErrNotifierConfigMissing) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrNotifierConfigMissing, err)
+	}
+
+	t.Setenv("SMS_FROM", "+5551000000000")
+	notifiers, err := FromEnv(nil)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if len(notifiers) != 2 || notifiers[0].Channel() != FAKE || notifiers[1].Channel() != SMS {
+		t.Errorf("Canais esperados: fake e sms, recebidos: %v", notifiers)
+	}
+
+	t.Setenv("NOTIFIER_CHANNELS", "email")
+	if _, err := FromEnv(nil); !errors.Is(err, ErrNotifierConfigMissing) {
+		t.Errorf("Erro esperado sem mailer: %v, recebido: %v", ErrNotifierConfigMissing, err)
+	}
+
+	t.Setenv("NOTIFIER_CHANNELS", "pigeon")
+	if _, err := FromEnv(nil); !errors.Is(err, ErrNotifierChannelInvalid) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrNotifierChannelInvalid, err)
+	}
+}
@@ -7,6 +7,12 @@ type Occurrence struct {
 	UserID			int64
 	Type			OccurrenceType
 	Description		string
+	Latitude		float64
+	Longitude		float64
 	TimeStamp 		time.Time
 	Confirmation	int64
+	Disputes		int64
+	CreatedAt		time.Time
+	UpdatedAt		*time.Time
+	DeletedAt		*time.Time
 }
@@ -0,0 +1,26 @@
+package occurrence
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+// StartExpiryJob soft deletes, every interval, the occurrences older than
+// maxAge that nobody confirmed.
+func StartExpiryJob(ctx context.Context, service IOccurrenceService, maxAge, interval time.Duration) {
+	utils.RunPeriodically(ctx, interval, func(ctx context.Context) error {
+		expired, err := service.ExpireUnconfirmedOccurrences(ctx, maxAge)
+		if err != nil {
+			return fmt.Errorf("could not expire unconfirmed occurrences, error: %w", err)
+		}
+
+		if expired > 0 {
+			log.Printf("%d unconfirmed occurrences expired\n", expired)
+		}
+		return nil
+	})
+}
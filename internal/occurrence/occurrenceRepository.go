@@ -0,0 +1,169 @@
+package occurrence
+
+import (
+	"context"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IOccurrenceRepository interface {
+	InsertOccurrence(ctx context.Context, occurrence internal.Occurrence) (int64, error)
+	GetOccurrence(ctx context.Context, occurrenceID int64) (internal.Occurrence, error)
+	ListOccurrences(ctx context.Context, filter internal.OccurrenceFilter) ([]internal.Occurrence, error)
+	SaveConfirmation(ctx context.Context, confirmation internal.OccurrenceConfirmation) (internal.Occurrence, error)
+	ExpireOccurrences(ctx context.Context, reportedBefore time.Time) (int64, error)
+}
+
+type occurrenceRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewOccurrenceRepository(connection *pgxpool.Pool) IOccurrenceRepository {
+	return &occurrenceRepository{Conn: connection}
+}
+
+func (r *occurrenceRepository) InsertOccurrence(ctx context.Context, occurrence internal.Occurrence) (int64, error) {
+	if err :=
+		r.Conn.QueryRow(
+			ctx,
+			`INSERT INTO occurrence (user_id, type, description, latitude, longitude, time_stamp)
+				VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`, occurrence.UserID, occurrence.Type,
+			occurrence.Description, occurrence.Latitude, occurrence.Longitude, occurrence.TimeStamp).Scan(&occurrence.ID); err != nil {
+		return internal.ZERO, err
+	}
+
+	return occurrence.ID, nil
+}
+
+func (r *occurrenceRepository) GetOccurrence(ctx context.Context, occurrenceID int64) (internal.Occurrence, error) {
+	occurrence := internal.Occurrence{ID: occurrenceID}
+	if err :=
+		r.Conn.QueryRow(
+			ctx,
+			`SELECT user_id, type, description, latitude, longitude, time_stamp, confirmation, disputes, created_at
+				FROM occurrence WHERE id = $1 AND deleted_at IS NULL;`, occurrenceID).Scan(&occurrence.UserID,
+			&occurrence.Type, &occurrence.Description, &occurrence.Latitude, &occurrence.Longitude,
+			&occurrence.TimeStamp, &occurrence.Confirmation, &occurrence.Disputes, &occurrence.CreatedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			return internal.Occurrence{}, nil
+		}
+		return internal.Occurrence{}, err
+	}
+
+	return occurrence, nil
+}
+
+func (r *occurrenceRepository) ListOccurrences(ctx context.Context, filter internal.OccurrenceFilter) ([]internal.Occurrence, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	occurrenceChannel := make(chan []internal.Occurrence)
+	errorChannel := make(chan error)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT id, user_id, type, description, latitude, longitude, time_stamp, confirmation, disputes, created_at
+					FROM occurrence WHERE ($1::INTEGER IS NULL OR type = $1)
+					AND time_stamp BETWEEN $2 AND $3 AND deleted_at IS NULL
+					ORDER BY time_stamp DESC;`, filter.Type, filter.From, filter.To)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		defer rows.Close()
+
+		occurrences := []internal.Occurrence{}
+		for rows.Next() {
+			o := internal.Occurrence{}
+			if err := rows.Scan(
+				&o.ID,
+				&o.UserID,
+				&o.Type,
+				&o.Description,
+				&o.Latitude,
+				&o.Longitude,
+				&o.TimeStamp,
+				&o.Confirmation,
+				&o.Disputes,
+				&o.CreatedAt); err != nil {
+				errorChannel <- err
+				return
+			}
+			occurrences = append(occurrences, o)
+		}
+		occurrenceChannel <- occurrences
+	}()
+
+	select {
+	case occurrences := <-occurrenceChannel:
+		return occurrences, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *occurrenceRepository) SaveConfirmation(ctx context.Context, confirmation internal.OccurrenceConfirmation) (internal.Occurrence, error) {
+	tx, err := r.Conn.Begin(ctx)
+	if err != nil {
+		return internal.Occurrence{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err :=
+		tx.Exec(
+			ctx,
+			`INSERT INTO occurrence_confirmation (occurrence_id, user_id, confirmed) VALUES ($1, $2, $3)
+				ON CONFLICT (occurrence_id, user_id) DO UPDATE SET confirmed = EXCLUDED.confirmed,
+					updated_at = NOW();`, confirmation.OccurrenceID, confirmation.UserID,
+			confirmation.Confirmed); err != nil {
+		return internal.Occurrence{}, err
+	}
+
+	occurrence := internal.Occurrence{ID: confirmation.OccurrenceID}
+	if err :=
+		tx.QueryRow(
+			ctx,
+			`UPDATE occurrence SET
+				confirmation = (SELECT COUNT(*) FROM occurrence_confirmation
+					WHERE occurrence_id = $1 AND confirmed),
+				disputes = (SELECT COUNT(*) FROM occurrence_confirmation
+					WHERE occurrence_id = $1 AND NOT confirmed),
+				updated_at = NOW()
+			WHERE id = $1 AND deleted_at IS NULL
+			RETURNING confirmation, disputes;`, confirmation.OccurrenceID).Scan(&occurrence.Confirmation,
+			&occurrence.Disputes); err != nil {
+		if err == pgx.ErrNoRows {
+			return internal.Occurrence{}, nil
+		}
+		return internal.Occurrence{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return internal.Occurrence{}, err
+	}
+
+	return occurrence, nil
+}
+
+// ExpireOccurrences soft deletes the occurrences reported before
+// reportedBefore that nobody confirmed. Disputes alone do not expire a
+// confirmed occurrence.
+func (r *occurrenceRepository) ExpireOccurrences(ctx context.Context, reportedBefore time.Time) (int64, error) {
+	result, err :=
+		r.Conn.Exec(
+			ctx,
+			`UPDATE occurrence SET deleted_at = $2 WHERE time_stamp < $1
+				AND confirmation = 0 AND deleted_at IS NULL;`, reportedBefore, time.Now())
+	if err != nil {
+		return internal.ZERO, err
+	}
+
+	return result.RowsAffected(), nil
+}
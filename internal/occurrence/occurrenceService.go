@@ -0,0 +1,216 @@
+package occurrence
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/alert"
+	"github.com/amarantec/move-easy/internal/realtime"
+)
+
+const ALERT_TIMEOUT = 30 * time.Second
+
+type IOccurrenceService interface {
+	InsertOccurrence(ctx context.Context, occurrence internal.Occurrence) (int64, error)
+	GetOccurrence(ctx context.Context, occurrenceID int64) (internal.Occurrence, error)
+	ListOccurrences(ctx context.Context, filter internal.OccurrenceFilter) ([]internal.Occurrence, error)
+	ConfirmOccurrence(ctx context.Context, userID, occurrenceID int64) (internal.Occurrence, error)
+	DisputeOccurrence(ctx context.Context, userID, occurrenceID int64) (internal.Occurrence, error)
+	ExpireUnconfirmedOccurrences(ctx context.Context, maxAge time.Duration) (int64, error)
+}
+
+type occurrenceService struct {
+	occurrenceRepository IOccurrenceRepository
+	publisher            realtime.IPublisher
+	alerter              alert.IAlertService
+}
+
+// NewOccurrenceService sends new occurrences to publisher and reported
+// accidents to alerter, each when it is not nil.
+func NewOccurrenceService(repository IOccurrenceRepository, publisher realtime.IPublisher, alerter alert.IAlertService) IOccurrenceService {
+	return &occurrenceService{occurrenceRepository: repository, publisher: publisher, alerter: alerter}
+}
+
+func (s *occurrenceService) InsertOccurrence(ctx context.Context, occurrence internal.Occurrence) (int64, error) {
+	if occurrence.TimeStamp.IsZero() {
+		occurrence.TimeStamp = time.Now()
+	}
+
+	if valid, err := validateOccurrence(occurrence); err != nil || !valid {
+		return internal.ZERO, err
+	}
+
+	id, err := s.occurrenceRepository.InsertOccurrence(ctx, occurrence)
+	if err != nil {
+		return internal.ZERO, err
+	}
+
+	occurrence.ID = id
+	if s.publisher != nil {
+		s.publisher.Publish(internal.RealtimeEvent{
+			Type:   internal.OCCURRENCE_CREATED,
+			Bounds: internal.PointBox(occurrence.Latitude, occurrence.Longitude),
+			Data: internal.OccurrenceEvent{
+				ID:           occurrence.ID,
+				Type:         occurrence.Type,
+				Description:  occurrence.Description,
+				Latitude:     occurrence.Latitude,
+				Longitude:    occurrence.Longitude,
+				TimeStamp:    occurrence.TimeStamp,
+				Confirmation: occurrence.Confirmation,
+				Disputes:     occurrence.Disputes,
+			},
+		})
+	}
+
+	if s.alerter != nil && occurrence.Type == internal.ACCIDENT {
+		go s.alertContacts(occurrence)
+	}
+	return id, nil
+}
+
+// alertContacts runs apart from the request that reported the accident, so
+// a slow notification channel does not hold it.
+func (s *occurrenceService) alertContacts(occurrence internal.Occurrence) {
+	ctx, cancel := context.WithTimeout(context.Background(), ALERT_TIMEOUT)
+	defer cancel()
+
+	if _, err := s.alerter.NotifyOccurrence(ctx, occurrence); err != nil &&
+		!errors.Is(err, alert.ErrAlertContactsEmpty) && !errors.Is(err, alert.ErrAlertNotifierMissing) &&
+		!errors.Is(err, alert.ErrAlertCooldown) {
+		log.Printf("could not alert the contacts of user %d on occurrence %d, error: %v",
+			occurrence.UserID, occurrence.ID, err)
+	}
+}
+
+func (s *occurrenceService) GetOccurrence(ctx context.Context, occurrenceID int64) (internal.Occurrence, error) {
+	if occurrenceID <= internal.ZERO {
+		return internal.Occurrence{}, ErrOccurrenceIDInvalid
+	}
+	return s.occurrenceRepository.GetOccurrence(ctx, occurrenceID)
+}
+
+func (s *occurrenceService) ListOccurrences(ctx context.Context, filter internal.OccurrenceFilter) ([]internal.Occurrence, error) {
+	if filter.Type != nil && !filter.Type.IsValid() {
+		return []internal.Occurrence{}, ErrOccurrenceTypeInvalid
+	}
+
+	if filter.To.IsZero() {
+		filter.To = time.Now()
+	}
+
+	if filter.From.IsZero() {
+		filter.From = filter.To.Add(-24 * time.Hour)
+	}
+
+	if filter.From.After(filter.To) {
+		return []internal.Occurrence{}, ErrOccurrenceTimeWindowInvalid
+	}
+
+	return s.occurrenceRepository.ListOccurrences(ctx, filter)
+}
+
+func (s *occurrenceService) ConfirmOccurrence(ctx context.Context, userID, occurrenceID int64) (internal.Occurrence, error) {
+	return s.saveConfirmation(ctx, internal.OccurrenceConfirmation{
+		OccurrenceID: occurrenceID,
+		UserID:       userID,
+		Confirmed:    true,
+	})
+}
+
+func (s *occurrenceService) DisputeOccurrence(ctx context.Context, userID, occurrenceID int64) (internal.Occurrence, error) {
+	return s.saveConfirmation(ctx, internal.OccurrenceConfirmation{
+		OccurrenceID: occurrenceID,
+		UserID:       userID,
+		Confirmed:    false,
+	})
+}
+
+func (s *occurrenceService) ExpireUnconfirmedOccurrences(ctx context.Context, maxAge time.Duration) (int64, error) {
+	if maxAge <= internal.ZERO {
+		return internal.ZERO, ErrOccurrenceMaxAgeInvalid
+	}
+	return s.occurrenceRepository.ExpireOccurrences(ctx, time.Now().Add(-maxAge))
+}
+
+func (s *occurrenceService) saveConfirmation(ctx context.Context, confirmation internal.OccurrenceConfirmation) (internal.Occurrence, error) {
+	if confirmation.UserID <= internal.ZERO {
+		return internal.Occurrence{}, ErrOccurrenceUserIDInvalid
+	}
+
+	if confirmation.OccurrenceID <= internal.ZERO {
+		return internal.Occurrence{}, ErrOccurrenceIDInvalid
+	}
+
+	occurrence, err := s.occurrenceRepository.GetOccurrence(ctx, confirmation.OccurrenceID)
+	if err != nil {
+		return internal.Occurrence{}, err
+	}
+
+	if occurrence.ID == internal.ZERO {
+		return internal.Occurrence{}, ErrOccurrenceNotFound
+	}
+
+	if occurrence.UserID == confirmation.UserID {
+		return internal.Occurrence{}, ErrOccurrenceOwnConfirmation
+	}
+
+	counters, err := s.occurrenceRepository.SaveConfirmation(ctx, confirmation)
+	if err != nil {
+		return internal.Occurrence{}, err
+	}
+
+	if counters.ID == internal.ZERO {
+		return internal.Occurrence{}, ErrOccurrenceNotFound
+	}
+
+	occurrence.Confirmation = counters.Confirmation
+	occurrence.Disputes = counters.Disputes
+	return occurrence, nil
+}
+
+func validateOccurrence(o internal.Occurrence) (bool, error) {
+	if o.UserID <= internal.ZERO {
+		return false, ErrOccurrenceUserIDInvalid
+	}
+
+	if !o.Type.IsValid() {
+		return false, ErrOccurrenceTypeInvalid
+	}
+
+	if utf8.RuneCountInString(o.Description) > 500 {
+		return false, ErrOccurrenceDescriptionInvalid
+	}
+
+	if o.Type == internal.OTHER && o.Description == internal.EMPTY {
+		return false, ErrOccurrenceDescriptionEmpty
+	}
+
+	if o.Latitude < -90 || o.Latitude > 90 || o.Longitude < -180 || o.Longitude > 180 {
+		return false, ErrOccurrenceLocationInvalid
+	}
+
+	if o.TimeStamp.After(time.Now().Add(5 * time.Minute)) {
+		return false, ErrOccurrenceTimeStampInvalid
+	}
+
+	return true, nil
+}
+
+var (
+	ErrOccurrenceIDInvalid          = errors.New("occurrence id is empty or negative")
+	ErrOccurrenceUserIDInvalid      = errors.New("occurrence user id is empty or negative")
+	ErrOccurrenceTypeInvalid        = errors.New("occurrence type must be between 0-4")
+	ErrOccurrenceDescriptionEmpty   = errors.New("occurrence description is required for type OTHER")
+	ErrOccurrenceDescriptionInvalid = errors.New("occurrence description must have at most 500 characters")
+	ErrOccurrenceLocationInvalid    = errors.New("occurrence latitude or longitude out of range")
+	ErrOccurrenceTimeStampInvalid   = errors.New("occurrence timestamp cannot be in the future")
+	ErrOccurrenceTimeWindowInvalid  = errors.New("occurrence time window start must be before its end")
+	ErrOccurrenceNotFound           = errors.New("occurrence not found")
+	ErrOccurrenceOwnConfirmation    = errors.New("occurrence cannot be confirmed or disputed by its reporter")
+	ErrOccurrenceMaxAgeInvalid      = errors.New("occurrence max age must be positive")
+)
@@ -0,0 +1,351 @@
+package occurrence
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/alert"
+)
+
+type mockOccurrenceRepository struct {
+	InsertOccurrenceFunc func(ctx context.Context, occurrence internal.Occurrence) (int64, error)
+	GetOccurrenceFunc    func(ctx context.Context, occurrenceID int64) (internal.Occurrence, error)
+	ListOccurrencesFunc  func(ctx context.Context, filter internal.OccurrenceFilter) ([]internal.Occurrence, error)
+	SaveConfirmationFunc func(ctx context.Context, confirmation internal.OccurrenceConfirmation) (internal.Occurrence, error)
+	ExpireFunc           func(ctx context.Context, reportedBefore time.Time) (int64, error)
+}
+
+func (m *mockOccurrenceRepository) InsertOccurrence(ctx context.Context, occurrence internal.Occurrence) (int64, error) {
+	if m.InsertOccurrenceFunc != nil {
+		return m.InsertOccurrenceFunc(ctx, occurrence)
+	}
+	return internal.ZERO, ErrInsertOccurrenceFuncNotImplemented
+}
+
+func (m *mockOccurrenceRepository) GetOccurrence(ctx context.Context, occurrenceID int64) (internal.Occurrence, error) {
+	if m.GetOccurrenceFunc != nil {
+		return m.GetOccurrenceFunc(ctx, occurrenceID)
+	}
+	return internal.Occurrence{}, ErrGetOccurrenceFuncNotImplemented
+}
+
+func (m *mockOccurrenceRepository) ListOccurrences(ctx context.Context, filter internal.OccurrenceFilter) ([]internal.Occurrence, error) {
+	if m.ListOccurrencesFunc != nil {
+		return m.ListOccurrencesFunc(ctx, filter)
+	}
+	return []internal.Occurrence{}, ErrListOccurrencesFuncNotImplemented
+}
+
+func (m *mockOccurrenceRepository) SaveConfirmation(ctx context.Context, confirmation internal.OccurrenceConfirmation) (internal.Occurrence, error) {
+	if m.SaveConfirmationFunc != nil {
+		return m.SaveConfirmationFunc(ctx, confirmation)
+	}
+	return internal.Occurrence{}, ErrSaveConfirmationFuncNotImplemented
+}
+
+func (m *mockOccurrenceRepository) ExpireOccurrences(ctx context.Context, reportedBefore time.Time) (int64, error) {
+	if m.ExpireFunc != nil {
+		return m.ExpireFunc(ctx, reportedBefore)
+	}
+	return internal.ZERO, ErrExpireFuncNotImplemented
+}
+
+func TestInsertOccurrence(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     internal.Occurrence
+		wantID    int64
+		wantError error
+	}{
+		{
+			name:   "Ocorrência registrada com sucesso",
+			input:  internal.Occurrence{UserID: 1, Type: internal.LOCKED_BUS, Latitude: -29.88, Longitude: -50.27},
+			wantID: 1,
+		},
+		{
+			name:      "UserID vazio",
+			input:     internal.Occurrence{Type: internal.ACCIDENT, Latitude: -29.88, Longitude: -50.27},
+			wantError: ErrOccurrenceUserIDInvalid,
+		},
+		{
+			name:      "Tipo inválido",
+			input:     internal.Occurrence{UserID: 1, Type: internal.OccurrenceType(9), Latitude: -29.88, Longitude: -50.27},
+			wantError: ErrOccurrenceTypeInvalid,
+		},
+		{
+			name:      "Tipo OTHER sem descrição",
+			input:     internal.Occurrence{UserID: 1, Type: internal.OTHER, Latitude: -29.88, Longitude: -50.27},
+			wantError: ErrOccurrenceDescriptionEmpty,
+		},
+		{
+			name:      "Localização inválida",
+			input:     internal.Occurrence{UserID: 1, Type: internal.ACCIDENT, Latitude: 91, Longitude: -50.27},
+			wantError: ErrOccurrenceLocationInvalid,
+		},
+		{
+			name:      "Data no futuro",
+			input:     internal.Occurrence{UserID: 1, Type: internal.ACCIDENT, TimeStamp: time.Now().Add(time.Hour)},
+			wantError: ErrOccurrenceTimeStampInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockOccurrenceRepository{
+				InsertOccurrenceFunc: func(ctx context.Context, occurrence internal.Occurrence) (int64, error) {
+					if occurrence.TimeStamp.IsZero() {
+						return internal.ZERO, ErrMissingTimeStamp
+					}
+					return 1, nil
+				},
+			}
+			service := NewOccurrenceService(mockRepo, nil, nil)
+
+			id, err := service.InsertOccurrence(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+
+			if id != tt.wantID {
+				t.Errorf("[%s] ID esperado: %d, recebido: %d", tt.name, tt.wantID, id)
+			}
+		})
+	}
+}
+
+type mockAlertService struct {
+	alert.IAlertService
+	occurrences chan internal.Occurrence
+}
+
+func (m *mockAlertService) NotifyOccurrence(ctx context.Context, occurrence internal.Occurrence) (internal.Alert, error) {
+	m.occurrences <- occurrence
+	return internal.Alert{}, nil
+}
+
+func TestInsertOccurrenceAlertsContacts(t *testing.T) {
+	mockRepo := &mockOccurrenceRepository{
+		InsertOccurrenceFunc: func(ctx context.Context, occurrence internal.Occurrence) (int64, error) {
+			return 7, nil
+		},
+	}
+	alerter := &mockAlertService{occurrences: make(chan internal.Occurrence, 1)}
+	service := NewOccurrenceService(mockRepo, nil, alerter)
+
+	if _, err := service.InsertOccurrence(context.Background(),
+		internal.Occurrence{UserID: 1, Type: internal.LOCKED_BUS, Latitude: -29.88, Longitude: -50.27}); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if _, err := service.InsertOccurrence(context.Background(),
+		internal.Occurrence{UserID: 1, Type: internal.ACCIDENT, Latitude: -29.88, Longitude: -50.27}); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	select {
+	case occurrence := <-alerter.occurrences:
+		if occurrence.Type != internal.ACCIDENT || occurrence.ID != 7 {
+			t.Errorf("Esperava o acidente 7, recebeu: %+v", occurrence)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Os contatos não foram alertados do acidente")
+	}
+
+	select {
+	case occurrence := <-alerter.occurrences:
+		t.Errorf("Alerta inesperado para: %+v", occurrence)
+	default:
+	}
+}
+
+type recordingPublisher struct {
+	events []internal.RealtimeEvent
+}
+
+func (p *recordingPublisher) Publish(event internal.RealtimeEvent) {
+	p.events = append(p.events, event)
+}
+
+func TestInsertOccurrencePublishesWithoutUser(t *testing.T) {
+	mockRepo := &mockOccurrenceRepository{
+		InsertOccurrenceFunc: func(ctx context.Context, occurrence internal.Occurrence) (int64, error) {
+			return 5, nil
+		},
+	}
+	publisher := &recordingPublisher{}
+	service := NewOccurrenceService(mockRepo, publisher, nil)
+
+	if _, err := service.InsertOccurrence(context.Background(),
+		internal.Occurrence{UserID: 1, Type: internal.LOCKED_BUS, Latitude: -29.88, Longitude: -50.27}); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if len(publisher.events) != 1 {
+		t.Fatalf("Esperava 1 evento, recebeu %d", len(publisher.events))
+	}
+
+	occurrence, ok := publisher.events[0].Data.(internal.OccurrenceEvent)
+	if !ok || occurrence.ID != 5 || occurrence.Type != internal.LOCKED_BUS {
+		t.Errorf("Evento de ocorrência sem usuário esperado, recebido: %#v", publisher.events[0].Data)
+	}
+}
+
+func TestListOccurrences(t *testing.T) {
+	accident := internal.ACCIDENT
+	invalid := internal.OccurrenceType(-1)
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		filter    internal.OccurrenceFilter
+		wantError error
+		check     func(t *testing.T, filter internal.OccurrenceFilter)
+	}{
+		{
+			name:   "Janela padrão de 24 horas",
+			filter: internal.OccurrenceFilter{},
+			check: func(t *testing.T, filter internal.OccurrenceFilter) {
+				if filter.To.Sub(filter.From) != 24*time.Hour {
+					t.Errorf("Janela esperada de 24h, recebida: %v", filter.To.Sub(filter.From))
+				}
+			},
+		},
+		{
+			name:   "Filtro por tipo",
+			filter: internal.OccurrenceFilter{Type: &accident, From: now.Add(-time.Hour), To: now},
+			check: func(t *testing.T, filter internal.OccurrenceFilter) {
+				if filter.Type == nil || *filter.Type != internal.ACCIDENT {
+					t.Errorf("Tipo esperado: %d, recebido: %v", internal.ACCIDENT, filter.Type)
+				}
+			},
+		},
+		{
+			name:      "Tipo inválido",
+			filter:    internal.OccurrenceFilter{Type: &invalid},
+			wantError: ErrOccurrenceTypeInvalid,
+		},
+		{
+			name:      "Janela invertida",
+			filter:    internal.OccurrenceFilter{From: now, To: now.Add(-time.Hour)},
+			wantError: ErrOccurrenceTimeWindowInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockOccurrenceRepository{
+				ListOccurrencesFunc: func(ctx context.Context, filter internal.OccurrenceFilter) ([]internal.Occurrence, error) {
+					if tt.check != nil {
+						tt.check(t, filter)
+					}
+					return []internal.Occurrence{}, nil
+				},
+			}
+			service := NewOccurrenceService(mockRepo, nil, nil)
+
+			_, err := service.ListOccurrences(context.Background(), tt.filter)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+		})
+	}
+}
+
+func TestConfirmOccurrence(t *testing.T) {
+	confirmations := map[int64]bool{}
+
+	mockRepo := &mockOccurrenceRepository{
+		GetOccurrenceFunc: func(ctx context.Context, occurrenceID int64) (internal.Occurrence, error) {
+			if occurrenceID != 1 {
+				return internal.Occurrence{}, nil
+			}
+			return internal.Occurrence{ID: 1, UserID: 10, Type: internal.ACCIDENT}, nil
+		},
+		SaveConfirmationFunc: func(ctx context.Context, confirmation internal.OccurrenceConfirmation) (internal.Occurrence, error) {
+			confirmations[confirmation.UserID] = confirmation.Confirmed
+			counters := internal.Occurrence{ID: confirmation.OccurrenceID}
+			for _, confirmed := range confirmations {
+				if confirmed {
+					counters.Confirmation++
+				} else {
+					counters.Disputes++
+				}
+			}
+			return counters, nil
+		},
+	}
+	service := NewOccurrenceService(mockRepo, nil, nil)
+
+	tests := []struct {
+		name             string
+		userID           int64
+		occurrenceID     int64
+		confirm          bool
+		wantConfirmation int64
+		wantDisputes     int64
+		wantError        error
+	}{
+		{name: "Primeira confirmação", userID: 20, occurrenceID: 1, confirm: true, wantConfirmation: 1},
+		{name: "Confirmação repetida não soma", userID: 20, occurrenceID: 1, confirm: true, wantConfirmation: 1},
+		{name: "Outro usuário contesta", userID: 30, occurrenceID: 1, confirm: false, wantConfirmation: 1, wantDisputes: 1},
+		{name: "Usuário muda para contestação", userID: 20, occurrenceID: 1, confirm: false, wantDisputes: 2},
+		{name: "Autor não pode confirmar", userID: 10, occurrenceID: 1, confirm: true, wantError: ErrOccurrenceOwnConfirmation},
+		{name: "Ocorrência inexistente", userID: 20, occurrenceID: 2, confirm: true, wantError: ErrOccurrenceNotFound},
+		{name: "UserID vazio", userID: internal.ZERO, occurrenceID: 1, confirm: true, wantError: ErrOccurrenceUserIDInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got internal.Occurrence
+			var err error
+			if tt.confirm {
+				got, err = service.ConfirmOccurrence(context.Background(), tt.userID, tt.occurrenceID)
+			} else {
+				got, err = service.DisputeOccurrence(context.Background(), tt.userID, tt.occurrenceID)
+			}
+
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+
+			if got.Confirmation != tt.wantConfirmation || got.Disputes != tt.wantDisputes {
+				t.Errorf("[%s] Contadores esperados: %d/%d, recebidos: %d/%d", tt.name,
+					tt.wantConfirmation, tt.wantDisputes, got.Confirmation, got.Disputes)
+			}
+		})
+	}
+}
+
+func TestExpireUnconfirmedOccurrences(t *testing.T) {
+	mockRepo := &mockOccurrenceRepository{
+		ExpireFunc: func(ctx context.Context, reportedBefore time.Time) (int64, error) {
+			if time.Since(reportedBefore) < time.Hour {
+				return internal.ZERO, ErrWrongExpiryCutoff
+			}
+			return 3, nil
+		},
+	}
+	service := NewOccurrenceService(mockRepo, nil, nil)
+
+	expired, err := service.ExpireUnconfirmedOccurrences(context.Background(), time.Hour)
+	if err != nil || expired != 3 {
+		t.Errorf("Esperava 3 ocorrências expiradas, recebeu %d (erro: %v)", expired, err)
+	}
+
+	if _, err := service.ExpireUnconfirmedOccurrences(context.Background(), internal.ZERO); !errors.Is(err, ErrOccurrenceMaxAgeInvalid) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrOccurrenceMaxAgeInvalid, err)
+	}
+}
+
+var (
+	ErrSaveConfirmationFuncNotImplemented = errors.New("SaveConfirmationFunc not implemented")
+	ErrExpireFuncNotImplemented           = errors.New("ExpireFunc not implemented")
+	ErrWrongExpiryCutoff                  = errors.New("expiry cutoff does not respect max age")
+	ErrInsertOccurrenceFuncNotImplemented = errors.New("InsertOccurrenceFunc not implemented")
+	ErrGetOccurrenceFuncNotImplemented    = errors.New("GetOccurrenceFunc not implemented")
+	ErrListOccurrencesFuncNotImplemented  = errors.New("ListOccurrencesFunc not implemented")
+	ErrMissingTimeStamp                   = errors.New("occurrence timestamp not filled")
+)
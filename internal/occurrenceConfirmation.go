@@ -0,0 +1,12 @@
+package internal
+
+import "time"
+
+type OccurrenceConfirmation struct {
+	ID				int64
+	OccurrenceID	int64
+	UserID			int64
+	Confirmed		bool
+	CreatedAt		time.Time
+	UpdatedAt		*time.Time
+}
@@ -0,0 +1,9 @@
+package internal
+
+import "time"
+
+type OccurrenceFilter struct {
+	Type	*OccurrenceType
+	From	time.Time
+	To		time.Time
+}
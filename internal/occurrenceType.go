@@ -9,4 +9,7 @@ const (
 	ITINERARY_CHANGE
 	OTHER
 )
-	
+
+func (t OccurrenceType) IsValid() bool {
+	return t >= STOPPED_TRAFFIC && t <= OTHER
+}
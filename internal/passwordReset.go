@@ -0,0 +1,17 @@
+package internal
+
+import "time"
+
+type PasswordResetToken struct {
+	ID			int64
+	UserID		int64
+	TokenHash	string
+	ExpiresAt	time.Time
+	UsedAt		*time.Time
+	CreatedAt	time.Time
+}
+
+type PasswordReset struct {
+	Token		string
+	NewPassword	NewPassword
+}
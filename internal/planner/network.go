@@ -0,0 +1,198 @@
+package planner
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/bus"
+	"github.com/amarantec/move-easy/internal/eta"
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+// GRID_CELL is the size in degrees of the cells used to find the places
+// near a point without measuring the distance to every one of them.
+const GRID_CELL = 0.01
+
+type place struct {
+	internal.JourneyPlace
+	metro bool
+}
+
+type footpath struct {
+	to       int
+	distance float64
+}
+
+type routeStop struct {
+	route    int
+	position int
+}
+
+// route is a bus line as the ordered places it serves, with the travel time
+// from its first stop to each of them and the departure clock times from
+// the first stop per weekday.
+type route struct {
+	lineID  int64
+	name    string
+	places  []int
+	offsets []time.Duration
+	starts  map[time.Weekday][]time.Duration
+}
+
+type network struct {
+	places    []place
+	routes    []route
+	routesAt  [][]routeStop
+	footpaths [][]footpath
+	stations  []int
+	grid      map[[2]int][]int
+	builtAt   time.Time
+}
+
+// buildNetwork indexes the stops, lines and stations for the searches. Lines
+// without ordered stops are ridden from their first to their last stop, and
+// missing travel times are estimated at eta.AVERAGE_BUS_SPEED.
+func buildNetwork(stops []internal.BusStop, lines []internal.BusLine, stations []internal.MetroStation) *network {
+	n := &network{grid: map[[2]int][]int{}, builtAt: time.Now()}
+
+	stopPlaces := map[int64]int{}
+	for _, stop := range stops {
+		id := stop.ID
+		stopPlaces[id] = n.addPlace(place{JourneyPlace: internal.JourneyPlace{
+			Name: stop.Name, BusStopID: &id, Latitude: stop.Latitude, Longitude: stop.Longitude,
+		}})
+	}
+
+	for _, station := range stations {
+		id := station.ID
+		n.stations = append(n.stations, n.addPlace(place{JourneyPlace: internal.JourneyPlace{
+			Name: station.StationName, MetroStationID: &id, Latitude: station.Latitude, Longitude: station.Longitude,
+		}, metro: true}))
+	}
+
+	n.routesAt = make([][]routeStop, len(n.places))
+	for _, line := range lines {
+		lineStops := line.Stops
+		if len(lineStops) < 2 {
+			lineStops = []internal.BusLineStop{{BusStop: line.BusInit}, {BusStop: line.BusEnd}}
+		}
+
+		r := route{lineID: line.ID, name: line.Name, starts: map[time.Weekday][]time.Duration{}}
+		for i, lineStop := range lineStops {
+			index, ok := stopPlaces[lineStop.BusStop.ID]
+			if !ok {
+				r.places = nil
+				break
+			}
+
+			offset := time.Duration(internal.ZERO)
+			if i > internal.ZERO {
+				offset = r.offsets[i-1] + hopTime(n.places[r.places[i-1]], n.places[index], lineStop.TravelTimeOffset)
+			}
+			r.places = append(r.places, index)
+			r.offsets = append(r.offsets, offset)
+		}
+
+		for _, schedule := range line.Schedules {
+			day, err := bus.ParseDayOfWeek(schedule.DayOfWeek)
+			if err != nil || schedule.StartTime == nil {
+				continue
+			}
+			start := schedule.StartTime
+			r.starts[day] = append(r.starts[day], time.Duration(start.Hour())*time.Hour+
+				time.Duration(start.Minute())*time.Minute+time.Duration(start.Second())*time.Second)
+		}
+
+		if len(r.places) < 2 || len(r.starts) == internal.ZERO {
+			continue
+		}
+
+		for day := range r.starts {
+			sort.Slice(r.starts[day], func(i, j int) bool { return r.starts[day][i] < r.starts[day][j] })
+		}
+
+		for position, index := range r.places {
+			n.routesAt[index] = append(n.routesAt[index], routeStop{route: len(n.routes), position: position})
+		}
+		n.routes = append(n.routes, r)
+	}
+
+	n.footpaths = make([][]footpath, len(n.places))
+	for i, p := range n.places {
+		for _, j := range n.nearby(p.Latitude, p.Longitude, MAX_TRANSFER_WALK) {
+			if i == j {
+				continue
+			}
+			n.footpaths[i] = append(n.footpaths[i], footpath{
+				to:       j,
+				distance: utils.HaversineDistance(p.Latitude, p.Longitude, n.places[j].Latitude, n.places[j].Longitude),
+			})
+		}
+	}
+
+	return n
+}
+
+func (n *network) addPlace(p place) int {
+	index := len(n.places)
+	n.places = append(n.places, p)
+	cell := gridCell(p.Latitude, p.Longitude)
+	n.grid[cell] = append(n.grid[cell], index)
+	return index
+}
+
+// nearby returns the places within radius meters of a point.
+func (n *network) nearby(latitude, longitude, radius float64) []int {
+	minLat, minLon, maxLat, maxLon := utils.BoundingBox(latitude, longitude, radius)
+	from := gridCell(minLat, minLon)
+	to := gridCell(maxLat, maxLon)
+
+	places := []int{}
+	for x := from[0]; x <= to[0]; x++ {
+		for y := from[1]; y <= to[1]; y++ {
+			for _, index := range n.grid[[2]int{x, y}] {
+				p := n.places[index]
+				if utils.HaversineDistance(latitude, longitude, p.Latitude, p.Longitude) <= radius {
+					places = append(places, index)
+				}
+			}
+		}
+	}
+	return places
+}
+
+// trips returns the departures from the first stop of a route between from
+// and until, reading the schedules of every day the window touches so late
+// trips are found from the day before and the day after.
+func (r *route) trips(from, until time.Time) []time.Time {
+	trips := []time.Time{}
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location()).AddDate(0, 0, -1)
+	for !day.After(until) {
+		for _, start := range r.starts[day.Weekday()] {
+			trip := day.Add(start)
+			if !trip.Before(from.Add(-r.offsets[len(r.offsets)-1])) && !trip.After(until) {
+				trips = append(trips, trip)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return trips
+}
+
+func hopTime(from, to place, offset *int64) time.Duration {
+	if offset != nil {
+		return time.Duration(*offset) * time.Second
+	}
+	distance := utils.HaversineDistance(from.Latitude, from.Longitude, to.Latitude, to.Longitude)
+	return time.Duration(distance / eta.AVERAGE_BUS_SPEED * float64(time.Second))
+}
+
+func walkTime(distance float64) time.Duration {
+	return time.Duration(distance / WALK_SPEED * float64(time.Second))
+}
+
+func gridCell(latitude, longitude float64) [2]int {
+	return [2]int{int(math.Floor(latitude / GRID_CELL)), int(math.Floor(longitude / GRID_CELL))}
+}
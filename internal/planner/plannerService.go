@@ -0,0 +1,166 @@
+package planner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+const (
+	// WALK_SPEED in meters per second, measured in a straight line.
+	WALK_SPEED        = 1.2
+	MAX_ACCESS_WALK   = 1000.0
+	MAX_TRANSFER_WALK = 400.0
+	MAX_WALK_ONLY     = 2000.0
+	MAX_TRANSFERS     = 3
+	// MAX_JOURNEY_DURATION bounds the trips read for a search.
+	MAX_JOURNEY_DURATION = 4 * time.Hour
+	// METRO_SPEED in meters per second, stops included, and METRO_WAIT, half
+	// of the usual headway, estimate metro rides since the schema has no
+	// metro lines or schedules.
+	METRO_SPEED = 11.0
+	METRO_WAIT  = 5 * time.Minute
+
+	DEFAULT_PLAN_LIMIT = 3
+	MAX_PLAN_LIMIT     = 10
+	// NETWORK_TTL is how long the loaded network is reused before the stops,
+	// lines and schedules are read again.
+	NETWORK_TTL = 5 * time.Minute
+)
+
+// IPlannerRepository is implemented by the GTFS repository, which already
+// reads the whole network for the feed export.
+type IPlannerRepository interface {
+	ListBusStops(ctx context.Context) ([]internal.BusStop, error)
+	ListBusLines(ctx context.Context) ([]internal.BusLine, error)
+	ListMetroStations(ctx context.Context) ([]internal.MetroStation, error)
+}
+
+type IPlannerService interface {
+	Plan(ctx context.Context, from, to internal.JourneyPlace, depart time.Time, limit int) ([]internal.Journey, error)
+}
+
+type plannerService struct {
+	repository IPlannerRepository
+	mu         sync.Mutex
+	network    *network
+}
+
+func NewPlannerService(repo IPlannerRepository) IPlannerService {
+	return &plannerService{repository: repo}
+}
+
+// Plan returns up to limit journeys ranked by arrival time and then by
+// number of transfers. After each search the departure is moved past the
+// earliest journey found, so later options are offered when the fastest
+// ones are fewer than limit.
+func (s *plannerService) Plan(ctx context.Context, from, to internal.JourneyPlace, depart time.Time, limit int) ([]internal.Journey, error) {
+	if !utils.ValidCoordinates(from.Latitude, from.Longitude) || !utils.ValidCoordinates(to.Latitude, to.Longitude) {
+		return []internal.Journey{}, ErrPlanLocationInvalid
+	}
+
+	if limit <= internal.ZERO {
+		limit = DEFAULT_PLAN_LIMIT
+	} else if limit > MAX_PLAN_LIMIT {
+		return []internal.Journey{}, ErrPlanLimitInvalid
+	}
+
+	if depart.IsZero() {
+		depart = time.Now()
+	}
+	depart = depart.In(time.Local)
+
+	n, err := s.loadNetwork(ctx)
+	if err != nil {
+		return []internal.Journey{}, err
+	}
+
+	journeys := []internal.Journey{}
+	seen := map[string]bool{}
+	after := depart
+	for i := internal.ZERO; i < limit && len(journeys) < limit; i++ {
+		next := time.Time{}
+		for _, journey := range n.search(from, to, after, i == internal.ZERO) {
+			if journey.Transfers == internal.ZERO && len(journey.Legs) == 1 && journey.Legs[0].Mode == internal.WALK {
+				journeys = append(journeys, journey)
+				continue
+			}
+
+			if key := journeyKey(journey); !seen[key] {
+				seen[key] = true
+				journeys = append(journeys, journey)
+			}
+			if improves(journey.Departure, next) {
+				next = journey.Departure
+			}
+		}
+
+		if next.IsZero() {
+			break
+		}
+		after = next.Add(time.Minute)
+	}
+
+	sort.SliceStable(journeys, func(i, j int) bool {
+		if !journeys[i].Arrival.Equal(journeys[j].Arrival) {
+			return journeys[i].Arrival.Before(journeys[j].Arrival)
+		}
+		return journeys[i].Transfers < journeys[j].Transfers
+	})
+
+	if len(journeys) > limit {
+		journeys = journeys[:limit]
+	}
+	return journeys, nil
+}
+
+func (s *plannerService) loadNetwork(ctx context.Context) (*network, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network != nil && time.Since(s.network.builtAt) < NETWORK_TTL {
+		return s.network, nil
+	}
+
+	stops, err := s.repository.ListBusStops(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := s.repository.ListBusLines(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stations, err := s.repository.ListMetroStations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.network = buildNetwork(stops, lines, stations)
+	return s.network, nil
+}
+
+// journeyKey identifies a journey by its rides, so the same vehicles found
+// again by a later search are not offered twice.
+func journeyKey(journey internal.Journey) string {
+	parts := []string{}
+	for _, leg := range journey.Legs {
+		if leg.Mode != internal.WALK {
+			parts = append(parts, fmt.Sprintf("%s:%d:%d", leg.Mode, leg.LineID, leg.Departure.Unix()))
+		}
+	}
+	return strings.Join(parts, "|")
+}
+
+var (
+	ErrPlanLocationInvalid = errors.New("plan origin or destination latitude or longitude out of range")
+	ErrPlanLimitInvalid    = errors.New("plan limit must be at most 10")
+)
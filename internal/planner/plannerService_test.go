@@ -0,0 +1,169 @@
+package planner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+type mockPlannerRepository struct {
+	stops    []internal.BusStop
+	lines    []internal.BusLine
+	stations []internal.MetroStation
+}
+
+func (m *mockPlannerRepository) ListBusStops(ctx context.Context) ([]internal.BusStop, error) {
+	return m.stops, nil
+}
+
+func (m *mockPlannerRepository) ListBusLines(ctx context.Context) ([]internal.BusLine, error) {
+	return m.lines, nil
+}
+
+func (m *mockPlannerRepository) ListMetroStations(ctx context.Context) ([]internal.MetroStation, error) {
+	return m.stations, nil
+}
+
+func clock(hour, minute int) *time.Time {
+	t := time.Date(2000, time.January, 1, hour, minute, 0, 0, time.UTC)
+	return &t
+}
+
+func lineStops(lineID int64, offset int64, stops ...internal.BusStop) []internal.BusLineStop {
+	lineStops := []internal.BusLineStop{}
+	for i, stop := range stops {
+		lineStop := internal.BusLineStop{BusLineID: lineID, BusStop: stop, Sequence: i + 1}
+		if i > 0 {
+			lineStop.TravelTimeOffset = &offset
+		}
+		lineStops = append(lineStops, lineStop)
+	}
+	return lineStops
+}
+
+// newTestNetwork builds three lines: 1 goes south from A to C, 2 goes east
+// from C to E, and 3 goes from A straight to E but much slower.
+func newTestNetwork() *mockPlannerRepository {
+	a := internal.BusStop{ID: 1, Name: "A", Latitude: -29.880, Longitude: -50.270}
+	b := internal.BusStop{ID: 2, Name: "B", Latitude: -29.890, Longitude: -50.270}
+	c := internal.BusStop{ID: 3, Name: "C", Latitude: -29.900, Longitude: -50.270}
+	e := internal.BusStop{ID: 4, Name: "E", Latitude: -29.900, Longitude: -50.240}
+
+	monday := time.Monday.String()
+	return &mockPlannerRepository{
+		stops: []internal.BusStop{a, b, c, e},
+		lines: []internal.BusLine{
+			{ID: 1, Name: "1", Stops: lineStops(1, 120, a, b, c), Schedules: []internal.BusSchedules{
+				{DayOfWeek: monday, StartTime: clock(8, 5)},
+				{DayOfWeek: monday, StartTime: clock(8, 35)},
+			}},
+			{ID: 2, Name: "2", Stops: lineStops(2, 300, c, e), Schedules: []internal.BusSchedules{
+				{DayOfWeek: monday, StartTime: clock(8, 20)},
+			}},
+			{ID: 3, Name: "3", Stops: lineStops(3, 1800, a, e), Schedules: []internal.BusSchedules{
+				{DayOfWeek: monday, StartTime: clock(8, 10)},
+			}},
+		},
+	}
+}
+
+func TestPlan(t *testing.T) {
+	service := NewPlannerService(newTestNetwork())
+	depart := time.Date(2026, time.October, 19, 8, 0, 0, 0, time.Local)
+	from := internal.JourneyPlace{Latitude: -29.8795, Longitude: -50.2700}
+	to := internal.JourneyPlace{Latitude: -29.9005, Longitude: -50.2400}
+
+	journeys, err := service.Plan(context.Background(), from, to, depart, 3)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if len(journeys) != 2 {
+		t.Fatalf("Itinerários esperados: 2, recebidos: %d", len(journeys))
+	}
+
+	fastest := journeys[0]
+	if fastest.Transfers != 1 || len(fastest.Legs) != 4 {
+		t.Fatalf("Itinerário com uma baldeação esperado: %+v", fastest)
+	}
+
+	modes := []internal.JourneyMode{internal.WALK, internal.BUS, internal.BUS, internal.WALK}
+	for i, leg := range fastest.Legs {
+		if leg.Mode != modes[i] {
+			t.Errorf("Trecho %d esperado: %s, recebido: %s", i, modes[i], leg.Mode)
+		}
+	}
+
+	if fastest.Legs[1].LineName != "1" || fastest.Legs[2].LineName != "2" || fastest.Legs[1].Stops != 2 {
+		t.Errorf("Linhas 1 e 2 esperadas: %+v", fastest.Legs)
+	}
+
+	if !fastest.Legs[0].Arrival.Equal(depart.Add(5*time.Minute)) || !fastest.Departure.Before(depart.Add(5*time.Minute)) {
+		t.Errorf("Saída esperada logo antes das 08:05, recebida: %v", fastest.Departure)
+	}
+
+	if want := depart.Add(25 * time.Minute); fastest.Legs[2].Arrival != want {
+		t.Errorf("Chegada em E esperada: %v, recebida: %v", want, fastest.Legs[2].Arrival)
+	}
+
+	direct := journeys[1]
+	if direct.Transfers != 0 || direct.Legs[1].LineName != "3" || !direct.Arrival.After(fastest.Arrival) {
+		t.Errorf("Itinerário direto mais lento esperado: %+v", direct)
+	}
+}
+
+func TestPlanMetro(t *testing.T) {
+	service := NewPlannerService(&mockPlannerRepository{stations: []internal.MetroStation{
+		{ID: 1, StationName: "Mercado", Latitude: -30.027, Longitude: -51.228},
+		{ID: 2, StationName: "Novo Hamburgo", Latitude: -29.687, Longitude: -51.128},
+	}})
+	depart := time.Date(2026, time.October, 19, 8, 0, 0, 0, time.Local)
+
+	journeys, err := service.Plan(context.Background(),
+		internal.JourneyPlace{Latitude: -30.027, Longitude: -51.228},
+		internal.JourneyPlace{Latitude: -29.687, Longitude: -51.128}, depart, 1)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if len(journeys) != 1 || len(journeys[0].Legs) != 1 || journeys[0].Legs[0].Mode != internal.METRO {
+		t.Fatalf("Itinerário de metrô esperado: %+v", journeys)
+	}
+
+	leg := journeys[0].Legs[0]
+	if want := depart.Add(METRO_WAIT + time.Duration(leg.Distance/METRO_SPEED*float64(time.Second))); !leg.Arrival.Equal(want) {
+		t.Errorf("Chegada esperada: %v, recebida: %v", want, leg.Arrival)
+	}
+}
+
+func TestPlanInvalid(t *testing.T) {
+	service := NewPlannerService(&mockPlannerRepository{})
+	valid := internal.JourneyPlace{Latitude: -29.88, Longitude: -50.27}
+
+	tests := []struct {
+		name      string
+		from      internal.JourneyPlace
+		limit     int
+		wantError error
+	}{
+		{name: "Origem inválida", from: internal.JourneyPlace{Latitude: 95}, wantError: ErrPlanLocationInvalid},
+		{name: "Limite acima do máximo", from: valid, limit: MAX_PLAN_LIMIT + 1, wantError: ErrPlanLimitInvalid},
+		{name: "Sem itinerários", from: valid, limit: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			journeys, err := service.Plan(context.Background(), tt.from, internal.JourneyPlace{Latitude: -29.99, Longitude: -50.27}, time.Time{}, tt.limit)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+
+			if len(journeys) != 0 {
+				t.Errorf("[%s] Nenhum itinerário esperado, recebidos: %d", tt.name, len(journeys))
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package planner
+
+import (
+	"sort"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+type labelKind int
+
+const (
+	ACCESS labelKind = iota
+	RIDE
+	METRO_RIDE
+	TRANSFER
+)
+
+// label records how a place was reached in a round, pointing back to the
+// place the leg started from.
+type label struct {
+	kind      labelKind
+	from      int
+	route     int
+	trip      time.Time
+	boardPos  int
+	alightPos int
+	departure time.Time
+	distance  float64
+}
+
+type search struct {
+	network  *network
+	origin   internal.JourneyPlace
+	target   internal.JourneyPlace
+	depart   time.Time
+	until    time.Time
+	arrivals [][]time.Time
+	labels   [][]*label
+	best     []time.Time
+	trips    map[int][]time.Time
+}
+
+// search runs RAPTOR: round k finds the earliest arrival at every place using
+// at most k rides, so each round that reaches the target earlier than all the
+// previous ones yields a journey that is not beaten in both arrival time and
+// number of transfers. The metro, which has no lines in the schema, is ridden
+// as a single line linking every station.
+func (n *network) search(origin, target internal.JourneyPlace, depart time.Time, includeWalk bool) []internal.Journey {
+	s := &search{
+		network: n,
+		origin:  origin,
+		target:  target,
+		depart:  depart,
+		until:   depart.Add(MAX_JOURNEY_DURATION),
+		best:    make([]time.Time, len(n.places)),
+		trips:   map[int][]time.Time{},
+	}
+
+	journeys := []internal.Journey{}
+	bestTarget := time.Time{}
+
+	direct := utils.HaversineDistance(origin.Latitude, origin.Longitude, target.Latitude, target.Longitude)
+	if includeWalk && direct <= MAX_WALK_ONLY {
+		bestTarget = depart.Add(walkTime(direct))
+		journeys = append(journeys, newJourney([]internal.JourneyLeg{walkLeg(origin, target, depart, bestTarget, direct)}))
+	}
+
+	egress := map[int]float64{}
+	for _, index := range n.nearby(target.Latitude, target.Longitude, MAX_ACCESS_WALK) {
+		egress[index] = s.distanceTo(index, target)
+	}
+
+	s.arrivals = append(s.arrivals, make([]time.Time, len(n.places)))
+	s.labels = append(s.labels, make([]*label, len(n.places)))
+	marked := map[int]bool{}
+	for _, index := range n.nearby(origin.Latitude, origin.Longitude, MAX_ACCESS_WALK) {
+		distance := s.distanceTo(index, origin)
+		arrival := depart.Add(walkTime(distance))
+		s.arrivals[0][index] = arrival
+		s.labels[0][index] = &label{kind: ACCESS, departure: depart, distance: distance}
+		s.best[index] = arrival
+		marked[index] = true
+	}
+
+	for k := 1; k <= MAX_TRANSFERS+1 && len(marked) > internal.ZERO; k++ {
+		s.arrivals = append(s.arrivals, append([]time.Time{}, s.arrivals[k-1]...))
+		s.labels = append(s.labels, make([]*label, len(n.places)))
+
+		improved := map[int]bool{}
+		update := func(index int, arrival time.Time, l *label) {
+			if improves(arrival, s.best[index]) && improves(arrival, bestTarget) {
+				s.arrivals[k][index] = arrival
+				s.labels[k][index] = l
+				s.best[index] = arrival
+				improved[index] = true
+			}
+		}
+
+		s.scanRoutes(k, marked, update)
+
+		for index := range marked {
+			if !n.places[index].metro {
+				continue
+			}
+			departure := s.arrivals[k-1][index].Add(METRO_WAIT)
+			for _, station := range n.stations {
+				if station == index {
+					continue
+				}
+				distance := s.distanceTo(station, n.places[index].JourneyPlace)
+				update(station, departure.Add(time.Duration(distance/METRO_SPEED*float64(time.Second))),
+					&label{kind: METRO_RIDE, from: index, departure: departure, distance: distance})
+			}
+		}
+
+		ridden := make([]int, internal.ZERO, len(improved))
+		for index := range improved {
+			ridden = append(ridden, index)
+		}
+		for _, index := range ridden {
+			for _, path := range n.footpaths[index] {
+				update(path.to, s.arrivals[k][index].Add(walkTime(path.distance)),
+					&label{kind: TRANSFER, from: index, distance: path.distance})
+			}
+		}
+
+		targetPlace := -1
+		targetArrival := time.Time{}
+		for index := range improved {
+			if distance, ok := egress[index]; ok {
+				if arrival := s.arrivals[k][index].Add(walkTime(distance)); improves(arrival, targetArrival) {
+					targetPlace, targetArrival = index, arrival
+				}
+			}
+		}
+
+		if targetPlace >= internal.ZERO && improves(targetArrival, bestTarget) {
+			bestTarget = targetArrival
+			journeys = append(journeys, s.journey(k, targetPlace, targetArrival, egress[targetPlace]))
+		}
+
+		marked = improved
+	}
+
+	return journeys
+}
+
+// scanRoutes rides every route serving a place marked in the previous round,
+// from the first marked position, boarding the earliest trip that can be
+// caught at each stop.
+func (s *search) scanRoutes(k int, marked map[int]bool, update func(int, time.Time, *label)) {
+	queue := map[int]int{}
+	for index := range marked {
+		for _, at := range s.network.routesAt[index] {
+			if position, ok := queue[at.route]; !ok || at.position < position {
+				queue[at.route] = at.position
+			}
+		}
+	}
+
+	for r, start := range queue {
+		rt := &s.network.routes[r]
+		trips, ok := s.trips[r]
+		if !ok {
+			trips = rt.trips(s.depart, s.until)
+			s.trips[r] = trips
+		}
+
+		trip := -1
+		boardPos := -1
+		for position := start; position < len(rt.places); position++ {
+			index := rt.places[position]
+			if trip >= internal.ZERO {
+				update(index, trips[trip].Add(rt.offsets[position]), &label{
+					kind: RIDE, from: rt.places[boardPos], route: r, trip: trips[trip], boardPos: boardPos, alightPos: position,
+				})
+			}
+
+			previous := s.arrivals[k-1][index]
+			if previous.IsZero() {
+				continue
+			}
+
+			catchable := sort.Search(len(trips), func(i int) bool {
+				return !trips[i].Add(rt.offsets[position]).Before(previous)
+			})
+			if catchable < len(trips) && (trip < internal.ZERO || catchable < trip) {
+				trip, boardPos = catchable, position
+			}
+		}
+	}
+}
+
+// journey walks the labels back from the place the target was reached from.
+func (s *search) journey(k, index int, arrival time.Time, egress float64) internal.Journey {
+	n := s.network
+	legs := []internal.JourneyLeg{walkLeg(n.places[index].JourneyPlace, s.target, s.arrivals[k][index], arrival, egress)}
+
+	for {
+		l := s.labels[k][index]
+		if l == nil {
+			k--
+			continue
+		}
+
+		place := n.places[index].JourneyPlace
+		switch l.kind {
+		case ACCESS:
+			legs = append(legs, walkLeg(s.origin, place, l.departure, s.arrivals[k][index], l.distance))
+			return newJourney(reverseLegs(legs))
+
+		case TRANSFER:
+			legs = append(legs, walkLeg(n.places[l.from].JourneyPlace, place, s.arrivals[k][l.from], s.arrivals[k][index], l.distance))
+			index = l.from
+
+		case RIDE:
+			rt := n.routes[l.route]
+			distance := 0.0
+			for i := l.boardPos + 1; i <= l.alightPos; i++ {
+				from, to := n.places[rt.places[i-1]], n.places[rt.places[i]]
+				distance += utils.HaversineDistance(from.Latitude, from.Longitude, to.Latitude, to.Longitude)
+			}
+			legs = append(legs, internal.JourneyLeg{
+				Mode:      internal.BUS,
+				From:      n.places[l.from].JourneyPlace,
+				To:        place,
+				Departure: l.trip.Add(rt.offsets[l.boardPos]),
+				Arrival:   s.arrivals[k][index],
+				Distance:  distance,
+				LineID:    rt.lineID,
+				LineName:  rt.name,
+				Stops:     l.alightPos - l.boardPos,
+			})
+			index = l.from
+			k--
+
+		case METRO_RIDE:
+			legs = append(legs, internal.JourneyLeg{
+				Mode:      internal.METRO,
+				From:      n.places[l.from].JourneyPlace,
+				To:        place,
+				Departure: l.departure,
+				Arrival:   s.arrivals[k][index],
+				Distance:  l.distance,
+				Stops:     1,
+			})
+			index = l.from
+			k--
+		}
+	}
+}
+
+func (s *search) distanceTo(index int, p internal.JourneyPlace) float64 {
+	place := s.network.places[index]
+	return utils.HaversineDistance(place.Latitude, place.Longitude, p.Latitude, p.Longitude)
+}
+
+// newJourney drops empty walks and delays the first walk so the rider leaves
+// just in time for the first ride instead of waiting at the stop.
+func newJourney(legs []internal.JourneyLeg) internal.Journey {
+	kept := []internal.JourneyLeg{}
+	for _, leg := range legs {
+		if leg.Mode != internal.WALK || leg.Distance >= 1 || len(legs) == 1 {
+			kept = append(kept, leg)
+		}
+	}
+
+	if len(kept) > 1 && kept[0].Mode == internal.WALK {
+		duration := kept[0].Arrival.Sub(kept[0].Departure)
+		kept[0].Arrival = kept[1].Departure
+		kept[0].Departure = kept[1].Departure.Add(-duration)
+	}
+
+	journey := internal.Journey{
+		Departure: kept[0].Departure,
+		Arrival:   kept[len(kept)-1].Arrival,
+		Legs:      kept,
+	}
+	for _, leg := range kept {
+		if leg.Mode == internal.WALK {
+			journey.WalkDistance += leg.Distance
+		} else {
+			journey.Transfers++
+		}
+	}
+	if journey.Transfers > internal.ZERO {
+		journey.Transfers--
+	}
+
+	return journey
+}
+
+func walkLeg(from, to internal.JourneyPlace, departure, arrival time.Time, distance float64) internal.JourneyLeg {
+	return internal.JourneyLeg{Mode: internal.WALK, From: from, To: to, Departure: departure, Arrival: arrival, Distance: distance}
+}
+
+func reverseLegs(legs []internal.JourneyLeg) []internal.JourneyLeg {
+	for i, j := 0, len(legs)-1; i < j; i, j = i+1, j-1 {
+		legs[i], legs[j] = legs[j], legs[i]
+	}
+	return legs
+}
+
+func improves(arrival, current time.Time) bool {
+	return current.IsZero() || arrival.Before(current)
+}
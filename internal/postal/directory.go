@@ -0,0 +1,114 @@
+package postal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+// PostalCode is the area a CEP belongs to. Street and neighborhood are empty
+// for CEPs that cover a whole city.
+type PostalCode struct {
+	CEP          string
+	Street       string
+	Neighborhood string
+	City         string
+	State        string
+}
+
+type IPostalDirectory interface {
+	Lookup(ctx context.Context, cep string) (PostalCode, error)
+}
+
+type CSVDirectory struct {
+	codes map[string]PostalCode
+}
+
+// NewCSVDirectory reads a CSV with a header naming the columns cep, city and
+// state, and optionally street and neighborhood.
+func NewCSVDirectory(r io.Reader) (*CSVDirectory, error) {
+	table, err := utils.NewCSVTable(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if missing := table.Missing("cep", "city", "state"); missing != internal.EMPTY {
+		return nil, fmt.Errorf("%w: %s", ErrPostalColumnMissing, missing)
+	}
+
+	d := &CSVDirectory{codes: map[string]PostalCode{}}
+	for {
+		record, err := table.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		code := PostalCode{
+			CEP:          utils.NormalizeCEP(record.Field("cep")),
+			Street:       record.Field("street"),
+			Neighborhood: record.Field("neighborhood"),
+			City:         record.Field("city"),
+			State:        strings.ToUpper(record.Field("state")),
+		}
+		if len(code.CEP) != 8 {
+			return nil, fmt.Errorf("%w: line %d", ErrPostalCEPInvalid, record.Line)
+		}
+		if code.City == internal.EMPTY || utf8.RuneCountInString(code.State) != 2 {
+			return nil, fmt.Errorf("%w: line %d", ErrPostalCityInvalid, record.Line)
+		}
+
+		d.codes[code.CEP] = code
+	}
+
+	return d, nil
+}
+
+func LoadCSVDirectory(path string) (*CSVDirectory, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return NewCSVDirectory(file)
+}
+
+// FromEnv loads the dataset at POSTAL_CSV, returning no directory when the
+// variable is not set so CEPs are only checked for their format.
+func FromEnv() (IPostalDirectory, error) {
+	path := os.Getenv("POSTAL_CSV")
+	if path == internal.EMPTY {
+		return nil, nil
+	}
+
+	d, err := LoadCSVDirectory(path)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *CSVDirectory) Lookup(ctx context.Context, cep string) (PostalCode, error) {
+	code, ok := d.codes[utils.NormalizeCEP(cep)]
+	if !ok {
+		return PostalCode{}, ErrPostalCodeNotFound
+	}
+	return code, nil
+}
+
+var (
+	ErrPostalCodeNotFound  = errors.New("cep not found in the postal directory")
+	ErrPostalColumnMissing = errors.New("postal dataset column missing")
+	ErrPostalCEPInvalid    = errors.New("postal dataset cep must contain 8 digits")
+	ErrPostalCityInvalid   = errors.New("postal dataset city is empty or state does not contain 2 characters")
+)
@@ -0,0 +1,49 @@
+package postal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	directory, err := NewCSVDirectory(strings.NewReader(
+		"cep,street,neighborhood,city,state\n95520-000,,Centro,Osório,rs\n"))
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	code, err := directory.Lookup(context.Background(), "95520000")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if code.City != "Osório" || code.State != "RS" || code.Neighborhood != "Centro" {
+		t.Errorf("CEP de Osório esperado, recebido: %+v", code)
+	}
+
+	if _, err := directory.Lookup(context.Background(), "00000000"); !errors.Is(err, ErrPostalCodeNotFound) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrPostalCodeNotFound, err)
+	}
+}
+
+func TestNewCSVDirectoryInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		csv       string
+		wantError error
+	}{
+		{name: "Sem coluna de estado", csv: "cep,city\n95520000,Osório\n", wantError: ErrPostalColumnMissing},
+		{name: "CEP curto", csv: "cep,city,state\n9552,Osório,RS\n", wantError: ErrPostalCEPInvalid},
+		{name: "Estado inválido", csv: "cep,city,state\n95520000,Osório,RGS\n", wantError: ErrPostalCityInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCSVDirectory(strings.NewReader(tt.csv)); !errors.Is(err, tt.wantError) {
+				t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+		})
+	}
+}
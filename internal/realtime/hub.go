@@ -0,0 +1,69 @@
+package realtime
+
+import (
+	"sync"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+// SUBSCRIPTION_BUFFER is how many events a subscriber may fall behind before
+// new events are dropped for it, so a slow client never blocks a publisher.
+const SUBSCRIPTION_BUFFER = 64
+
+type IPublisher interface {
+	Publish(event internal.RealtimeEvent)
+}
+
+type Subscription struct {
+	Events <-chan internal.RealtimeEvent
+	box    internal.BoundingBox
+	events chan internal.RealtimeEvent
+}
+
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[*Subscription]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subscribers: map[*Subscription]struct{}{}}
+}
+
+// Subscribe receives the events whose bounds intersect box until the
+// subscription is passed to Unsubscribe.
+func (h *Hub) Subscribe(box internal.BoundingBox) *Subscription {
+	events := make(chan internal.RealtimeEvent, SUBSCRIPTION_BUFFER)
+	subscription := &Subscription{Events: events, box: box, events: events}
+
+	h.mu.Lock()
+	h.subscribers[subscription] = struct{}{}
+	h.mu.Unlock()
+
+	return subscription
+}
+
+func (h *Hub) Unsubscribe(subscription *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[subscription]; ok {
+		delete(h.subscribers, subscription)
+		close(subscription.events)
+	}
+}
+
+func (h *Hub) Publish(event internal.RealtimeEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for subscription := range h.subscribers {
+		if !subscription.box.Intersects(event.Bounds) {
+			continue
+		}
+
+		select {
+		case subscription.events <- event:
+		default:
+		}
+	}
+}
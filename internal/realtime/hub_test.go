@@ -0,0 +1,63 @@
+package realtime
+
+import (
+	"testing"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+func TestPublish(t *testing.T) {
+	hub := NewHub()
+	subscription := hub.Subscribe(internal.BoundingBox{
+		MinLatitude: -30, MinLongitude: -51, MaxLatitude: -29, MaxLongitude: -50,
+	})
+
+	tests := []struct {
+		name      string
+		bounds    internal.BoundingBox
+		delivered bool
+	}{
+		{name: "Evento dentro da área", bounds: internal.PointBox(-29.88, -50.27), delivered: true},
+		{name: "Evento fora da área", bounds: internal.PointBox(-23.55, -46.63), delivered: false},
+		{
+			name:      "Linha que cruza a área",
+			bounds:    internal.PointBox(-31, -50.5).Extend(-29.5, -50.5),
+			delivered: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub.Publish(internal.RealtimeEvent{Type: internal.OCCURRENCE_CREATED, Bounds: tt.bounds})
+
+			select {
+			case <-subscription.Events:
+				if !tt.delivered {
+					t.Errorf("[%s] Evento não deveria ser entregue", tt.name)
+				}
+			default:
+				if tt.delivered {
+					t.Errorf("[%s] Evento deveria ser entregue", tt.name)
+				}
+			}
+		})
+	}
+
+	hub.Unsubscribe(subscription)
+	if _, open := <-subscription.Events; open {
+		t.Errorf("Canal deveria ser fechado ao cancelar a inscrição")
+	}
+}
+
+func TestPublishSlowSubscriber(t *testing.T) {
+	hub := NewHub()
+	subscription := hub.Subscribe(internal.PointBox(-29.88, -50.27))
+
+	for i := 0; i < SUBSCRIPTION_BUFFER+10; i++ {
+		hub.Publish(internal.RealtimeEvent{Type: internal.BUS_POSITIONS, Bounds: internal.PointBox(-29.88, -50.27)})
+	}
+
+	if len(subscription.Events) != SUBSCRIPTION_BUFFER {
+		t.Errorf("Eventos esperados: %d, recebidos: %d", SUBSCRIPTION_BUFFER, len(subscription.Events))
+	}
+}
@@ -0,0 +1,45 @@
+package internal
+
+import "time"
+
+type RealtimeEventType string
+
+const (
+	SHARED_VEHICLE_INSERTED	RealtimeEventType = "shared-vehicle-inserted"
+	SHARED_VEHICLE_MOVED	RealtimeEventType = "shared-vehicle-moved"
+	OCCURRENCE_CREATED		RealtimeEventType = "occurrence-created"
+	BUS_POSITIONS			RealtimeEventType = "bus-positions"
+)
+
+type RealtimeEvent struct {
+	Type	RealtimeEventType
+	Bounds	BoundingBox
+	Data	interface{}
+}
+
+type LineBusPositions struct {
+	LineID		int64
+	Positions	[]BusPosition
+}
+
+// SharedVehicleEvent and OccurrenceEvent are what the realtime stream shows of
+// a shared vehicle or an occurrence. The stream is public, so they never carry
+// the user who reported it.
+type SharedVehicleEvent struct {
+	ID			int64
+	VehicleType	VehicleType
+	Latitude	float64
+	Longitude	float64
+	ReportedAt	time.Time
+}
+
+type OccurrenceEvent struct {
+	ID				int64
+	Type			OccurrenceType
+	Description		string
+	Latitude		float64
+	Longitude		float64
+	TimeStamp		time.Time
+	Confirmation	int64
+	Disputes		int64
+}
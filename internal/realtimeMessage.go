@@ -0,0 +1,23 @@
+package internal
+
+const (
+	SUBSCRIBE				= "subscribe"
+	REPORT_LOCATION			= "report-location"
+	UPDATE_SHARED_VEHICLE	= "update-shared-vehicle"
+	ACK						= "ack"
+	FAILURE					= "error"
+)
+
+// RealtimeMessage is exchanged over the realtime WebSocket. Requests carry an
+// ID chosen by the client that is echoed in the matching ACK or FAILURE;
+// events pushed by the server use the RealtimeEventType as Type.
+type RealtimeMessage struct {
+	ID				int64
+	Type			string
+	Bounds			*BoundingBox	`json:",omitempty"`
+	Location		*UserLocation	`json:",omitempty"`
+	SharedVehicle	*SharedVehicle	`json:",omitempty"`
+	Response		interface{}		`json:",omitempty"`
+	Data			interface{}		`json:",omitempty"`
+	Error			string			`json:",omitempty"`
+}
@@ -0,0 +1,14 @@
+package internal
+
+import "time"
+
+type SavedPlace struct {
+	ID			int64
+	UserID		int64
+	Name		string
+	Latitude	float64
+	Longitude	float64
+	CreatedAt	time.Time
+	UpdatedAt	*time.Time
+	DeletedAt	*time.Time
+}
@@ -0,0 +1,21 @@
+package internal
+
+import "time"
+
+type Session struct {
+	ID					int64
+	UserID				int64
+	Email				string
+	RefreshTokenHash	string
+	PreviousTokenHash	*string
+	ExpiresAt			time.Time
+	RevokedAt			*time.Time
+	CreatedAt			time.Time
+	UpdatedAt			*time.Time
+}
+
+type AuthTokens struct {
+	AccessToken		string
+	RefreshToken	string
+	ExpiresAt		time.Time
+}
@@ -13,3 +13,8 @@ type SharedVehicle struct {
 	UpdatedAt   *time.Time
 	DeletedAt   *time.Time
 }
+
+type NearbySharedVehicle struct {
+	SharedVehicle
+	Distance float64
+}
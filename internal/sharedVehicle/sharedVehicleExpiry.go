@@ -0,0 +1,26 @@
+package sharedVehicle
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+// StartExpiryJob soft deletes, every interval, the vehicles that were not
+// reported again within retention.
+func StartExpiryJob(ctx context.Context, service ISharedVehicleService, retention, interval time.Duration) {
+	utils.RunPeriodically(ctx, interval, func(ctx context.Context) error {
+		expired, err := service.ExpireStaleSharedVehicles(ctx, retention)
+		if err != nil {
+			return fmt.Errorf("could not expire stale shared vehicles, error: %w", err)
+		}
+
+		if expired > 0 {
+			log.Printf("%d stale shared vehicles expired\n", expired)
+		}
+		return nil
+	})
+}
@@ -5,15 +5,18 @@ import (
 	"time"
 
 	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/utils"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type ISharedVehicleRepository interface {
 	InsertSharedVehicle(ctx context.Context, vehicle internal.SharedVehicle) (int64, error)
-	ListAllSharedVehicles(ctx context.Context) ([]internal.SharedVehicle, error)
+	ListAllSharedVehicles(ctx context.Context, reportedAfter time.Time) ([]internal.SharedVehicle, error)
 	GetSharedVehicle(ctx context.Context, vehicleID int64) (internal.SharedVehicle, error)
 	UpdateSharedVehicleLocation(ctx context.Context, vehicle internal.SharedVehicle) (bool, error)
+	ListNearbySharedVehicles(ctx context.Context, latitude, longitude, radius float64, vehicleType *internal.VehicleType, reportedAfter time.Time, limit int) ([]internal.NearbySharedVehicle, error)
+	ExpireSharedVehicles(ctx context.Context, reportedBefore time.Time) (int64, error)
 }
 
 type sharedVehicleRepository struct {
@@ -28,7 +31,7 @@ func (r *sharedVehicleRepository) InsertSharedVehicle(ctx context.Context, vehic
 	if err :=
 		r.Conn.QueryRow(
 			ctx,
-			`INSERT INTO shared_vehicle (user_id, latitude, longitude, vehicle_type, reported_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`, vehicle.UserID, vehicle.Latitude, vehicle.Longitude, vehicle.VehicleType, time.Now()).Scan(&vehicle.ID); err != nil {
+			`INSERT INTO shared_vehicle (user_id, latitude, longitude, vehicle_type, reported_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`, vehicle.UserID, vehicle.Latitude, vehicle.Longitude, vehicle.VehicleType, vehicle.ReportedAt).Scan(&vehicle.ID); err != nil {
 		return internal.ZERO, err
 	}
 	return vehicle.ID, nil
@@ -51,7 +54,7 @@ func (r *sharedVehicleRepository) GetSharedVehicle(ctx context.Context, vehicleI
 	return vehicle, nil
 }
 
-func (r *sharedVehicleRepository) ListAllSharedVehicles(ctx context.Context) ([]internal.SharedVehicle, error) {
+func (r *sharedVehicleRepository) ListAllSharedVehicles(ctx context.Context, reportedAfter time.Time) ([]internal.SharedVehicle, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 	sharedVehicleChannel := make(chan []internal.SharedVehicle)
@@ -62,7 +65,7 @@ func (r *sharedVehicleRepository) ListAllSharedVehicles(ctx context.Context) ([]
 			r.Conn.Query(
 				ctx,
 				`SELECT id, latitude, longitude, vehicle_type, reported_at
-				FROM shared_vehicle WHERE deleted_at IS NULL;`)
+				FROM shared_vehicle WHERE reported_at >= $1 AND deleted_at IS NULL;`, reportedAfter)
 		if err != nil {
 			errorChannel <- err
 			return
@@ -101,7 +104,7 @@ func (r *sharedVehicleRepository) UpdateSharedVehicleLocation(ctx context.Contex
 		r.Conn.Exec(
 			ctx,
 			`UPDATE shared_vehicle SET user_id = $2, latitude = $3, longitude = $4, reported_at = $5, updated_at = $6
-			WHERE id = $1 AND deleted_at IS NULL;`, vehicle.ID, vehicle.UserID, vehicle.Latitude, vehicle.Longitude, time.Now(), time.Now())
+			WHERE id = $1 AND deleted_at IS NULL;`, vehicle.ID, vehicle.UserID, vehicle.Latitude, vehicle.Longitude, vehicle.ReportedAt, time.Now())
 
 	if err != nil {
 		return false, err
@@ -113,3 +116,69 @@ func (r *sharedVehicleRepository) UpdateSharedVehicleLocation(ctx context.Contex
 		return true, nil
 	}
 }
+
+func (r *sharedVehicleRepository) ListNearbySharedVehicles(ctx context.Context, latitude, longitude, radius float64, vehicleType *internal.VehicleType, reportedAfter time.Time, limit int) ([]internal.NearbySharedVehicle, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	sharedVehicleChannel := make(chan []internal.NearbySharedVehicle)
+	errorChannel := make(chan error)
+
+	minLat, minLon, maxLat, maxLon := utils.BoundingBox(latitude, longitude, radius)
+
+	go func() {
+		rows, err :=
+			r.Conn.Query(
+				ctx,
+				`SELECT id, latitude, longitude, vehicle_type, reported_at, distance FROM (
+					SELECT id, latitude, longitude, vehicle_type, reported_at, `+utils.DISTANCE_SQL+` AS distance
+					FROM shared_vehicle WHERE latitude BETWEEN $3 AND $4 AND longitude BETWEEN $5 AND $6
+						AND ($7::INTEGER IS NULL OR vehicle_type = $7) AND reported_at >= $8
+						AND deleted_at IS NULL) AS nearby
+				WHERE distance <= $9 ORDER BY distance LIMIT $10;`, latitude, longitude,
+				minLat, maxLat, minLon, maxLon, vehicleType, reportedAfter, radius, limit)
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+
+		defer rows.Close()
+		vehicles := []internal.NearbySharedVehicle{}
+		for rows.Next() {
+			v := internal.NearbySharedVehicle{}
+			if err := rows.Scan(
+				&v.ID,
+				&v.Latitude,
+				&v.Longitude,
+				&v.VehicleType,
+				&v.ReportedAt,
+				&v.Distance); err != nil {
+				errorChannel <- err
+				return
+			}
+			vehicles = append(vehicles, v)
+		}
+		sharedVehicleChannel <- vehicles
+	}()
+
+	select {
+	case vehicles := <-sharedVehicleChannel:
+		return vehicles, nil
+	case err := <-errorChannel:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *sharedVehicleRepository) ExpireSharedVehicles(ctx context.Context, reportedBefore time.Time) (int64, error) {
+	result, err :=
+		r.Conn.Exec(
+			ctx,
+			`UPDATE shared_vehicle SET deleted_at = $2 WHERE reported_at < $1 AND deleted_at IS NULL;`,
+			reportedBefore, time.Now())
+	if err != nil {
+		return internal.ZERO, err
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -3,8 +3,17 @@ package sharedVehicle
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/amarantec/move-easy/internal"
+	"github.com/amarantec/move-easy/internal/realtime"
+	"github.com/amarantec/move-easy/internal/utils"
+)
+
+const (
+	DEFAULT_NEARBY_RADIUS = 500.0
+	MAX_NEARBY_RADIUS     = 10000.0
+	NEARBY_LIMIT          = 50
 )
 
 type ISharedVehicleService interface {
@@ -12,25 +21,41 @@ type ISharedVehicleService interface {
 	ListAllSharedVehicles(ctx context.Context) ([]internal.SharedVehicle, error)
 	GetSharedVehicle(ctx context.Context, vehicleID int64) (internal.SharedVehicle, error)
 	UpdateSharedVehicleLocation(ctx context.Context, vehicle internal.SharedVehicle) (bool, error)
+	ListNearbySharedVehicles(ctx context.Context, latitude, longitude, radius float64, vehicleType *internal.VehicleType) ([]internal.NearbySharedVehicle, error)
+	ExpireStaleSharedVehicles(ctx context.Context, retention time.Duration) (int64, error)
 }
 
 type sharedVehicleService struct {
 	repository ISharedVehicleRepository
+	freshness  time.Duration
+	publisher  realtime.IPublisher
 }
 
-func NewSharedVehicleService(repo ISharedVehicleRepository) ISharedVehicleService {
-	return &sharedVehicleService{repository: repo}
+// NewSharedVehicleService hides from listings the vehicles that were not
+// reported within freshness. A zero freshness lists every vehicle. Inserts
+// and moves are sent to publisher when it is not nil.
+func NewSharedVehicleService(repo ISharedVehicleRepository, freshness time.Duration, publisher realtime.IPublisher) ISharedVehicleService {
+	return &sharedVehicleService{repository: repo, freshness: freshness, publisher: publisher}
 }
 
 func (s *sharedVehicleService) InsertSharedVehicle(ctx context.Context, vehicle internal.SharedVehicle) (int64, error) {
 	if valid, err := validateSharedVehicle(vehicle); err != nil || !valid {
 		return internal.ZERO, err
 	}
-	return s.repository.InsertSharedVehicle(ctx, vehicle)
+
+	vehicle.ReportedAt = time.Now()
+	id, err := s.repository.InsertSharedVehicle(ctx, vehicle)
+	if err != nil {
+		return internal.ZERO, err
+	}
+
+	vehicle.ID = id
+	s.publish(internal.SHARED_VEHICLE_INSERTED, vehicle)
+	return id, nil
 }
 
 func (s *sharedVehicleService) ListAllSharedVehicles(ctx context.Context) ([]internal.SharedVehicle, error) {
-	return s.repository.ListAllSharedVehicles(ctx)
+	return s.repository.ListAllSharedVehicles(ctx, s.reportedAfter())
 }
 
 func (s *sharedVehicleService) GetSharedVehicle(ctx context.Context, vehicleID int64) (internal.SharedVehicle, error) {
@@ -41,7 +66,67 @@ func (s *sharedVehicleService) UpdateSharedVehicleLocation(ctx context.Context,
 	if valid, err := validateSharedVehicle(vehicle); err != nil || !valid {
 		return false, err
 	}
-	return s.repository.UpdateSharedVehicleLocation(ctx, vehicle)
+
+	vehicle.ReportedAt = time.Now()
+	updated, err := s.repository.UpdateSharedVehicleLocation(ctx, vehicle)
+	if err != nil {
+		return false, err
+	}
+
+	if updated {
+		s.publish(internal.SHARED_VEHICLE_MOVED, vehicle)
+	}
+	return updated, nil
+}
+
+func (s *sharedVehicleService) ListNearbySharedVehicles(ctx context.Context, latitude, longitude, radius float64, vehicleType *internal.VehicleType) ([]internal.NearbySharedVehicle, error) {
+	if !utils.ValidCoordinates(latitude, longitude) {
+		return []internal.NearbySharedVehicle{}, ErrSVLocationInvalid
+	}
+
+	if vehicleType != nil && !vehicleType.IsValid() {
+		return []internal.NearbySharedVehicle{}, ErrSVTypeInvalid
+	}
+
+	if radius <= internal.ZERO {
+		radius = DEFAULT_NEARBY_RADIUS
+	} else if radius > MAX_NEARBY_RADIUS {
+		return []internal.NearbySharedVehicle{}, ErrSVRadiusInvalid
+	}
+
+	return s.repository.ListNearbySharedVehicles(ctx, latitude, longitude, radius, vehicleType, s.reportedAfter(), NEARBY_LIMIT)
+}
+
+func (s *sharedVehicleService) ExpireStaleSharedVehicles(ctx context.Context, retention time.Duration) (int64, error) {
+	if retention <= internal.ZERO {
+		return internal.ZERO, ErrSVRetentionInvalid
+	}
+	return s.repository.ExpireSharedVehicles(ctx, time.Now().Add(-retention))
+}
+
+func (s *sharedVehicleService) reportedAfter() time.Time {
+	if s.freshness <= internal.ZERO {
+		return time.Time{}
+	}
+	return time.Now().Add(-s.freshness)
+}
+
+func (s *sharedVehicleService) publish(eventType internal.RealtimeEventType, vehicle internal.SharedVehicle) {
+	if s.publisher == nil {
+		return
+	}
+
+	s.publisher.Publish(internal.RealtimeEvent{
+		Type:   eventType,
+		Bounds: internal.PointBox(vehicle.Latitude, vehicle.Longitude),
+		Data: internal.SharedVehicleEvent{
+			ID:          vehicle.ID,
+			VehicleType: vehicle.VehicleType,
+			Latitude:    vehicle.Latitude,
+			Longitude:   vehicle.Longitude,
+			ReportedAt:  vehicle.ReportedAt,
+		},
+	})
 }
 
 func validateSharedVehicle(sv internal.SharedVehicle) (bool, error) {
@@ -58,4 +143,9 @@ func validateSharedVehicle(sv internal.SharedVehicle) (bool, error) {
 var (
 	ErrSVUserIDEmpty = errors.New("Erro shared vehicle user id empty")
 	ErrSVTypeEmpty   = errors.New("Error shared vahicle type empty")
+
+	ErrSVTypeInvalid      = errors.New("shared vehicle type is invalid")
+	ErrSVLocationInvalid  = errors.New("shared vehicle latitude or longitude out of range")
+	ErrSVRadiusInvalid    = errors.New("shared vehicle search radius must be at most 10000 meters")
+	ErrSVRetentionInvalid = errors.New("shared vehicle retention must be positive")
 )
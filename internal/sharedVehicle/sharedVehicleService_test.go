@@ -0,0 +1,191 @@
+package sharedVehicle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+type mockSharedVehicleRepository struct {
+	ISharedVehicleRepository
+	InsertSharedVehicleFunc      func(ctx context.Context, vehicle internal.SharedVehicle) (int64, error)
+	ListAllSharedVehiclesFunc    func(ctx context.Context, reportedAfter time.Time) ([]internal.SharedVehicle, error)
+	ExpireSharedVehiclesFunc     func(ctx context.Context, reportedBefore time.Time) (int64, error)
+	ListNearbySharedVehiclesFunc func(ctx context.Context, latitude, longitude, radius float64, vehicleType *internal.VehicleType, reportedAfter time.Time, limit int) ([]internal.NearbySharedVehicle, error)
+}
+
+func (m *mockSharedVehicleRepository) InsertSharedVehicle(ctx context.Context, vehicle internal.SharedVehicle) (int64, error) {
+	if m.InsertSharedVehicleFunc != nil {
+		return m.InsertSharedVehicleFunc(ctx, vehicle)
+	}
+	return internal.ZERO, ErrInsertSharedVehicleFuncNotImplemented
+}
+
+func (m *mockSharedVehicleRepository) ListNearbySharedVehicles(ctx context.Context, latitude, longitude, radius float64, vehicleType *internal.VehicleType, reportedAfter time.Time, limit int) ([]internal.NearbySharedVehicle, error) {
+	if m.ListNearbySharedVehiclesFunc != nil {
+		return m.ListNearbySharedVehiclesFunc(ctx, latitude, longitude, radius, vehicleType, reportedAfter, limit)
+	}
+	return nil, ErrListNearbySharedVehiclesFuncNotImplemented
+}
+
+func (m *mockSharedVehicleRepository) ListAllSharedVehicles(ctx context.Context, reportedAfter time.Time) ([]internal.SharedVehicle, error) {
+	if m.ListAllSharedVehiclesFunc != nil {
+		return m.ListAllSharedVehiclesFunc(ctx, reportedAfter)
+	}
+	return nil, ErrListAllSharedVehiclesFuncNotImplemented
+}
+
+func (m *mockSharedVehicleRepository) ExpireSharedVehicles(ctx context.Context, reportedBefore time.Time) (int64, error) {
+	if m.ExpireSharedVehiclesFunc != nil {
+		return m.ExpireSharedVehiclesFunc(ctx, reportedBefore)
+	}
+	return internal.ZERO, ErrExpireSharedVehiclesFuncNotImplemented
+}
+
+func TestListNearbySharedVehicles(t *testing.T) {
+	scooter := internal.SCOOTER
+	invalidType := internal.VehicleType(9)
+
+	tests := []struct {
+		name        string
+		latitude    float64
+		longitude   float64
+		radius      float64
+		vehicleType *internal.VehicleType
+		wantRadius  float64
+		wantError   error
+	}{
+		{name: "Raio padrão", latitude: -29.88, longitude: -50.27, wantRadius: DEFAULT_NEARBY_RADIUS},
+		{name: "Raio e tipo informados", latitude: -29.88, longitude: -50.27, radius: 200, vehicleType: &scooter, wantRadius: 200},
+		{name: "Raio acima do máximo", latitude: -29.88, longitude: -50.27, radius: MAX_NEARBY_RADIUS + 1, wantError: ErrSVRadiusInvalid},
+		{name: "Tipo inválido", latitude: -29.88, longitude: -50.27, vehicleType: &invalidType, wantError: ErrSVTypeInvalid},
+		{name: "Localização inválida", latitude: 95, longitude: -50.27, wantError: ErrSVLocationInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockSharedVehicleRepository{
+				ListNearbySharedVehiclesFunc: func(ctx context.Context, latitude, longitude, radius float64, vehicleType *internal.VehicleType, reportedAfter time.Time, limit int) ([]internal.NearbySharedVehicle, error) {
+					if radius != tt.wantRadius {
+						t.Errorf("[%s] Raio esperado: %v, recebido: %v", tt.name, tt.wantRadius, radius)
+					}
+					if vehicleType != tt.vehicleType {
+						t.Errorf("[%s] Tipo esperado: %v, recebido: %v", tt.name, tt.vehicleType, vehicleType)
+					}
+					if limit != NEARBY_LIMIT {
+						t.Errorf("[%s] Limite esperado: %d, recebido: %d", tt.name, NEARBY_LIMIT, limit)
+					}
+					return []internal.NearbySharedVehicle{}, nil
+				},
+			}
+			service := NewSharedVehicleService(mockRepo, internal.ZERO, nil)
+
+			_, err := service.ListNearbySharedVehicles(context.Background(), tt.latitude, tt.longitude, tt.radius, tt.vehicleType)
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+		})
+	}
+}
+
+func TestListAllSharedVehiclesFreshness(t *testing.T) {
+	tests := []struct {
+		name      string
+		freshness time.Duration
+		wantAge   time.Duration
+	}{
+		{name: "Janela de validade aplicada", freshness: 30 * time.Minute, wantAge: 30 * time.Minute},
+		{name: "Sem janela de validade", freshness: internal.ZERO},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockSharedVehicleRepository{
+				ListAllSharedVehiclesFunc: func(ctx context.Context, reportedAfter time.Time) ([]internal.SharedVehicle, error) {
+					if tt.wantAge == internal.ZERO {
+						if !reportedAfter.IsZero() {
+							t.Errorf("[%s] Nenhum filtro esperado, recebido: %v", tt.name, reportedAfter)
+						}
+					} else if age := time.Since(reportedAfter); age < tt.wantAge || age > tt.wantAge+time.Minute {
+						t.Errorf("[%s] Idade esperada: %v, recebida: %v", tt.name, tt.wantAge, age)
+					}
+					return []internal.SharedVehicle{}, nil
+				},
+			}
+			service := NewSharedVehicleService(mockRepo, tt.freshness, nil)
+
+			if _, err := service.ListAllSharedVehicles(context.Background()); err != nil {
+				t.Errorf("[%s] Erro inesperado: %v", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestExpireStaleSharedVehicles(t *testing.T) {
+	mockRepo := &mockSharedVehicleRepository{
+		ExpireSharedVehiclesFunc: func(ctx context.Context, reportedBefore time.Time) (int64, error) {
+			if age := time.Since(reportedBefore); age < 24*time.Hour || age > 24*time.Hour+time.Minute {
+				t.Errorf("Idade esperada: 24h, recebida: %v", age)
+			}
+			return 3, nil
+		},
+	}
+	service := NewSharedVehicleService(mockRepo, 30*time.Minute, nil)
+
+	expired, err := service.ExpireStaleSharedVehicles(context.Background(), 24*time.Hour)
+	if err != nil || expired != 3 {
+		t.Errorf("Esperado: 3 veículos expirados, recebido: %d (%v)", expired, err)
+	}
+
+	if _, err := service.ExpireStaleSharedVehicles(context.Background(), internal.ZERO); !errors.Is(err, ErrSVRetentionInvalid) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrSVRetentionInvalid, err)
+	}
+}
+
+type recordingPublisher struct {
+	events []internal.RealtimeEvent
+}
+
+func (p *recordingPublisher) Publish(event internal.RealtimeEvent) {
+	p.events = append(p.events, event)
+}
+
+func TestInsertSharedVehiclePublishesWithoutUser(t *testing.T) {
+	mockRepo := &mockSharedVehicleRepository{
+		InsertSharedVehicleFunc: func(ctx context.Context, vehicle internal.SharedVehicle) (int64, error) {
+			return 3, nil
+		},
+	}
+	publisher := &recordingPublisher{}
+	service := NewSharedVehicleService(mockRepo, internal.ZERO, publisher)
+
+	// O horário enviado pelo cliente é ignorado.
+	sent := time.Now().Add(-48 * time.Hour)
+	if _, err := service.InsertSharedVehicle(context.Background(),
+		internal.SharedVehicle{UserID: 1, VehicleType: internal.SCOOTER, Latitude: -29.88, Longitude: -50.27, ReportedAt: sent}); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if len(publisher.events) != 1 {
+		t.Fatalf("Esperava 1 evento, recebeu %d", len(publisher.events))
+	}
+
+	vehicle, ok := publisher.events[0].Data.(internal.SharedVehicleEvent)
+	if !ok || vehicle.ID != 3 || vehicle.VehicleType != internal.SCOOTER {
+		t.Errorf("Evento de veículo sem usuário esperado, recebido: %#v", publisher.events[0].Data)
+	}
+
+	if time.Since(vehicle.ReportedAt) > time.Minute {
+		t.Errorf("Horário do relato deveria ser o do servidor, recebido: %v", vehicle.ReportedAt)
+	}
+}
+
+var (
+	ErrInsertSharedVehicleFuncNotImplemented      = errors.New("InsertSharedVehicleFunc not implemented")
+	ErrListAllSharedVehiclesFuncNotImplemented    = errors.New("ListAllSharedVehiclesFunc not implemented")
+	ErrExpireSharedVehiclesFuncNotImplemented     = errors.New("ExpireSharedVehiclesFunc not implemented")
+	ErrListNearbySharedVehiclesFuncNotImplemented = errors.New("ListNearbySharedVehiclesFunc not implemented")
+)
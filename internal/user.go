@@ -9,7 +9,7 @@ type User struct {
 	Email		string
 	Password	string
 	Contacts	[]Contact
-	Address		Address
+	Addresses	[]Address
     CreatedAt   time.Time
     UpdatedAt   *time.Time
     DeletedAt   *time.Time
@@ -0,0 +1,189 @@
+package user
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "net/url"
+    "strings"
+    "sync"
+    "time"
+    "github.com/amarantec/move-easy/internal"
+    "github.com/amarantec/move-easy/internal/mailer"
+    "github.com/amarantec/move-easy/internal/utils"
+)
+
+const (
+    RESET_MAIL_TIMEOUT = 30 * time.Second
+    // RESET_MAIL_WORKERS bounds how many reset mails are sent at once.
+    RESET_MAIL_WORKERS = 8
+    // Each e-mail may ask for RESET_EMAIL_LIMIT resets and each client IP for
+    // RESET_IP_LIMIT within RESET_LIMIT_WINDOW. No new token is issued while
+    // one younger than RESET_REISSUE_AFTER is unused.
+    RESET_EMAIL_LIMIT = 3
+    RESET_IP_LIMIT = 10
+    RESET_LIMIT_WINDOW = time.Hour
+    RESET_REISSUE_AFTER = 15 * time.Minute
+)
+
+type IPasswordResetService interface {
+    ForgotPassword(ctx context.Context, email, clientIP string) error
+    ResetPassword(ctx context.Context, reset internal.PasswordReset) (bool, error)
+    Wait()
+}
+
+type passwordResetService struct {
+    userRepository  IUserRepository
+    mailer          mailer.IMailer
+    ttl             time.Duration
+    resetURL        string
+    emailLimiter    *utils.RateLimiter
+    ipLimiter       *utils.RateLimiter
+    workers         chan struct{}
+    pending         sync.WaitGroup
+}
+
+// NewPasswordResetService mails reset tokens valid for ttl. When resetURL is
+// not empty the mail links to it with the token in the token query parameter.
+func NewPasswordResetService(repository IUserRepository, m mailer.IMailer, ttl time.Duration, resetURL string) IPasswordResetService {
+    return &passwordResetService{
+        userRepository: repository,
+        mailer: m,
+        ttl: ttl,
+        resetURL: resetURL,
+        emailLimiter: utils.NewRateLimiter(RESET_EMAIL_LIMIT, RESET_LIMIT_WINDOW),
+        ipLimiter: utils.NewRateLimiter(RESET_IP_LIMIT, RESET_LIMIT_WINDOW),
+        workers: make(chan struct{}, RESET_MAIL_WORKERS),
+    }
+}
+
+// ForgotPassword mails a reset token when the e-mail belongs to a user. The
+// lookup and the mail run apart from the request, so it answers in the same
+// time whether the e-mail is registered or not and cannot be used to find
+// out who is. Requests over the limits of the e-mail or of the client IP
+// fail with ErrResetThrottled, registered or not.
+func (s *passwordResetService) ForgotPassword(ctx context.Context, email, clientIP string) error {
+    if s.mailer == nil {
+        return ErrMailerMissing
+    }
+
+    email = strings.TrimSpace(email)
+    if email == internal.EMPTY {
+        return ErrResetEmailEmpty
+    }
+
+    if !s.ipLimiter.Allow(clientIP) || !s.emailLimiter.Allow(strings.ToLower(email)) {
+        return ErrResetThrottled
+    }
+
+    select {
+    case s.workers <- struct{}{}:
+    default:
+        return ErrResetThrottled
+    }
+
+    s.pending.Add(1)
+    go s.sendResetMail(email)
+    return nil
+}
+
+// Wait blocks until the reset mails already accepted by ForgotPassword are
+// sent, so a shutdown does not drop them.
+func (s *passwordResetService) Wait() {
+    s.pending.Wait()
+}
+
+func (s *passwordResetService) sendResetMail(email string) {
+    defer s.pending.Done()
+    defer func() { <-s.workers }()
+
+    ctx, cancel := context.WithTimeout(context.Background(), RESET_MAIL_TIMEOUT)
+    defer cancel()
+
+    userID, err := s.userRepository.GetUserIDByEmail(ctx, email)
+    if err != nil {
+        log.Printf("could not look up the user of a password reset, error: %v", err)
+        return
+    }
+
+    if userID == internal.ZERO {
+        return
+    }
+
+    token, tokenHash, err := utils.GenerateOpaqueToken()
+    if err != nil {
+        log.Printf("could not generate the password reset token of user %d, error: %v", userID, err)
+        return
+    }
+
+    tokenID, err := s.userRepository.CreatePasswordResetToken(ctx, internal.PasswordResetToken{
+        UserID: userID,
+        TokenHash: tokenHash,
+    }, s.ttl, RESET_REISSUE_AFTER)
+    if err != nil {
+        log.Printf("could not save the password reset token of user %d, error: %v", userID, err)
+        return
+    }
+
+    if tokenID == internal.ZERO {
+        return
+    }
+
+    if err := s.mailer.Send(ctx, s.resetMail(email, token)); err != nil {
+        log.Printf("could not send the password reset mail to user %d, error: %v", userID, err)
+    }
+}
+
+// ResetPassword sets the new password if the token is valid, and revokes
+// every session of the user.
+func (s *passwordResetService) ResetPassword(ctx context.Context, reset internal.PasswordReset) (bool, error) {
+    if reset.Token == internal.EMPTY {
+        return false, ErrResetTokenInvalid
+    }
+
+    if err := validatePassword(string(reset.NewPassword)); err != nil {
+        return false, err
+    }
+
+    hashedPassword, err := utils.HashPassword(string(reset.NewPassword))
+    if err != nil {
+        return false, err
+    }
+
+    userID, err := s.userRepository.ResetPassword(ctx, utils.HashToken(reset.Token), hashedPassword)
+    if err != nil {
+        return false, err
+    }
+
+    if userID == internal.ZERO {
+        return false, ErrResetTokenInvalid
+    }
+
+    if _, err := s.userRepository.RevokeUserSessions(ctx, userID, internal.ZERO); err != nil {
+        return false, err
+    }
+
+    return true, nil
+}
+
+func (s *passwordResetService) resetMail(email, token string) mailer.Mail {
+    body := fmt.Sprintf("Someone asked to reset the password of your Move Easy account.\n\n"+
+        "Use this code within %d minutes to choose a new password:\n\n%s\n", int(s.ttl.Minutes()), token)
+    if link, err := url.Parse(s.resetURL); s.resetURL != internal.EMPTY && err == nil {
+        query := link.Query()
+        query.Set("token", token)
+        link.RawQuery = query.Encode()
+        body += "\nOr open: " + link.String() + "\n"
+    }
+    body += "\nIf it was not you, ignore this mail. Your password stays the same.\n"
+
+    return mailer.Mail{To: email, Subject: "Reset your Move Easy password", Body: body}
+}
+
+var (
+    ErrMailerMissing = errors.New("no mailer is configured")
+    ErrResetEmailEmpty = errors.New("email is required")
+    ErrResetTokenInvalid = errors.New("password reset token is invalid, expired or already used")
+    ErrResetThrottled = errors.New("too many password reset requests, try again later")
+)
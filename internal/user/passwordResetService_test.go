@@ -0,0 +1,146 @@
+package user
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "regexp"
+    "strings"
+    "testing"
+    "time"
+    "github.com/amarantec/move-easy/internal"
+    "github.com/amarantec/move-easy/internal/mailer"
+    "github.com/amarantec/move-easy/internal/utils"
+)
+
+var resetCode = regexp.MustCompile(`(?m)^([A-Za-z0-9_-]{43})$`)
+
+func TestForgotPassword(t *testing.T) {
+    mockRepo := &mockUserRepository{}
+    m := mailer.NewMemoryMailer()
+    service := NewPasswordResetService(mockRepo, m, time.Hour, "https://move-easy.example.com/reset")
+    ctx := context.Background()
+
+    if err := service.ForgotPassword(ctx, "unknown@example.com", "10.0.0.1"); err != nil {
+        t.Errorf("Erro inesperado para e-mail desconhecido: %v", err)
+    }
+    service.Wait()
+
+    if len(m.Sent()) != internal.ZERO {
+        t.Errorf("Nenhum e-mail esperado para usuário desconhecido, recebidos: %d", len(m.Sent()))
+    }
+
+    if err := service.ForgotPassword(ctx, " valid@example.com ", "10.0.0.1"); err != nil {
+        t.Fatalf("Erro inesperado: %v", err)
+    }
+    service.Wait()
+
+    sent := m.Sent()
+    if len(sent) != 1 || sent[0].To != "valid@example.com" {
+        t.Fatalf("Um e-mail para valid@example.com esperado, recebido: %+v", sent)
+    }
+
+    code := resetCode.FindString(sent[0].Body)
+    if code == internal.EMPTY || !strings.Contains(sent[0].Body, "https://move-easy.example.com/reset?token="+code) {
+        t.Errorf("Código e link de redefinição esperados, recebido: %s", sent[0].Body)
+    }
+
+    if mockRepo.resetTokens[0].TokenHash != utils.HashToken(code) {
+        t.Errorf("Somente o hash do código deve ser guardado")
+    }
+
+    if err := service.ForgotPassword(ctx, "", "10.0.0.1"); !errors.Is(err, ErrResetEmailEmpty) {
+        t.Errorf("Erro esperado: %v, recebido: %v", ErrResetEmailEmpty, err)
+    }
+
+    if err := NewPasswordResetService(mockRepo, nil, time.Hour, "").ForgotPassword(ctx, "valid@example.com", "10.0.0.1"); !errors.Is(err, ErrMailerMissing) {
+        t.Errorf("Erro esperado: %v, recebido: %v", ErrMailerMissing, err)
+    }
+}
+
+func TestForgotPasswordThrottled(t *testing.T) {
+    mockRepo := &mockUserRepository{}
+    m := mailer.NewMemoryMailer()
+    service := NewPasswordResetService(mockRepo, m, time.Hour, "")
+    ctx := context.Background()
+
+    for i := 0; i < RESET_EMAIL_LIMIT; i++ {
+        if err := service.ForgotPassword(ctx, "valid@example.com", "10.0.0.1"); err != nil {
+            t.Fatalf("Erro inesperado: %v", err)
+        }
+        service.Wait()
+    }
+
+    // O primeiro código ainda não foi usado, então não é substituído.
+    if len(m.Sent()) != 1 || len(mockRepo.resetTokens) != 1 {
+        t.Errorf("Um e-mail e um código esperados, recebidos: %d e %d", len(m.Sent()), len(mockRepo.resetTokens))
+    }
+
+    if err := service.ForgotPassword(ctx, "VALID@example.com", "10.0.0.2"); !errors.Is(err, ErrResetThrottled) {
+        t.Errorf("Erro esperado por e-mail: %v, recebido: %v", ErrResetThrottled, err)
+    }
+
+    for i := 0; i < RESET_IP_LIMIT-RESET_EMAIL_LIMIT; i++ {
+        if err := service.ForgotPassword(ctx, fmt.Sprintf("unknown%d@example.com", i), "10.0.0.1"); err != nil {
+            t.Fatalf("Erro inesperado: %v", err)
+        }
+    }
+
+    if err := service.ForgotPassword(ctx, "other@example.com", "10.0.0.1"); !errors.Is(err, ErrResetThrottled) {
+        t.Errorf("Erro esperado por IP: %v, recebido: %v", ErrResetThrottled, err)
+    }
+    service.Wait()
+}
+
+func TestResetPassword(t *testing.T) {
+    mockRepo := &mockUserRepository{password: "old-hash"}
+    m := mailer.NewMemoryMailer()
+    service := NewPasswordResetService(mockRepo, m, time.Hour, "")
+    ctx := context.Background()
+
+    mockRepo.CreateSession(ctx, internal.Session{UserID: 1}, time.Hour)
+
+    for i := 0; i < 2; i++ {
+        if err := service.ForgotPassword(ctx, "valid@example.com", "10.0.0.1"); err != nil {
+            t.Fatalf("Erro inesperado: %v", err)
+        }
+        service.Wait()
+        // Só um código antigo é substituído por um novo.
+        mockRepo.resetTokens[len(mockRepo.resetTokens)-1].CreatedAt = time.Now().Add(-RESET_REISSUE_AFTER)
+    }
+    sent := m.Sent()
+    oldCode, code := resetCode.FindString(sent[0].Body), resetCode.FindString(sent[1].Body)
+
+    tests := []struct {
+        name        string
+        input       internal.PasswordReset
+        wantError   error
+    }{
+        {name: "Código vazio", input: internal.PasswordReset{NewPassword: "NewStrong456"}, wantError: ErrResetTokenInvalid},
+        {name: "Senha fraca", input: internal.PasswordReset{Token: code, NewPassword: "weak"}, wantError: ErrPasswordTooShort},
+        {name: "Código substituído", input: internal.PasswordReset{Token: oldCode, NewPassword: "NewStrong456"}, wantError: ErrResetTokenInvalid},
+        {name: "Senha redefinida", input: internal.PasswordReset{Token: code, NewPassword: "NewStrong456"}},
+        {name: "Código já usado", input: internal.PasswordReset{Token: code, NewPassword: "OtherStrong789"}, wantError: ErrResetTokenInvalid},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            reset, err := service.ResetPassword(ctx, tt.input)
+            if !errors.Is(err, tt.wantError) {
+                t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+            }
+
+            if reset != (tt.wantError == nil) {
+                t.Errorf("[%s] Redefinição esperada: %v, recebida: %v", tt.name, tt.wantError == nil, reset)
+            }
+        })
+    }
+
+    if !utils.CheckPasswordHash("NewStrong456", mockRepo.password) {
+        t.Errorf("A nova senha deveria ser gravada com hash")
+    }
+
+    if active, _ := mockRepo.IsSessionActive(ctx, 1, 1); active {
+        t.Errorf("As sessões do usuário deveriam ser revogadas")
+    }
+}
@@ -2,13 +2,27 @@ package user
 
 import (
     "context"
+    "time"
     "github.com/amarantec/move-easy/internal"
+    "github.com/jackc/pgx/v5"
     "github.com/jackc/pgx/v5/pgxpool"
 )
 
 type IUserRepository interface {
     Register(ctx context.Context, user internal.UserRegister) (int64, error)
     ValidateCredentials(ctx context.Context, user internal.UserLogin) (internal.UserLogin, error)
+    IsAdmin(ctx context.Context, userID int64) (bool, error)
+    GetPasswordHash(ctx context.Context, userID int64) (string, error)
+    UpdatePassword(ctx context.Context, userID int64, password string) (bool, error)
+    GetUserIDByEmail(ctx context.Context, email string) (int64, error)
+    CreatePasswordResetToken(ctx context.Context, token internal.PasswordResetToken, ttl, reissueAfter time.Duration) (int64, error)
+    ResetPassword(ctx context.Context, tokenHash, password string) (int64, error)
+    CreateSession(ctx context.Context, session internal.Session, ttl time.Duration) (int64, error)
+    GetSessionByRefreshToken(ctx context.Context, tokenHash string) (internal.Session, error)
+    RotateSession(ctx context.Context, sessionID int64, oldHash, newHash string, ttl time.Duration) (bool, error)
+    IsSessionActive(ctx context.Context, userID, sessionID int64) (bool, error)
+    RevokeSession(ctx context.Context, userID, sessionID int64) (bool, error)
+    RevokeUserSessions(ctx context.Context, userID, exceptSessionID int64) (int64, error)
 }
 
 type userRepository struct {
@@ -45,3 +59,266 @@ func (r *userRepository) ValidateCredentials(ctx context.Context, user internal.
 
     return user, nil
 }
+
+func (r *userRepository) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+    var isAdmin bool
+    err :=
+        r.Conn.QueryRow(
+            ctx,
+            `SELECT is_admin FROM users WHERE id = $1 AND deleted_at IS NULL;`, userID).Scan(&isAdmin)
+    if err != nil {
+        if err == pgx.ErrNoRows {
+            return false, nil
+        }
+        return false, err
+    }
+
+    return isAdmin, nil
+}
+
+func (r *userRepository) GetPasswordHash(ctx context.Context, userID int64) (string, error) {
+    var password string
+    err :=
+        r.Conn.QueryRow(
+            ctx,
+            `SELECT password FROM users WHERE id = $1 AND deleted_at IS NULL;`, userID).Scan(&password)
+    if err != nil {
+        if err == pgx.ErrNoRows {
+            return internal.EMPTY, nil
+        }
+        return internal.EMPTY, err
+    }
+
+    return password, nil
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, userID int64, password string) (bool, error) {
+    result, err :=
+        r.Conn.Exec(
+            ctx,
+            `UPDATE users SET password = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL;`,
+            userID, password)
+    if err != nil {
+        return false, err
+    }
+
+    return result.RowsAffected() > internal.ZERO, nil
+}
+
+func (r *userRepository) GetUserIDByEmail(ctx context.Context, email string) (int64, error) {
+    var userID int64
+    err :=
+        r.Conn.QueryRow(
+            ctx,
+            `SELECT id FROM users WHERE email = $1 AND deleted_at IS NULL;`, email).Scan(&userID)
+    if err != nil {
+        if err == pgx.ErrNoRows {
+            return internal.ZERO, nil
+        }
+        return internal.ZERO, err
+    }
+
+    return userID, nil
+}
+
+// CreatePasswordResetToken stores a new reset token valid for ttl,
+// invalidating the ones the user asked for before, so only the latest mail
+// works. While a token issued less than reissueAfter ago is unused nothing is
+// stored and zero is returned, so nobody else can void the mail the user just
+// got. The expiry is computed by the database, which is also the clock it is
+// checked against.
+func (r *userRepository) CreatePasswordResetToken(ctx context.Context, token internal.PasswordResetToken, ttl, reissueAfter time.Duration) (int64, error) {
+    tx, err := r.Conn.Begin(ctx)
+    if err != nil {
+        return internal.ZERO, err
+    }
+    defer tx.Rollback(ctx)
+
+    // Locking the user keeps two requests from both finding no recent token.
+    var recent bool
+    if err :=
+        tx.QueryRow(
+            ctx,
+            `SELECT EXISTS (SELECT 1 FROM password_reset_tokens
+                WHERE user_id = u.id AND used_at IS NULL AND expires_at > NOW()
+                    AND created_at > NOW() - make_interval(secs => $2))
+                FROM users u WHERE u.id = $1 FOR UPDATE;`,
+            token.UserID, reissueAfter.Seconds()).Scan(&recent); err != nil {
+        if err == pgx.ErrNoRows {
+            return internal.ZERO, nil
+        }
+        return internal.ZERO, err
+    }
+
+    if recent {
+        return internal.ZERO, nil
+    }
+
+    if _, err :=
+        tx.Exec(
+            ctx,
+            `UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL;`,
+            token.UserID); err != nil {
+        return internal.ZERO, err
+    }
+
+    if err :=
+        tx.QueryRow(
+            ctx,
+            `INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+                VALUES ($1, $2, NOW() + make_interval(secs => $3)) RETURNING id;`,
+            token.UserID, token.TokenHash, ttl.Seconds()).Scan(&token.ID); err != nil {
+        return internal.ZERO, err
+    }
+
+    if err := tx.Commit(ctx); err != nil {
+        return internal.ZERO, err
+    }
+
+    return token.ID, nil
+}
+
+// ResetPassword consumes an unused, unexpired reset token and sets the
+// password of its user, returning the user id, or zero when the token is not
+// valid.
+func (r *userRepository) ResetPassword(ctx context.Context, tokenHash, password string) (int64, error) {
+    tx, err := r.Conn.Begin(ctx)
+    if err != nil {
+        return internal.ZERO, err
+    }
+    defer tx.Rollback(ctx)
+
+    var userID int64
+    if err :=
+        tx.QueryRow(
+            ctx,
+            `UPDATE password_reset_tokens SET used_at = NOW()
+                WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+                RETURNING user_id;`, tokenHash).Scan(&userID); err != nil {
+        if err == pgx.ErrNoRows {
+            return internal.ZERO, nil
+        }
+        return internal.ZERO, err
+    }
+
+    result, err :=
+        tx.Exec(
+            ctx,
+            `UPDATE users SET password = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL;`,
+            userID, password)
+    if err != nil {
+        return internal.ZERO, err
+    }
+
+    if result.RowsAffected() == internal.ZERO {
+        return internal.ZERO, nil
+    }
+
+    if err := tx.Commit(ctx); err != nil {
+        return internal.ZERO, err
+    }
+
+    return userID, nil
+}
+
+// CreateSession stores a session valid for ttl. Like every other session
+// expiry it is computed and checked with the database clock, never the one
+// of the API.
+func (r *userRepository) CreateSession(ctx context.Context, session internal.Session, ttl time.Duration) (int64, error) {
+    err :=
+        r.Conn.QueryRow(
+            ctx,
+            `INSERT INTO sessions (user_id, refresh_token_hash, expires_at)
+                VALUES ($1, $2, NOW() + make_interval(secs => $3)) RETURNING id;`,
+            session.UserID, session.RefreshTokenHash, ttl.Seconds()).Scan(&session.ID)
+    if err != nil {
+        return internal.ZERO, err
+    }
+
+    return session.ID, nil
+}
+
+// GetSessionByRefreshToken finds the unexpired session whose current or
+// previous refresh token has the given hash, so a replayed token can be told
+// apart from an unknown one.
+func (r *userRepository) GetSessionByRefreshToken(ctx context.Context, tokenHash string) (internal.Session, error) {
+    var session internal.Session
+    err :=
+        r.Conn.QueryRow(
+            ctx,
+            `SELECT s.id, s.user_id, u.email, s.refresh_token_hash, s.previous_token_hash, s.expires_at,
+                s.revoked_at, s.created_at, s.updated_at
+                FROM sessions s JOIN users u ON u.id = s.user_id AND u.deleted_at IS NULL
+                WHERE (s.refresh_token_hash = $1 OR s.previous_token_hash = $1) AND s.expires_at > NOW();`, tokenHash).Scan(&session.ID,
+            &session.UserID, &session.Email, &session.RefreshTokenHash, &session.PreviousTokenHash,
+            &session.ExpiresAt, &session.RevokedAt, &session.CreatedAt, &session.UpdatedAt)
+    if err != nil {
+        if err == pgx.ErrNoRows {
+            return internal.Session{}, nil
+        }
+        return internal.Session{}, err
+    }
+
+    return session, nil
+}
+
+// RotateSession replaces the refresh token only if oldHash is still the
+// current one, so two concurrent refreshes cannot both succeed, and extends
+// the session for ttl.
+func (r *userRepository) RotateSession(ctx context.Context, sessionID int64, oldHash, newHash string, ttl time.Duration) (bool, error) {
+    result, err :=
+        r.Conn.Exec(
+            ctx,
+            `UPDATE sessions SET previous_token_hash = refresh_token_hash, refresh_token_hash = $3,
+                expires_at = NOW() + make_interval(secs => $4), updated_at = NOW()
+                WHERE id = $1 AND refresh_token_hash = $2 AND revoked_at IS NULL AND expires_at > NOW();`,
+            sessionID, oldHash, newHash, ttl.Seconds())
+    if err != nil {
+        return false, err
+    }
+
+    return result.RowsAffected() > internal.ZERO, nil
+}
+
+func (r *userRepository) IsSessionActive(ctx context.Context, userID, sessionID int64) (bool, error) {
+    var active bool
+    err :=
+        r.Conn.QueryRow(
+            ctx,
+            `SELECT EXISTS (SELECT 1 FROM sessions
+                WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL AND expires_at > NOW());`,
+            sessionID, userID).Scan(&active)
+    if err != nil {
+        return false, err
+    }
+
+    return active, nil
+}
+
+func (r *userRepository) RevokeSession(ctx context.Context, userID, sessionID int64) (bool, error) {
+    result, err :=
+        r.Conn.Exec(
+            ctx,
+            `UPDATE sessions SET revoked_at = NOW(), updated_at = NOW()
+                WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;`, sessionID, userID)
+    if err != nil {
+        return false, err
+    }
+
+    return result.RowsAffected() > internal.ZERO, nil
+}
+
+// RevokeUserSessions revokes every active session of the user but
+// exceptSessionID, which may be zero to revoke them all.
+func (r *userRepository) RevokeUserSessions(ctx context.Context, userID, exceptSessionID int64) (int64, error) {
+    result, err :=
+        r.Conn.Exec(
+            ctx,
+            `UPDATE sessions SET revoked_at = NOW(), updated_at = NOW()
+                WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL;`, userID, exceptSessionID)
+    if err != nil {
+        return internal.ZERO, err
+    }
+
+    return result.RowsAffected(), nil
+}
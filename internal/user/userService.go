@@ -2,21 +2,41 @@ package user
 
 import (
     "context"
+    "errors"
+    "time"
+    "unicode"
+    "unicode/utf8"
     "github.com/amarantec/move-easy/internal"
     "github.com/amarantec/move-easy/internal/utils"
 )
 
+const (
+    MIN_PASSWORD_LENGTH = 8
+    MAX_PASSWORD_BYTES = 72
+)
+
 type IUserService interface {
     Register (ctx context.Context, user internal.UserRegister) (int64, error)
-    ValidateCredentials(ctx context.Context, user internal.UserLogin) (string, error)
+    ValidateCredentials(ctx context.Context, user internal.UserLogin) (internal.AuthTokens, error)
+    Refresh(ctx context.Context, refreshToken string) (internal.AuthTokens, error)
+    Logout(ctx context.Context, userID, sessionID int64) (bool, error)
+    LogoutAll(ctx context.Context, userID int64) (int64, error)
+    IsSessionActive(ctx context.Context, userID, sessionID int64) (bool, error)
+    ChangePassword(ctx context.Context, userID, sessionID int64, change internal.ChangePassword) (bool, error)
+    IsAdmin(ctx context.Context, userID int64) (bool, error)
 }
 
 type userService struct {
-    userRepository IUserRepository
+    userRepository  IUserRepository
+    accessTTL       time.Duration
+    refreshTTL      time.Duration
 }
 
-func NewUserService(repository IUserRepository) IUserService {
-    return &userService{userRepository: repository}
+// NewUserService issues access tokens valid for accessTTL, paired with a
+// refresh token that keeps the session alive for refreshTTL after its last
+// use.
+func NewUserService(repository IUserRepository, accessTTL, refreshTTL time.Duration) IUserService {
+    return &userService{userRepository: repository, accessTTL: accessTTL, refreshTTL: refreshTTL}
 }
 
 func (s *userService) Register (ctx context.Context, user internal.UserRegister) (int64, error) {
@@ -35,22 +55,202 @@ func (s *userService) Register (ctx context.Context, user internal.UserRegister)
     return response, nil
 }
 
-func (s *userService) ValidateCredentials(ctx context.Context, user internal.UserLogin) (string, error) {
+// ValidateCredentials starts a new session when the password matches, and
+// returns empty tokens when it does not.
+func (s *userService) ValidateCredentials(ctx context.Context, user internal.UserLogin) (internal.AuthTokens, error) {
     userDb, err := s.userRepository.ValidateCredentials(ctx, user)
     if err != nil {
-        return internal.EMPTY, err
+        return internal.AuthTokens{}, err
     }
 
     passwordIsValid :=
         utils.CheckPasswordHash(user.Password, userDb.Password)
     if !passwordIsValid {
-        return internal.EMPTY, nil
+        return internal.AuthTokens{}, nil
+    }
+
+    refreshToken, refreshHash, err := utils.GenerateOpaqueToken()
+    if err != nil {
+        return internal.AuthTokens{}, err
+    }
+
+    sessionID, err := s.userRepository.CreateSession(ctx, internal.Session{
+        UserID: userDb.ID,
+        RefreshTokenHash: refreshHash,
+    }, s.refreshTTL)
+    if err != nil {
+        return internal.AuthTokens{}, err
+    }
+
+    return s.issueTokens(userDb.Email, userDb.ID, sessionID, refreshToken)
+}
+
+// Refresh trades a refresh token for a new pair. Each refresh token works
+// once: presenting one that was already rotated means it leaked, and the
+// whole session is revoked.
+func (s *userService) Refresh(ctx context.Context, refreshToken string) (internal.AuthTokens, error) {
+    if refreshToken == internal.EMPTY {
+        return internal.AuthTokens{}, ErrRefreshTokenInvalid
+    }
+
+    hash := utils.HashToken(refreshToken)
+    session, err := s.userRepository.GetSessionByRefreshToken(ctx, hash)
+    if err != nil {
+        return internal.AuthTokens{}, err
+    }
+
+    if session.ID == internal.ZERO || session.RevokedAt != nil {
+        return internal.AuthTokens{}, ErrRefreshTokenInvalid
+    }
+
+    if session.RefreshTokenHash != hash {
+        if _, err := s.userRepository.RevokeSession(ctx, session.UserID, session.ID); err != nil {
+            return internal.AuthTokens{}, err
+        }
+        return internal.AuthTokens{}, ErrRefreshTokenReused
+    }
+
+    newToken, newHash, err := utils.GenerateOpaqueToken()
+    if err != nil {
+        return internal.AuthTokens{}, err
+    }
+
+    rotated, err := s.userRepository.RotateSession(ctx, session.ID, hash, newHash, s.refreshTTL)
+    if err != nil {
+        return internal.AuthTokens{}, err
+    }
+
+    if !rotated {
+        return internal.AuthTokens{}, ErrRefreshTokenInvalid
+    }
+
+    return s.issueTokens(session.Email, session.UserID, session.ID, newToken)
+}
+
+func (s *userService) Logout(ctx context.Context, userID, sessionID int64) (bool, error) {
+    if userID <= internal.ZERO || sessionID <= internal.ZERO {
+        return false, ErrSessionInvalid
+    }
+
+    return s.userRepository.RevokeSession(ctx, userID, sessionID)
+}
+
+func (s *userService) LogoutAll(ctx context.Context, userID int64) (int64, error) {
+    if userID <= internal.ZERO {
+        return internal.ZERO, ErrSessionInvalid
+    }
+
+    return s.userRepository.RevokeUserSessions(ctx, userID, internal.ZERO)
+}
+
+func (s *userService) IsSessionActive(ctx context.Context, userID, sessionID int64) (bool, error) {
+    if userID <= internal.ZERO || sessionID <= internal.ZERO {
+        return false, nil
     }
 
-    token, err := utils.GenerateToken(userDb.Email, userDb.ID)
+    return s.userRepository.IsSessionActive(ctx, userID, sessionID)
+}
+
+// ChangePassword replaces the password after checking the current one, and
+// revokes every other session of the user, keeping the one that asked.
+func (s *userService) ChangePassword(ctx context.Context, userID, sessionID int64, change internal.ChangePassword) (bool, error) {
+    if userID <= internal.ZERO {
+        return false, ErrSessionInvalid
+    }
+
+    currentHash, err := s.userRepository.GetPasswordHash(ctx, userID)
+    if err != nil {
+        return false, err
+    }
+
+    if currentHash == internal.EMPTY || !utils.CheckPasswordHash(change.CurrentPassword, currentHash) {
+        return false, ErrCurrentPasswordWrong
+    }
+
+    newPassword := string(change.NewPassword)
+    if err := validatePassword(newPassword); err != nil {
+        return false, err
+    }
+
+    if newPassword == change.CurrentPassword {
+        return false, ErrPasswordUnchanged
+    }
+
+    hashedPassword, err := utils.HashPassword(newPassword)
     if err != nil {
-        return internal.EMPTY, err
-     }
+        return false, err
+    }
+
+    updated, err := s.userRepository.UpdatePassword(ctx, userID, hashedPassword)
+    if err != nil || !updated {
+        return false, err
+    }
+
+    if _, err := s.userRepository.RevokeUserSessions(ctx, userID, sessionID); err != nil {
+        return false, err
+    }
 
-     return token, nil
+    return true, nil
 }
+
+func (s *userService) issueTokens(email string, userID, sessionID int64, refreshToken string) (internal.AuthTokens, error) {
+    accessToken, err := utils.GenerateToken(email, userID, sessionID, s.accessTTL)
+    if err != nil {
+        return internal.AuthTokens{}, err
+    }
+
+    return internal.AuthTokens{
+        AccessToken: accessToken,
+        RefreshToken: refreshToken,
+        ExpiresAt: time.Now().Add(s.accessTTL),
+    }, nil
+}
+
+func (s *userService) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+    if userID <= internal.ZERO {
+        return false, nil
+    }
+
+    return s.userRepository.IsAdmin(ctx, userID)
+}
+
+// validatePassword enforces the password policy: 8 to 72 bytes, the most
+// bcrypt uses, with upper and lower case letters and a digit.
+func validatePassword(password string) error {
+    if utf8.RuneCountInString(password) < MIN_PASSWORD_LENGTH {
+        return ErrPasswordTooShort
+    }
+
+    if len(password) > MAX_PASSWORD_BYTES {
+        return ErrPasswordTooLong
+    }
+
+    var upper, lower, digit bool
+    for _, r := range password {
+        switch {
+        case unicode.IsUpper(r):
+            upper = true
+        case unicode.IsLower(r):
+            lower = true
+        case unicode.IsDigit(r):
+            digit = true
+        }
+    }
+
+    if !upper || !lower || !digit {
+        return ErrPasswordWeak
+    }
+
+    return nil
+}
+
+var (
+    ErrCurrentPasswordWrong = errors.New("current password is wrong")
+    ErrPasswordTooShort = errors.New("password must have at least 8 characters")
+    ErrPasswordTooLong = errors.New("password must have at most 72 bytes")
+    ErrPasswordWeak = errors.New("password must have upper and lower case letters and a digit")
+    ErrPasswordUnchanged = errors.New("new password must be different from the current one")
+    ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+    ErrRefreshTokenReused = errors.New("refresh token was already used, the session has been revoked")
+    ErrSessionInvalid = errors.New("user id and session id must be greater than zero")
+)
@@ -3,7 +3,9 @@ package user
 import (
     "context"
     "errors"
+    "strings"
     "testing"
+    "time"
     "github.com/amarantec/move-easy/internal"
     "github.com/amarantec/move-easy/internal/utils"
 )
@@ -11,6 +13,10 @@ import (
 type mockUserRepository struct {
     RegisterFunc func (ctx context.Context, user internal.UserRegister) (int64, error)
     ValidateCredentialsFunc func (ctx context.Context, user internal.UserLogin) (internal.UserLogin, error) 
+    IsAdminFunc func (ctx context.Context, userID int64) (bool, error)
+    sessions []internal.Session
+    password string
+    resetTokens []internal.PasswordResetToken
 }
 
 func (m *mockUserRepository) Register(ctx context.Context, user internal.UserRegister) (int64, error) {
@@ -29,6 +35,120 @@ func (m *mockUserRepository) ValidateCredentials(ctx context.Context, user inter
     return internal.UserLogin{}, ErrValidateCredentialsFuncNotImplemented
 }
 
+func (m *mockUserRepository) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+    if m.IsAdminFunc != nil {
+        return m.IsAdminFunc(ctx, userID)
+    }
+    return false, ErrIsAdminFuncNotImplemented
+}
+
+func (m *mockUserRepository) GetPasswordHash(ctx context.Context, userID int64) (string, error) {
+    return m.password, nil
+}
+
+func (m *mockUserRepository) UpdatePassword(ctx context.Context, userID int64, password string) (bool, error) {
+    m.password = password
+    return true, nil
+}
+
+func (m *mockUserRepository) GetUserIDByEmail(ctx context.Context, email string) (int64, error) {
+    if email == "valid@example.com" {
+        return 1, nil
+    }
+    return internal.ZERO, nil
+}
+
+func (m *mockUserRepository) CreatePasswordResetToken(ctx context.Context, token internal.PasswordResetToken, ttl, reissueAfter time.Duration) (int64, error) {
+    now := time.Now()
+    for _, t := range m.resetTokens {
+        if t.UserID == token.UserID && t.UsedAt == nil && t.ExpiresAt.After(now) && now.Sub(t.CreatedAt) < reissueAfter {
+            return internal.ZERO, nil
+        }
+    }
+    token.ExpiresAt = now.Add(ttl)
+    token.CreatedAt = now
+    for i := range m.resetTokens {
+        if m.resetTokens[i].UserID == token.UserID && m.resetTokens[i].UsedAt == nil {
+            m.resetTokens[i].UsedAt = &now
+        }
+    }
+    token.ID = int64(len(m.resetTokens) + 1)
+    m.resetTokens = append(m.resetTokens, token)
+    return token.ID, nil
+}
+
+func (m *mockUserRepository) ResetPassword(ctx context.Context, tokenHash, password string) (int64, error) {
+    now := time.Now()
+    for i := range m.resetTokens {
+        token := &m.resetTokens[i]
+        if token.TokenHash == tokenHash && token.UsedAt == nil && token.ExpiresAt.After(now) {
+            token.UsedAt = &now
+            m.password = password
+            return token.UserID, nil
+        }
+    }
+    return internal.ZERO, nil
+}
+
+func (m *mockUserRepository) CreateSession(ctx context.Context, session internal.Session, ttl time.Duration) (int64, error) {
+    session.ExpiresAt = time.Now().Add(ttl)
+    session.ID = int64(len(m.sessions) + 1)
+    m.sessions = append(m.sessions, session)
+    return session.ID, nil
+}
+
+func (m *mockUserRepository) GetSessionByRefreshToken(ctx context.Context, tokenHash string) (internal.Session, error) {
+    for _, session := range m.sessions {
+        if (session.RefreshTokenHash == tokenHash || (session.PreviousTokenHash != nil && *session.PreviousTokenHash == tokenHash)) &&
+            session.ExpiresAt.After(time.Now()) {
+            return session, nil
+        }
+    }
+    return internal.Session{}, nil
+}
+
+func (m *mockUserRepository) RotateSession(ctx context.Context, sessionID int64, oldHash, newHash string, ttl time.Duration) (bool, error) {
+    session := &m.sessions[sessionID-1]
+    if session.RefreshTokenHash != oldHash || session.RevokedAt != nil || !session.ExpiresAt.After(time.Now()) {
+        return false, nil
+    }
+    session.PreviousTokenHash = &oldHash
+    session.RefreshTokenHash = newHash
+    session.ExpiresAt = time.Now().Add(ttl)
+    return true, nil
+}
+
+func (m *mockUserRepository) IsSessionActive(ctx context.Context, userID, sessionID int64) (bool, error) {
+    if sessionID > int64(len(m.sessions)) {
+        return false, nil
+    }
+    session := m.sessions[sessionID-1]
+    return session.UserID == userID && session.RevokedAt == nil && session.ExpiresAt.After(time.Now()), nil
+}
+
+func (m *mockUserRepository) RevokeSession(ctx context.Context, userID, sessionID int64) (bool, error) {
+    now := time.Now()
+    session := &m.sessions[sessionID-1]
+    if session.UserID != userID || session.RevokedAt != nil {
+        return false, nil
+    }
+    session.RevokedAt = &now
+    return true, nil
+}
+
+func (m *mockUserRepository) RevokeUserSessions(ctx context.Context, userID, exceptSessionID int64) (int64, error) {
+    var revoked int64
+    for i := range m.sessions {
+        if m.sessions[i].UserID == userID && m.sessions[i].ID != exceptSessionID && m.sessions[i].RevokedAt == nil {
+            if _, err := m.RevokeSession(ctx, userID, m.sessions[i].ID); err != nil {
+                return internal.ZERO, err
+            }
+            revoked++
+        }
+    }
+    return revoked, nil
+}
+
 func TestRegister(t *testing.T) {
     tests := []struct {
         name        string
@@ -105,7 +225,7 @@ func TestRegister(t *testing.T) {
                 },
             }
 
-            service := NewUserService(mockRepo)
+            service := NewUserService(mockRepo, time.Minute, time.Hour)
 
             id, err := service.Register(context.Background(), tt.input)
             if (err != nil) != tt.wantError {
@@ -119,9 +239,146 @@ func TestRegister(t *testing.T) {
     }
 }
 
+func TestRefreshRotation(t *testing.T) {
+    keys, err := utils.ParseJWTKeys("k1=aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", "", "")
+    if err != nil {
+        t.Fatalf("Erro inesperado: %v", err)
+    }
+    utils.SetJWTKeys(keys)
+
+    hashedPassword, err := utils.HashPassword("StrongPass123")
+    if err != nil {
+        t.Fatalf("Erro inesperado: %v", err)
+    }
+
+    mockRepo := &mockUserRepository{
+        ValidateCredentialsFunc: func(ctx context.Context, user internal.UserLogin) (internal.UserLogin, error) {
+            return internal.UserLogin{ID: 1, Email: user.Email, Password: hashedPassword}, nil
+        },
+    }
+    service := NewUserService(mockRepo, time.Minute, time.Hour)
+    ctx := context.Background()
+
+    login, err := service.ValidateCredentials(ctx, internal.UserLogin{Email: "valid@example.com", Password: "StrongPass123"})
+    if err != nil || login.AccessToken == internal.EMPTY || login.RefreshToken == internal.EMPTY {
+        t.Fatalf("Tokens esperados, recebido: %+v (erro: %v)", login, err)
+    }
+
+    if mockRepo.sessions[0].RefreshTokenHash == login.RefreshToken {
+        t.Errorf("O refresh token não deve ser guardado em texto puro")
+    }
+
+    refreshed, err := service.Refresh(ctx, login.RefreshToken)
+    if err != nil || refreshed.RefreshToken == login.RefreshToken {
+        t.Fatalf("Novo refresh token esperado, recebido: %+v (erro: %v)", refreshed, err)
+    }
+
+    claims, err := utils.VerifyToken(refreshed.AccessToken)
+    if err != nil || claims.UserID != 1 || claims.SessionID != 1 {
+        t.Errorf("Usuário 1 na sessão 1 esperado, recebido: %+v (erro: %v)", claims, err)
+    }
+
+    if _, err := service.Refresh(ctx, login.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+        t.Errorf("Erro esperado: %v, recebido: %v", ErrRefreshTokenReused, err)
+    }
+
+    if _, err := service.Refresh(ctx, refreshed.RefreshToken); !errors.Is(err, ErrRefreshTokenInvalid) {
+        t.Errorf("Sessão revogada após reuso, erro esperado: %v, recebido: %v", ErrRefreshTokenInvalid, err)
+    }
+
+    if active, _ := service.IsSessionActive(ctx, 1, 1); active {
+        t.Errorf("A sessão deveria estar revogada")
+    }
+
+    if _, err := service.Refresh(ctx, "unknown"); !errors.Is(err, ErrRefreshTokenInvalid) {
+        t.Errorf("Erro esperado: %v, recebido: %v", ErrRefreshTokenInvalid, err)
+    }
+}
+
+func TestLogoutAll(t *testing.T) {
+    mockRepo := &mockUserRepository{}
+    service := NewUserService(mockRepo, time.Minute, time.Hour)
+    ctx := context.Background()
+
+    for _, userID := range []int64{1, 1, 2} {
+        mockRepo.CreateSession(ctx, internal.Session{UserID: userID}, time.Hour)
+    }
+
+    if revoked, err := service.LogoutAll(ctx, 1); err != nil || revoked != 2 {
+        t.Errorf("Esperava 2 sessões revogadas, recebeu %d (erro: %v)", revoked, err)
+    }
+
+    if active, _ := service.IsSessionActive(ctx, 2, 3); !active {
+        t.Errorf("A sessão de outro usuário não deveria ser revogada")
+    }
+
+    if _, err := service.Logout(ctx, 1, internal.ZERO); !errors.Is(err, ErrSessionInvalid) {
+        t.Errorf("Erro esperado: %v, recebido: %v", ErrSessionInvalid, err)
+    }
+}
+
+func TestChangePassword(t *testing.T) {
+    hashedPassword, err := utils.HashPassword("StrongPass123")
+    if err != nil {
+        t.Fatalf("Erro inesperado: %v", err)
+    }
+
+    tests := []struct {
+        name        string
+        input       internal.ChangePassword
+        wantError   error
+    }{
+        {name: "Senha atual errada", input: internal.ChangePassword{CurrentPassword: "WrongPass123", NewPassword: "NewStrong456"}, wantError: ErrCurrentPasswordWrong},
+        {name: "Senha curta", input: internal.ChangePassword{CurrentPassword: "StrongPass123", NewPassword: "Ab1"}, wantError: ErrPasswordTooShort},
+        {name: "Senha longa", input: internal.ChangePassword{CurrentPassword: "StrongPass123", NewPassword: internal.NewPassword("Ab1" + strings.Repeat("x", 70))}, wantError: ErrPasswordTooLong},
+        {name: "Senha sem dígito", input: internal.ChangePassword{CurrentPassword: "StrongPass123", NewPassword: "NoDigitsHere"}, wantError: ErrPasswordWeak},
+        {name: "Senha sem maiúscula", input: internal.ChangePassword{CurrentPassword: "StrongPass123", NewPassword: "lowercase123"}, wantError: ErrPasswordWeak},
+        {name: "Senha igual à atual", input: internal.ChangePassword{CurrentPassword: "StrongPass123", NewPassword: "StrongPass123"}, wantError: ErrPasswordUnchanged},
+        {name: "Senha alterada", input: internal.ChangePassword{CurrentPassword: "StrongPass123", NewPassword: "NewStrong456"}},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            mockRepo := &mockUserRepository{password: hashedPassword}
+            service := NewUserService(mockRepo, time.Minute, time.Hour)
+            ctx := context.Background()
+            for i := 0; i < 3; i++ {
+                mockRepo.CreateSession(ctx, internal.Session{UserID: 1}, time.Hour)
+            }
+
+            changed, err := service.ChangePassword(ctx, 1, 2, tt.input)
+            if !errors.Is(err, tt.wantError) {
+                t.Fatalf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+            }
+
+            if changed != (tt.wantError == nil) {
+                t.Errorf("[%s] Alteração esperada: %v, recebida: %v", tt.name, tt.wantError == nil, changed)
+            }
+
+            if tt.wantError != nil {
+                if mockRepo.password != hashedPassword {
+                    t.Errorf("[%s] A senha não deveria ser alterada", tt.name)
+                }
+                return
+            }
+
+            if !utils.CheckPasswordHash(string(tt.input.NewPassword), mockRepo.password) {
+                t.Errorf("[%s] A nova senha deveria ser gravada com hash", tt.name)
+            }
+
+            for sessionID, wantActive := range map[int64]bool{1: false, 2: true, 3: false} {
+                if active, _ := service.IsSessionActive(ctx, 1, sessionID); active != wantActive {
+                    t.Errorf("[%s] Sessão %d ativa esperada: %v, recebida: %v", tt.name, sessionID, wantActive, active)
+                }
+            }
+        })
+    }
+}
+
 var (
     ErrRegisterFuncNotImplemented = errors.New("RegisterFunc not implemented")
     ErrValidateCredentialsFuncNotImplemented = errors.New("ValidateCredentialsFunc not implemented")
+    ErrIsAdminFuncNotImplemented = errors.New("IsAdminFunc not implemented")
     ErrEmailEmpty = errors.New("Email cannot be empty")
     ErrPasswordEmpty = errors.New("Password cannot be empty")
     ErrDatabaseError = errors.New("Database error")
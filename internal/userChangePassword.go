@@ -1,3 +1,8 @@
 package internal
 
 type NewPassword string
+
+type ChangePassword struct {
+	CurrentPassword	string
+	NewPassword		NewPassword
+}
@@ -9,5 +9,10 @@ type UserFeedback struct {
 	Longitude	float64
 	Description	string
 	Category	string
+	Upvotes		int64
+	Downvotes	int64
+	Score		int64
 	CreatedAt	time.Time
+	UpdatedAt	*time.Time
+	DeletedAt	*time.Time
 }
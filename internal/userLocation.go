@@ -3,9 +3,11 @@ package internal
 import "time"
 
 type UserLocation struct {
+	ID			int64
 	UserID		int64
 	LineID		int64
 	Latitude	float64
 	Longitude	float64
 	TimeStamp	time.Time
+	CreatedAt	time.Time
 }
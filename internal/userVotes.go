@@ -6,6 +6,6 @@ type UserVotes struct {
 	ID		int64
 	UserID	int64
 	FeedbackID	int64
-	VoteType	VoteType
+	VoteType	*VoteType
 	VotedAt		time.Time
 }
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+// CSVTable reads a CSV whose first line names its columns, so the datasets
+// can list them in any order and case and leave the optional ones out. A
+// UTF-8 byte order mark before the first name, as spreadsheets save it, is
+// dropped.
+type CSVTable struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+type CSVRecord struct {
+	Line    int
+	columns map[string]int
+	fields  []string
+}
+
+func NewCSVTable(r io.Reader) (*CSVTable, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	return &CSVTable{reader: reader, columns: columns}, nil
+}
+
+// Missing returns the first of names the header does not have, or an empty
+// string when it has them all.
+func (t *CSVTable) Missing(names ...string) string {
+	for _, name := range names {
+		if _, ok := t.columns[name]; !ok {
+			return name
+		}
+	}
+	return internal.EMPTY
+}
+
+// Read returns the next record, or io.EOF after the last one. Its Line is
+// the line of the file it starts on.
+func (t *CSVTable) Read() (CSVRecord, error) {
+	fields, err := t.reader.Read()
+	if err != nil {
+		return CSVRecord{}, err
+	}
+
+	line, _ := t.reader.FieldPos(internal.ZERO)
+	return CSVRecord{Line: line, columns: t.columns, fields: fields}, nil
+}
+
+// Field returns the trimmed value of the column, empty when the table or the
+// record does not have it.
+func (r CSVRecord) Field(name string) string {
+	if i, ok := r.columns[name]; ok && i < len(r.fields) {
+		return strings.TrimSpace(r.fields[i])
+	}
+	return internal.EMPTY
+}
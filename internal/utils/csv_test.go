@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCSVTable(t *testing.T) {
+	table, err := NewCSVTable(strings.NewReader("\ufeff CEP ,City\n95520-000, Osório \n95530000\n"))
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if missing := table.Missing("cep", "city", "state"); missing != "state" {
+		t.Errorf("Coluna ausente esperada: state, recebida: %q", missing)
+	}
+
+	record, err := table.Read()
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if NormalizeCEP(record.Field("cep")) != "95520000" || record.Field("city") != "Osório" || record.Line != 2 {
+		t.Errorf("Registro inesperado na linha %d: %q, %q", record.Line, record.Field("cep"), record.Field("city"))
+	}
+
+	if record, err = table.Read(); err != nil || record.Field("city") != "" || record.Line != 3 {
+		t.Errorf("Registro curto deveria ter a cidade vazia na linha 3, recebido: %+v (erro: %v)", record, err)
+	}
+
+	if _, err := table.Read(); err != io.EOF {
+		t.Errorf("Erro esperado: %v, recebido: %v", io.EOF, err)
+	}
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == internal.EMPTY {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= internal.ZERO {
+		log.Printf("invalid duration %q for %s, using %s\n", value, key, fallback)
+		return fallback
+	}
+
+	return duration
+}
@@ -0,0 +1,49 @@
+package utils
+
+import "math"
+
+const EARTH_RADIUS_METERS = 6371000.0
+
+// DISTANCE_SQL is the haversine distance in meters between the latitude and
+// longitude columns of a row and the point given by the $1 and $2 parameters.
+const DISTANCE_SQL = `(2 * 6371000 * ASIN(SQRT(
+	POWER(SIN(RADIANS(latitude - $1) / 2), 2) +
+	COS(RADIANS($1)) * COS(RADIANS(latitude)) *
+	POWER(SIN(RADIANS(longitude - $2) / 2), 2))))`
+
+func ValidCoordinates(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+func HaversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+
+	a := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Pow(math.Sin(dLon/2), 2)
+
+	return 2 * EARTH_RADIUS_METERS * math.Asin(math.Sqrt(a))
+}
+
+// BoundingBox returns the smallest latitude/longitude box containing every
+// point within radius meters of the center, so queries can use a b-tree
+// index on (latitude, longitude) before computing exact distances.
+func BoundingBox(latitude, longitude, radius float64) (minLat, minLon, maxLat, maxLon float64) {
+	deltaLat := radius / EARTH_RADIUS_METERS * 180 / math.Pi
+	minLat = math.Max(latitude-deltaLat, -90)
+	maxLat = math.Min(latitude+deltaLat, 90)
+
+	cosLat := math.Cos(toRadians(latitude))
+	if cosLat < 1e-6 || minLat == -90 || maxLat == 90 {
+		return minLat, -180, maxLat, 180
+	}
+
+	deltaLon := deltaLat / cosLat
+	minLon = math.Max(longitude-deltaLon, -180)
+	maxLon = math.Min(longitude+deltaLon, 180)
+	return minLat, minLon, maxLat, maxLon
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
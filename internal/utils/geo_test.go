@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHaversineDistance(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want                   float64
+		tolerance              float64
+	}{
+		{name: "Mesmo ponto", lat1: -29.88, lon1: -50.27, lat2: -29.88, lon2: -50.27, want: 0, tolerance: 0.001},
+		{name: "Porto Alegre - Osório", lat1: -30.0346, lon1: -51.2177, lat2: -29.8869, lon2: -50.2697, want: 92800, tolerance: 1500},
+		{name: "Um grau de latitude", lat1: 0, lon1: 0, lat2: 1, lon2: 0, want: 111195, tolerance: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HaversineDistance(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+			if math.Abs(got-tt.want) > tt.tolerance {
+				t.Errorf("[%s] Distância esperada: %.0f, recebida: %.0f", tt.name, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBoundingBox(t *testing.T) {
+	lat, lon, radius := -29.88, -50.27, 1000.0
+	minLat, minLon, maxLat, maxLon := BoundingBox(lat, lon, radius)
+
+	corners := [][2]float64{{minLat, lon}, {maxLat, lon}, {lat, minLon}, {lat, maxLon}}
+	for _, corner := range corners {
+		distance := HaversineDistance(lat, lon, corner[0], corner[1])
+		if math.Abs(distance-radius) > 1 {
+			t.Errorf("Borda da caixa a %.2f metros do centro, esperado %.0f", distance, radius)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	JWT_PEM_PREFIX   = "pem:"
+	MIN_RSA_KEY_BITS = 2048
+	RSA_KEY_BITS     = 3072
+)
+
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Modulus   string `json:"n,omitempty"`
+	Exponent  string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// ParseJWTPEM reads an RSA or Ed25519 key. A private key signs and verifies
+// with RS256 or EdDSA, a public key only verifies.
+func ParseJWTPEM(id string, data []byte) (JWTKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return JWTKey{}, ErrJWTPEMInvalid
+	}
+
+	var signKey crypto.PrivateKey
+	var verifyKey crypto.PublicKey
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return JWTKey{}, fmt.Errorf("%w: %v", ErrJWTPEMInvalid, err)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return JWTKey{}, ErrJWTKeyTypeInvalid
+		}
+		signKey, verifyKey = signer, signer.Public()
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return JWTKey{}, fmt.Errorf("%w: %v", ErrJWTPEMInvalid, err)
+		}
+		signKey, verifyKey = key, key.Public()
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return JWTKey{}, fmt.Errorf("%w: %v", ErrJWTPEMInvalid, err)
+		}
+		verifyKey = key
+	default:
+		return JWTKey{}, fmt.Errorf("%w: %s", ErrJWTPEMInvalid, block.Type)
+	}
+
+	key := JWTKey{ID: id, SignKey: signKey, VerifyKey: verifyKey}
+	switch public := verifyKey.(type) {
+	case *rsa.PublicKey:
+		if public.N.BitLen() < MIN_RSA_KEY_BITS {
+			return JWTKey{}, ErrJWTRSAKeyTooShort
+		}
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return JWTKey{}, ErrJWTKeyTypeInvalid
+	}
+	return key, nil
+}
+
+// GenerateJWTKeyPair creates a key pair for RS256 or EdDSA, returning the
+// private key in PKCS #8 PEM and the public key in PKIX PEM.
+func GenerateJWTKeyPair(algorithm string) ([]byte, []byte, error) {
+	var private crypto.Signer
+	var err error
+	switch strings.ToUpper(algorithm) {
+	case jwt.SigningMethodRS256.Alg():
+		private, err = rsa.GenerateKey(rand.Reader, RSA_KEY_BITS)
+	case strings.ToUpper(jwt.SigningMethodEdDSA.Alg()):
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, nil, ErrJWTAlgorithmInvalid
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), nil
+}
+
+// JWKS publishes the public keys still accepted by VerifyToken. HMAC secrets
+// are never published.
+func JWKS() JWKSet {
+	keys := currentJWTKeys()
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range keys.Keys {
+		if key.RetiredAt != nil && time.Now().After(*key.RetiredAt) {
+			continue
+		}
+
+		jwk := JWK{KeyID: key.ID, Use: "sig", Algorithm: key.Method.Alg()}
+		switch public := key.VerifyKey.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.Modulus = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
+
+var (
+	ErrJWTPEMInvalid       = errors.New("JWT key is not a PEM encoded private or public key")
+	ErrJWTKeyTypeInvalid   = errors.New("JWT key must be an RSA or Ed25519 key")
+	ErrJWTRSAKeyTooShort   = errors.New("JWT RSA key must have at least 2048 bits")
+	ErrJWTAlgorithmInvalid = errors.New("JWT key algorithm must be RS256 or EdDSA")
+)
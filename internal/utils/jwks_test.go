@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func writeTestKey(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	return path
+}
+
+func TestAsymmetricTokens(t *testing.T) {
+	for _, algorithm := range []string{"RS256", "EdDSA"} {
+		t.Run(algorithm, func(t *testing.T) {
+			private, public, err := GenerateJWTKeyPair(algorithm)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			keys, err := ParseJWTKeys("k1=pem:"+writeTestKey(t, "k1.pem", private)+",h1="+testSecretA, "k1", "")
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+			SetJWTKeys(keys)
+
+			token, err := GenerateToken("maria@example.com", 7, 3, time.Hour)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+
+			parsed, _, _ := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+			if parsed.Method.Alg() != algorithm {
+				t.Errorf("Algoritmo esperado: %s, recebido: %s", algorithm, parsed.Method.Alg())
+			}
+
+			if claims, err := VerifyToken(token); err != nil || claims.UserID != 7 || claims.SessionID != 3 {
+				t.Errorf("Usuário 7 na sessão 3 esperado, recebido: %+v (erro: %v)", claims, err)
+			}
+
+			jwks := JWKS()
+			if len(jwks.Keys) != 1 || jwks.Keys[0].KeyID != "k1" || jwks.Keys[0].Algorithm != algorithm {
+				t.Errorf("Somente a chave pública k1 era esperada, recebido: %+v", jwks.Keys)
+			}
+
+			// A service holding only the public key verifies the token.
+			keys, err = ParseJWTKeys("k1=pem:"+writeTestKey(t, "k1.pub.pem", public)+",h1="+testSecretA, "h1", "")
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+			SetJWTKeys(keys)
+
+			if claims, err := VerifyToken(token); err != nil || claims.UserID != 7 || claims.SessionID != 3 {
+				t.Errorf("Usuário 7 na sessão 3 esperado, recebido: %+v (erro: %v)", claims, err)
+			}
+		})
+	}
+}
+
+func TestPublicKeyCannotSign(t *testing.T) {
+	_, public, err := GenerateJWTKeyPair("EdDSA")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if _, err := ParseJWTKeys("k1=pem:"+writeTestKey(t, "k1.pub.pem", public), "", ""); !errors.Is(err, ErrJWTActiveKeyPublic) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrJWTActiveKeyPublic, err)
+	}
+
+	if _, err := ParseJWTPEM("k1", []byte("not a key")); !errors.Is(err, ErrJWTPEMInvalid) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrJWTPEMInvalid, err)
+	}
+
+	if _, _, err := GenerateJWTKeyPair("HS256"); !errors.Is(err, ErrJWTAlgorithmInvalid) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrJWTAlgorithmInvalid, err)
+	}
+}
+
+func TestVerifyTokenRejectsAlgorithmConfusion(t *testing.T) {
+	private, public, err := GenerateJWTKeyPair("RS256")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	keys, err := ParseJWTKeys("k1=pem:"+writeTestKey(t, "k1.pem", private), "", "")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	SetJWTKeys(keys)
+
+	// An HS256 token signed with the published public key as the secret.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"userID": 1, "exp": time.Now().Add(time.Hour).Unix()})
+	forged.Header["kid"] = "k1"
+	token, _ := forged.SignedString(public)
+
+	if _, err := VerifyToken(token); !errors.Is(err, ErrCouldNotParseToken) {
+		t.Errorf("Erro esperado: %v, recebido: %v", ErrCouldNotParseToken, err)
+	}
+}
@@ -3,53 +3,233 @@ package utils
 import (
     "log"
     "errors"
+    "fmt"
+    "os"
+    "strings"
+    "sync"
     "time"
     "github.com/golang-jwt/jwt/v5"
     "github.com/amarantec/move-easy/internal"
 )
 
-const secret = "secret"
+const MIN_JWT_SECRET_LENGTH = 32
 
-func GenerateToken(email string, userID int64) (string, error) {
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+// JWTKey is either a shared HMAC secret or an asymmetric key pair. A key
+// loaded from a public key PEM has no SignKey and only verifies tokens.
+type JWTKey struct {
+    ID          string
+    Method      jwt.SigningMethod
+    SignKey     interface{}
+    VerifyKey   interface{}
+    RetiredAt   *time.Time
+}
+
+// JWTKeySet holds every key tokens may be signed with. New tokens are signed
+// with the active key only, the others keep verifying the tokens they signed
+// until their RetiredAt, which should be at least one token lifetime after
+// the rotation so nobody is logged out by it.
+type JWTKeySet struct {
+    ActiveID    string
+    Keys        map[string]JWTKey
+}
+
+type TokenClaims struct {
+    UserID      int64
+    SessionID   int64
+}
+
+var (
+    jwtKeysMu   sync.RWMutex
+    jwtKeys     JWTKeySet
+)
+
+// LoadJWTKeys reads the signing keys from the environment:
+//
+//  JWT_KEYS          comma separated kid=secret pairs, where secret may be
+//                    pem:<path> to an RSA or Ed25519 key in PEM
+//  JWT_ACTIVE_KID    kid used to sign new tokens, optional with a single key
+//  JWT_RETIRED_KIDS  comma separated kid@time pairs, time in RFC 3339, after
+//                    which tokens signed with kid are rejected
+func LoadJWTKeys() error {
+    keys, err := ParseJWTKeys(os.Getenv("JWT_KEYS"), os.Getenv("JWT_ACTIVE_KID"), os.Getenv("JWT_RETIRED_KIDS"))
+    if err != nil {
+        return err
+    }
+
+    SetJWTKeys(keys)
+    return nil
+}
+
+func ParseJWTKeys(keys, activeID, retired string) (JWTKeySet, error) {
+    set := JWTKeySet{ActiveID: strings.TrimSpace(activeID), Keys: map[string]JWTKey{}}
+
+    for _, pair := range strings.Split(keys, ",") {
+        if strings.TrimSpace(pair) == internal.EMPTY {
+            continue
+        }
+
+        id, secret, found := strings.Cut(strings.TrimSpace(pair), "=")
+        if !found || id == internal.EMPTY {
+            return JWTKeySet{}, fmt.Errorf("%w: %q", ErrJWTKeyInvalid, pair)
+        }
+
+        if _, duplicated := set.Keys[id]; duplicated {
+            return JWTKeySet{}, fmt.Errorf("%w: %s", ErrJWTKeyDuplicated, id)
+        }
+
+        key := JWTKey{ID: id, Method: jwt.SigningMethodHS256, SignKey: []byte(secret), VerifyKey: []byte(secret)}
+        if path, isPEM := strings.CutPrefix(secret, JWT_PEM_PREFIX); isPEM {
+            data, err := os.ReadFile(path)
+            if err != nil {
+                return JWTKeySet{}, fmt.Errorf("%s: %w", id, err)
+            }
+
+            if key, err = ParseJWTPEM(id, data); err != nil {
+                return JWTKeySet{}, fmt.Errorf("%s: %w", id, err)
+            }
+        } else if len(secret) < MIN_JWT_SECRET_LENGTH {
+            return JWTKeySet{}, fmt.Errorf("%w: %s", ErrJWTSecretTooShort, id)
+        }
+
+        set.Keys[id] = key
+    }
+
+    if len(set.Keys) == internal.ZERO {
+        return JWTKeySet{}, ErrJWTKeysMissing
+    }
+
+    if set.ActiveID == internal.EMPTY {
+        if len(set.Keys) > 1 {
+            return JWTKeySet{}, ErrJWTActiveKeyMissing
+        }
+        for id := range set.Keys {
+            set.ActiveID = id
+        }
+    }
+
+    if active, ok := set.Keys[set.ActiveID]; !ok {
+        return JWTKeySet{}, fmt.Errorf("%w: %s", ErrJWTActiveKeyMissing, set.ActiveID)
+    } else if active.SignKey == nil {
+        return JWTKeySet{}, fmt.Errorf("%w: %s", ErrJWTActiveKeyPublic, set.ActiveID)
+    }
+
+    for _, pair := range strings.Split(retired, ",") {
+        if strings.TrimSpace(pair) == internal.EMPTY {
+            continue
+        }
+
+        id, at, found := strings.Cut(strings.TrimSpace(pair), "@")
+        retiredAt, err := time.Parse(time.RFC3339, at)
+        if !found || err != nil {
+            return JWTKeySet{}, fmt.Errorf("%w: %q", ErrJWTRetirementInvalid, pair)
+        }
+
+        key, ok := set.Keys[id]
+        if !ok || id == set.ActiveID {
+            return JWTKeySet{}, fmt.Errorf("%w: %q", ErrJWTRetirementInvalid, pair)
+        }
+
+        key.RetiredAt = &retiredAt
+        set.Keys[id] = key
+    }
+
+    return set, nil
+}
+
+func SetJWTKeys(keys JWTKeySet) {
+    jwtKeysMu.Lock()
+    defer jwtKeysMu.Unlock()
+
+    jwtKeys = keys
+}
+
+func currentJWTKeys() JWTKeySet {
+    jwtKeysMu.RLock()
+    defer jwtKeysMu.RUnlock()
+
+    return jwtKeys
+}
+
+// GenerateToken signs an access token for the session, valid for ttl.
+func GenerateToken(email string, userID, sessionID int64, ttl time.Duration) (string, error) {
+    keys := currentJWTKeys()
+    key, ok := keys.Keys[keys.ActiveID]
+    if !ok {
+        return internal.EMPTY, ErrJWTKeysMissing
+    }
+
+    token := jwt.NewWithClaims(key.Method, jwt.MapClaims{
         "email": email,
         "userID": userID,
-        "exp": time.Now().Add(time.Hour * 24).Unix(),
+        "sid": sessionID,
+        "iat": time.Now().Unix(),
+        "exp": time.Now().Add(ttl).Unix(),
     })
+    token.Header["kid"] = key.ID
 
-    return token.SignedString([]byte(secret))
+    return token.SignedString(key.SignKey)
 }
 
-func VerifyToken(token string) (int64, error) {
+func VerifyToken(token string) (TokenClaims, error) {
+    keys := currentJWTKeys()
     parsedToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
-        _, ok := t.Method.(*jwt.SigningMethodHMAC)
+        id, _ := t.Header["kid"].(string)
+        key, ok := keys.Keys[id]
         if !ok {
+            return internal.ZERO, ErrUnknownKeyID
+        }
+
+        // Each key verifies only its own algorithm, so a public key can never
+        // be used as an HMAC secret.
+        if t.Method.Alg() != key.Method.Alg() {
             return internal.ZERO, ErrUnexpectedSigningMethod
         }
 
-        return []byte(secret), nil
+        if key.RetiredAt != nil && time.Now().After(*key.RetiredAt) {
+            return internal.ZERO, ErrRetiredKeyID
+        }
+
+        return key.VerifyKey, nil
     })
 
     if err != nil {
-        log.Print("Could not parse this token.\n")
-        return internal.ZERO, ErrCouldNotParseToken
+        log.Printf("Could not parse this token: %v\n", err)
+        return TokenClaims{}, ErrCouldNotParseToken
     }
 
     tokenIsValid := parsedToken.Valid
     if !tokenIsValid {
-        return internal.ZERO, ErrInvalidToken
+        return TokenClaims{}, ErrInvalidToken
     }
 
     claims, ok := parsedToken.Claims.(jwt.MapClaims)
     if !ok {
-        return internal.ZERO, ErrInvalidTokenClaims
+        return TokenClaims{}, ErrInvalidTokenClaims
+    }
+
+    userID, ok := claims["userID"].(float64)
+    if !ok {
+        return TokenClaims{}, ErrInvalidTokenClaims
+    }
+
+    sessionID, ok := claims["sid"].(float64)
+    if !ok {
+        return TokenClaims{}, ErrInvalidTokenClaims
     }
 
-    userID := int64(claims["userID"].(float64))
-    return userID, nil
+    return TokenClaims{UserID: int64(userID), SessionID: int64(sessionID)}, nil
 }
 
 var ErrUnexpectedSigningMethod = errors.New("Unexpected signing method")
 var ErrCouldNotParseToken = errors.New("Could not parse token")
 var ErrInvalidToken = errors.New("Invalid Token")
 var ErrInvalidTokenClaims = errors.New("Invalid token claims")
+var ErrUnknownKeyID = errors.New("Token signed with an unknown key")
+var ErrRetiredKeyID = errors.New("Token signed with a retired key")
+var ErrJWTKeysMissing = errors.New("JWT_KEYS must have at least one kid=secret pair")
+var ErrJWTKeyInvalid = errors.New("JWT key must be a kid=secret pair")
+var ErrJWTKeyDuplicated = errors.New("JWT key id is duplicated")
+var ErrJWTSecretTooShort = errors.New("JWT secret must have at least 32 bytes")
+var ErrJWTActiveKeyMissing = errors.New("JWT_ACTIVE_KID must name one of JWT_KEYS")
+var ErrJWTActiveKeyPublic = errors.New("JWT active key must have a private key to sign with")
+var ErrJWTRetirementInvalid = errors.New("JWT retired key must be a kid@RFC3339 pair naming an inactive key")
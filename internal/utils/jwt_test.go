@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testSecretA = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	testSecretB = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+func TestParseJWTKeys(t *testing.T) {
+	tests := []struct {
+		name      string
+		keys      string
+		active    string
+		retired   string
+		wantError error
+	}{
+		{name: "Chave única sem kid ativo", keys: "k1=" + testSecretA},
+		{name: "Rotação com chave aposentada", keys: "k1=" + testSecretA + ",k2=" + testSecretB, active: "k2", retired: "k1@2030-01-01T00:00:00Z"},
+		{name: "Sem chaves", keys: "", wantError: ErrJWTKeysMissing},
+		{name: "Segredo curto", keys: "k1=secret", wantError: ErrJWTSecretTooShort},
+		{name: "Par inválido", keys: testSecretA, wantError: ErrJWTKeyInvalid},
+		{name: "Kid duplicado", keys: "k1=" + testSecretA + ",k1=" + testSecretB, active: "k1", wantError: ErrJWTKeyDuplicated},
+		{name: "Várias chaves sem kid ativo", keys: "k1=" + testSecretA + ",k2=" + testSecretB, wantError: ErrJWTActiveKeyMissing},
+		{name: "Kid ativo desconhecido", keys: "k1=" + testSecretA, active: "k9", wantError: ErrJWTActiveKeyMissing},
+		{name: "Chave ativa aposentada", keys: "k1=" + testSecretA, retired: "k1@2030-01-01T00:00:00Z", wantError: ErrJWTRetirementInvalid},
+		{name: "Data de aposentadoria inválida", keys: "k1=" + testSecretA + ",k2=" + testSecretB, active: "k2", retired: "k1@amanhã", wantError: ErrJWTRetirementInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseJWTKeys(tt.keys, tt.active, tt.retired); !errors.Is(err, tt.wantError) {
+				t.Errorf("[%s] Erro esperado: %v, recebido: %v", tt.name, tt.wantError, err)
+			}
+		})
+	}
+}
+
+func TestTokenKeyRotation(t *testing.T) {
+	keys, err := ParseJWTKeys("k1="+testSecretA, "", "")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	SetJWTKeys(keys)
+
+	oldToken, err := GenerateToken("maria@example.com", 7, 3, time.Hour)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	gracePeriod := time.Now().Add(time.Hour).Format(time.RFC3339)
+	keys, err = ParseJWTKeys("k1="+testSecretA+",k2="+testSecretB, "k2", "k1@"+gracePeriod)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	SetJWTKeys(keys)
+
+	newToken, err := GenerateToken("maria@example.com", 7, 3, time.Hour)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, jwt.MapClaims{})
+	if err != nil || parsed.Header["kid"] != "k2" {
+		t.Errorf("Kid esperado: k2, recebido: %v (erro: %v)", parsed.Header["kid"], err)
+	}
+
+	for _, token := range []string{oldToken, newToken} {
+		if claims, err := VerifyToken(token); err != nil || claims.UserID != 7 || claims.SessionID != 3 {
+			t.Errorf("Usuário 7 na sessão 3 esperado, recebido: %+v (erro: %v)", claims, err)
+		}
+	}
+
+	retiredAt := time.Now().Add(-time.Minute).Format(time.RFC3339)
+	keys, err = ParseJWTKeys("k1="+testSecretA+",k2="+testSecretB, "k2", "k1@"+retiredAt)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	SetJWTKeys(keys)
+
+	if _, err := VerifyToken(oldToken); !errors.Is(err, ErrCouldNotParseToken) {
+		t.Errorf("Erro esperado para chave aposentada: %v, recebido: %v", ErrCouldNotParseToken, err)
+	}
+
+	if claims, err := VerifyToken(newToken); err != nil || claims.UserID != 7 || claims.SessionID != 3 {
+		t.Errorf("Usuário 7 na sessão 3 esperado, recebido: %+v (erro: %v)", claims, err)
+	}
+}
+
+func TestVerifyTokenRejectsForgedTokens(t *testing.T) {
+	keys, err := ParseJWTKeys("k1="+testSecretA, "", "")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	SetJWTKeys(keys)
+
+	claims := jwt.MapClaims{"userID": 1, "sid": 1, "exp": time.Now().Add(time.Hour).Unix()}
+	noKid, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+
+	unknown := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	unknown.Header["kid"] = "k9"
+	unknownKid, _ := unknown.SignedString([]byte(testSecretA))
+
+	wrong := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	wrong.Header["kid"] = "k1"
+	wrongSecret, _ := wrong.SignedString([]byte(strings.Repeat("c", 32)))
+
+	for name, token := range map[string]string{"Sem kid": noKid, "Kid desconhecido": unknownKid, "Segredo errado": wrongSecret} {
+		if _, err := VerifyToken(token); !errors.Is(err, ErrCouldNotParseToken) {
+			t.Errorf("[%s] Erro esperado: %v, recebido: %v", name, ErrCouldNotParseToken, err)
+		}
+	}
+
+	withoutSession := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"userID": 1, "exp": time.Now().Add(time.Hour).Unix()})
+	withoutSession.Header["kid"] = "k1"
+	noSession, _ := withoutSession.SignedString([]byte(testSecretA))
+	if _, err := VerifyToken(noSession); !errors.Is(err, ErrInvalidTokenClaims) {
+		t.Errorf("[Sem sessão] Erro esperado: %v, recebido: %v", ErrInvalidTokenClaims, err)
+	}
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// PERIODIC_RUN_TIMEOUT bounds each call made by RunPeriodically.
+const PERIODIC_RUN_TIMEOUT = 30 * time.Second
+
+// RunPeriodically calls run every interval and logs the error it returns.
+// It blocks until ctx is done.
+func RunPeriodically(ctx context.Context, interval time.Duration, run func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ctxTimeout, cancel := context.WithTimeout(ctx, PERIODIC_RUN_TIMEOUT)
+			if err := run(ctxTimeout); err != nil {
+				log.Printf("%v\n", err)
+			}
+			cancel()
+		}
+	}
+}
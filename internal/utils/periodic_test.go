@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunPeriodically(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	done := make(chan struct{})
+
+	go func() {
+		RunPeriodically(ctx, 5*time.Millisecond, func(ctx context.Context) error {
+			calls++
+			if calls == 3 {
+				cancel()
+			}
+			// Um erro não interrompe as próximas execuções.
+			return errors.New("falha")
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunPeriodically deveria parar quando o contexto termina")
+	}
+
+	if calls != 3 {
+		t.Errorf("Execuções esperadas: 3, recebidas: %d", calls)
+	}
+}
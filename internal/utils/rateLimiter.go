@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"sync"
+	"time"
+
+	"github.com/amarantec/move-easy/internal"
+)
+
+// RATE_LIMITER_SWEEP is how many keys a RateLimiter keeps before it drops the
+// ones with no hit left in the window.
+const RATE_LIMITER_SWEEP = 1024
+
+// RateLimiter allows up to limit hits per key within a sliding window. It
+// lives in memory, so each API instance counts on its own.
+type RateLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	hits   map[string][]time.Time
+}
+
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{limit: limit, window: window, hits: map[string][]time.Time{}}
+}
+
+// Allow records a hit for key and reports whether it is within the limit.
+// Hits over the limit are not recorded.
+func (l *RateLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if len(l.hits) >= RATE_LIMITER_SWEEP {
+		for k := range l.hits {
+			if l.recent(k, now) == internal.ZERO {
+				delete(l.hits, k)
+			}
+		}
+	}
+
+	if l.recent(key, now) >= l.limit {
+		return false
+	}
+	l.hits[key] = append(l.hits[key], now)
+	return true
+}
+
+// Release drops the last hit of key, for attempts that turned out to cost
+// nothing and should not count.
+func (l *RateLimiter) Release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if hits := l.hits[key]; len(hits) > internal.ZERO {
+		l.hits[key] = hits[:len(hits)-1]
+	}
+}
+
+func (l *RateLimiter) recent(key string, now time.Time) int {
+	hits := l.hits[key]
+	for len(hits) > internal.ZERO && now.Sub(hits[0]) >= l.window {
+		hits = hits[1:]
+	}
+	l.hits[key] = hits
+	return len(hits)
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(2, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if !limiter.Allow("maria") {
+			t.Fatalf("Tentativa %d deveria ser permitida", i+1)
+		}
+	}
+
+	if limiter.Allow("maria") {
+		t.Errorf("Terceira tentativa deveria ser bloqueada")
+	}
+
+	if !limiter.Allow("joao") {
+		t.Errorf("Cada chave deveria ter seu próprio limite")
+	}
+
+	limiter.Release("maria")
+	if !limiter.Allow("maria") {
+		t.Errorf("Tentativa liberada não deveria contar")
+	}
+
+	short := NewRateLimiter(1, 10*time.Millisecond)
+	short.Allow("maria")
+	time.Sleep(20 * time.Millisecond)
+	if !short.Allow("maria") {
+		t.Errorf("Tentativa fora da janela deveria ser permitida")
+	}
+}
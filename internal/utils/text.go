@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "ê", "e", "è", "e",
+	"í", "i", "î", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ü", "u",
+	"ç", "c",
+)
+
+// FoldText lowercases the text and drops the accents used in Portuguese, so
+// names typed with and without them compare equal.
+func FoldText(text string) string {
+	return accents.Replace(strings.ToLower(strings.TrimSpace(text)))
+}
+
+// NormalizeCEP keeps only the digits of a CEP, so "95520-000" and "95520000"
+// are the same one.
+func NormalizeCEP(cep string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, cep)
+}
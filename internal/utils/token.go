@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const OPAQUE_TOKEN_BYTES = 32
+
+// GenerateOpaqueToken returns a random token to hand to the client and the
+// hash to store in its place, so a leaked table cannot be replayed.
+func GenerateOpaqueToken() (string, string, error) {
+	buffer := make([]byte, OPAQUE_TOKEN_BYTES)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buffer)
+	return token, HashToken(token), nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
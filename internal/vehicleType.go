@@ -6,3 +6,7 @@ const (
 	BICYCLE VehicleType = iota
 	SCOOTER
 )
+
+func (t VehicleType) IsValid() bool {
+	return t >= BICYCLE && t <= SCOOTER
+}
@@ -6,3 +6,7 @@ const (
 	UPVOTE VoteType = iota
 	DOWNVOTE
 )
+
+func (t VoteType) IsValid() bool {
+	return t == UPVOTE || t == DOWNVOTE
+}